@@ -16,75 +16,356 @@ package cmd
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	workloads "github.com/fairwindsops/insights-plugins/plugins/workloads"
-	workloadsPkg "github.com/fairwindsops/insights-plugins/plugins/workloads/pkg"
 
 	"github.com/fairwindsops/polaris/pkg/auth"
 	cfg "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/events"
+	"github.com/fairwindsops/polaris/pkg/gitops"
 	"github.com/fairwindsops/polaris/pkg/insights"
 	"github.com/fairwindsops/polaris/pkg/kube"
+	"github.com/fairwindsops/polaris/pkg/results"
+	"github.com/fairwindsops/polaris/pkg/retry"
+	"github.com/fairwindsops/polaris/pkg/signing"
 	"github.com/fairwindsops/polaris/pkg/validator"
+	"github.com/fairwindsops/polaris/pkg/vault"
+	"github.com/fairwindsops/polaris/pkg/wasm"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/thoas/go-funk"
+	"golang.org/x/term"
+	yamlv3 "gopkg.in/yaml.v3"
 	"sigs.k8s.io/yaml"
 )
 
 var (
-	setExitCode         bool
-	onlyShowFailedTests bool
-	minScore            int
-	auditOutputURL      string
-	auditOutputFile     string
-	auditOutputFormat   string
-	resourceToAudit     string
-	useColor            bool
-	helmChart           string
-	helmValues          string
-	checks              []string
-	auditNamespace      string
-	skipSslValidation   bool
-	uploadInsights      bool
-	clusterName         string
+	setExitCode          bool
+	onlyShowFailedTests  bool
+	minScore             int
+	dangerExitCode       int
+	scoreExitCode        int
+	warningExitCode      int
+	auditOutputURL       string
+	auditOutputFile      string
+	auditOutputFormat    string
+	outputIndent         int
+	outputIndentTabs     bool
+	resourceToAudit      string
+	resourceName         string
+	resourceKind         string
+	useColor             bool
+	reportBranding       validator.ReportBranding
+	groupIdentical       bool
+	helmChart            string
+	helmChartVersion     string
+	helmReleaseName      string
+	targetK8sVersion     string
+	helmValues           string
+	helmSetValues        []string
+	helmKubeVersion      string
+	helmAPIVersions      []string
+	helmTimeout          time.Duration
+	helmValuesFromVault  string
+	helmSecrets          []string
+	gitopsRepo           string
+	kustomize            bool
+	kustomizeEnableHelm  bool
+	gitopsApps           []string
+	verboseResults       bool
+	auditDryRun          bool
+	gateName             string
+	exitCodeStrategy     string
+	exitReasonFile       string
+	minPriority          string
+	minSeverity          string
+	scoreGranularity     string
+	wasmChecksDir        string
+	checksFromInsights   bool
+	wasmCheckTimeout     time.Duration
+	outputSQLite         string
+	gate                 cfg.Gate
+	checks               []string
+	skipChecks           []string
+	auditNamespace       string
+	defaultNamespace     string
+	excludePaths         []string
+	resourceKinds        []string
+	skipSslValidation    bool
+	uploadInsights       bool
+	uploadInsightsDryRun bool
+	insightsNamespaces   insights.NamespaceFilter
+	insightsRetries      int
+	insightsTimeout      time.Duration
+	concurrency          int
+	parallelism          int
+	gitRepo              string
+	gitRef               string
+	gitPath              string
+	storeResults         bool
+	emitEvents           bool
+	maxEvents            int
+	streamOutputURL      bool
+	compressOutputURL    bool
+	signResults          bool
+	signingKeyFile       string
+	signatureFile        string
+	signingKey           ed25519.PrivateKey
+	resultsNamespace     string
+	resultsRetention     int
+	clusterName          string
+	anonymize            bool
+	anonymizeMapFile     string
+	checkPaths           []string
+	checkSeverities      []string
+	transformExec        string
+	baselineFile         string
+	diffAgainst          string
+	auditDelta           *validator.AuditDelta
+	auditedLocalFiles    bool
+	auditPaths           []string
+	baselineFromCluster  string
+	maxRegression        int
+	metadataFlags        []string
+	metadataFile         string
+	auditMetadata        map[string]interface{}
+	framework            string
+	complianceFramework  cfg.Framework
 )
 
+// Values of --exit-code-strategy
+const (
+	exitCodeStrategyFirst = "first"
+	exitCodeStrategyMax   = "max"
+)
+
+// helmOCIPrefix marks a --helm-chart stored in an OCI registry, which is pulled before it's templated
+const helmOCIPrefix = "oci://"
+
+// defaultOutputIndent is the number of spaces json and yaml output are indented with
+const defaultOutputIndent = 2
+
+// auditFormats are the values accepted by --format
+var auditFormats = []string{"json", "yaml", "pretty", "score", "status", "ndjson", "team-summary", "team-summary-json", "app-summary", "app-summary-json", "score-by-namespace", "score-by-namespace-json", "compliance", "compliance-json", "failed-checks", "runbook", "runbook-json", "sarif", "junit", "heatmap", "heatmap-json", "prometheus", "csv", "html"}
+
 func init() {
 	rootCmd.AddCommand(auditCmd)
-	auditCmd.PersistentFlags().StringVar(&auditPath, "audit-path", "", "If specified, audits one or more YAML files instead of a cluster.")
-	auditCmd.PersistentFlags().BoolVar(&setExitCode, "set-exit-code-on-danger", false, "Set an exit code of 3 when the audit contains danger-level issues.")
+	auditCmd.PersistentFlags().StringArrayVar(&auditPaths, "audit-path", []string{}, "If specified, audits one or more YAML files instead of a cluster. Use - to read YAML from stdin, a .tar or .tar.gz archive of YAML files, git::<url>//<path>?ref=<ref> to audit a remote Git repository, or oci://<registry>/<repository>:<tag> to audit the YAML layers of an OCI artifact. Can be repeated to audit several paths together, except for Git repositories.")
+	auditCmd.PersistentFlags().BoolVar(&setExitCode, "set-exit-code-on-danger", false, "Set an exit code of 3, or --danger-exit-code, when the audit contains danger-level issues.")
+	auditCmd.PersistentFlags().IntVar(&dangerExitCode, "danger-exit-code", validator.GateExitCodeDangers, "Exit code set by --set-exit-code-on-danger (1-255).")
+	auditCmd.PersistentFlags().IntVar(&warningExitCode, "exit-code-on-warning", 0, "Set this exit code when the audit contains warning-level issues (1-255). 0 disables it.")
+	auditCmd.PersistentFlags().BoolVar(&auditDryRun, "dry-run", false, "Validate the config and flags, and check that the cluster and Fairwinds Insights are reachable, then exit without running the audit.")
+	auditCmd.PersistentFlags().BoolVar(&verboseResults, "verbose-results", false, "Include what was checked and the observed values in passing results, e.g. to debug custom checks. Increases the size of json and pretty output.")
 	auditCmd.PersistentFlags().BoolVar(&onlyShowFailedTests, "only-show-failed-tests", false, "If specified, audit output will only show failed tests.")
-	auditCmd.PersistentFlags().IntVar(&minScore, "set-exit-code-below-score", 0, "Set an exit code of 4 when the score is below this threshold (1-100).")
+	auditCmd.PersistentFlags().StringVar(&minSeverity, "min-severity", "", "Only show checks with at least this severity in the output, without changing the exit code. One of danger or warning.")
+	auditCmd.PersistentFlags().StringVar(&minPriority, "min-priority", "", "Only show failures with at least this priority in the output, without changing the score or exit code. One of must-fix, neutral, or nice-to-have.")
+	auditCmd.PersistentFlags().StringVar(&exitReasonFile, "exit-reason-file", "", "Destination file for a JSON description of why the audit exited with its exit code, e.g. dangers or score, along with the score and counts.")
+	auditCmd.PersistentFlags().StringVar(&exitCodeStrategy, "exit-code-strategy", exitCodeStrategyFirst, "How the exit code is chosen when several thresholds fail - first exits with the first one checked, max checks all of them, logs each, and exits with the highest code.")
+	auditCmd.PersistentFlags().StringVar(&gateName, "gate", "", "Name of a gate from the config whose thresholds set the exit code, e.g. release.")
+	auditCmd.PersistentFlags().StringVar(&scoreGranularity, "score-granularity", string(validator.ScoreGranularityContainer), "How container results count toward the score - container counts every container, pod counts each container check once per pod.")
+	auditCmd.PersistentFlags().IntVar(&minScore, "set-exit-code-below-score", 0, "Set an exit code of 4, or --score-exit-code, when the score is below this threshold (1-100).")
+	auditCmd.PersistentFlags().IntVar(&scoreExitCode, "score-exit-code", validator.GateExitCodeScore, "Exit code set by --set-exit-code-below-score (1-255).")
+	auditCmd.PersistentFlags().StringVar(&reportBranding.Title, "report-title", "", "Title at the top of pretty and html output, replacing the Polaris audit header, e.g. to white-label shared reports.")
+	auditCmd.PersistentFlags().StringVar(&reportBranding.Footer, "report-footer", "", "Text added at the end of pretty and html output.")
+	auditCmd.PersistentFlags().BoolVar(&groupIdentical, "group-identical", false, "Collapse resources of the same kind which fail the same checks into one entry in pretty output, listing their names.")
+	auditCmd.PersistentFlags().IntVar(&outputIndent, "indent", defaultOutputIndent, "Number of spaces to indent json and yaml output with, or tabs with --indent-tabs (1-9).")
+	auditCmd.PersistentFlags().BoolVar(&outputIndentTabs, "indent-tabs", false, "Indent json output with tabs instead of spaces, one per level unless --indent is set. YAML can't be indented with tabs.")
 	auditCmd.PersistentFlags().StringVar(&auditOutputURL, "output-url", "", "Destination URL to send audit results.")
+	auditCmd.PersistentFlags().BoolVar(&compressOutputURL, "output-compress", false, "Compress json and yaml results sent to --output-url with gzip, setting Content-Encoding: gzip.")
+	auditCmd.PersistentFlags().BoolVar(&streamOutputURL, "output-url-stream", false, "Stream results to --output-url using chunked transfer encoding instead of buffering the whole payload. Requires the ndjson format, other formats are sent buffered.")
 	auditCmd.PersistentFlags().StringVar(&auditOutputFile, "output-file", "", "Destination file for audit results.")
-	auditCmd.PersistentFlags().StringVarP(&auditOutputFormat, "format", "f", "json", "Output format for results - json, yaml, pretty, or score.")
+	auditCmd.PersistentFlags().StringVar(&outputSQLite, "output-sqlite", "", "SQLite database to append the audit's runs, resources, and findings to. Requires a build with the sqlite tag.")
+	auditCmd.PersistentFlags().StringVarP(&auditOutputFormat, "format", "f", "json", "Output format for results - json, yaml, pretty, score, status, ndjson, team-summary, team-summary-json, app-summary, app-summary-json, score-by-namespace, score-by-namespace-json, compliance, compliance-json, failed-checks, runbook, runbook-json, sarif, junit, heatmap, heatmap-json, prometheus, csv, or html.")
+	auditCmd.PersistentFlags().StringVar(&framework, "framework", "cis", "Compliance framework used by the compliance formats - cis or nsa.")
 	auditCmd.PersistentFlags().BoolVar(&useColor, "color", true, "Whether to use color in pretty format.")
 	auditCmd.PersistentFlags().StringVar(&displayName, "display-name", "", "An optional identifier for the audit.")
+	auditCmd.PersistentFlags().StringArrayVar(&metadataFlags, "metadata", []string{}, "Metadata to attach to the audit, in the format key=value. Can be repeated.")
+	auditCmd.PersistentFlags().StringVar(&metadataFile, "metadata-file", "", "JSON file with an object of metadata to attach to the audit, e.g. CI build context. --metadata takes precedence.")
+	auditCmd.PersistentFlags().StringVar(&resourceName, "resource-name", "", "Audit the resource with this name, in the namespace set by --namespace or any namespace. The version is discovered from the cluster.")
+	auditCmd.PersistentFlags().StringVar(&resourceKind, "resource-kind", "", "Kind of the resource audited with --resource-name, e.g. Deployment or CronJob.batch. Defaults to searching the workload kinds and the kinds the checks need.")
 	auditCmd.PersistentFlags().StringVar(&resourceToAudit, "resource", "", "Audit a specific resource, in the format namespace/kind/version/name, e.g. nginx-ingress/Deployment.apps/v1/default-backend.")
+	auditCmd.PersistentFlags().StringVar(&gitRepo, "git-repo", "", "Clone this Git repository and audit its manifests instead of a cluster.")
+	auditCmd.PersistentFlags().StringVar(&gitRef, "git-ref", "", "Branch, tag, or commit of --git-repo to audit. Defaults to the default branch.")
+	auditCmd.PersistentFlags().StringVar(&gitPath, "git-path", "", "Directory within --git-repo to audit. Defaults to the root of the repository.")
 	auditCmd.PersistentFlags().StringVar(&helmChart, "helm-chart", "", "Will fill out Helm template")
+	auditCmd.PersistentFlags().StringVar(&helmChartVersion, "helm-chart-version", "", "Version of an oci:// --helm-chart to pull. Defaults to the latest version.")
+	auditCmd.PersistentFlags().StringVar(&targetK8sVersion, "target-k8s-version", "", "Kubernetes version being upgraded to, e.g. 1.25. Resources using APIs removed in it fail the deprecatedAPIVersion check, with the API to use instead.")
+	auditCmd.PersistentFlags().StringVar(&helmReleaseName, "helm-release-name", "", "Release name used when templating --helm-chart, e.g. for charts using .Release.Name in labels. Defaults to a generated name.")
 	auditCmd.PersistentFlags().StringVar(&helmValues, "helm-values", "", "Optional flag to add helm values")
+	auditCmd.PersistentFlags().StringArrayVar(&helmSetValues, "helm-set", []string{}, "Set a helm value when templating, in the format key=value, e.g. image.tag=1.2.3. Takes precedence over --helm-values. Can be repeated.")
+	auditCmd.PersistentFlags().StringVar(&helmValuesFromVault, "helm-values-from-vault", "", "Path of a Vault secret containing helm values, e.g. secret/data/myapp/values. Uses VAULT_ADDR and VAULT_TOKEN.")
+	auditCmd.PersistentFlags().StringVar(&helmKubeVersion, "helm-kube-version", "", "Kubernetes version used by helm when templating, e.g. 1.27.0. Defaults to helm's own default.")
+	auditCmd.PersistentFlags().StringSliceVar(&helmAPIVersions, "helm-api-versions", []string{}, "Kubernetes API versions used by helm for Capabilities.APIVersions when templating, e.g. monitoring.coreos.com/v1.")
+	auditCmd.PersistentFlags().BoolVar(&kustomize, "kustomize", false, "Render --audit-path with kustomize build before auditing it. Without it, a kustomization's files are audited as they are.")
+	auditCmd.PersistentFlags().BoolVar(&kustomizeEnableHelm, "enable-helm", false, "Pass --enable-helm to kustomize build, so helm charts referenced by kustomizations are inflated.")
+	auditCmd.PersistentFlags().DurationVar(&helmTimeout, "timeout", 5*time.Minute, "Maximum time each helm, kustomize, or git clone may run when rendering --helm-chart, --kustomize, or --gitops-repo or cloning a Git repository, after which it is killed. Set to 0 to disable.")
+	auditCmd.PersistentFlags().StringVar(&gitopsRepo, "gitops-repo", "", "Audit every Argo CD Application and Flux Kustomization in this repository, rendering each with helm or kustomize.")
+	auditCmd.PersistentFlags().StringVar(&wasmChecksDir, "wasm-checks", "", "Directory of custom checks implemented as WebAssembly modules, each named after its file.")
+	auditCmd.PersistentFlags().DurationVar(&wasmCheckTimeout, "wasm-check-timeout", time.Second, "Maximum time a WebAssembly check may run for each resource.")
 	auditCmd.PersistentFlags().StringSliceVar(&checks, "checks", []string{}, "Optional flag to specify specific checks to check")
-	auditCmd.PersistentFlags().StringVar(&auditNamespace, "namespace", "", "Namespace to audit. Only applies to in-cluster audits")
+	auditCmd.PersistentFlags().StringSliceVar(&skipChecks, "skip-checks", []string{}, "Checks to ignore while running all others at their configured severity. Takes precedence over --checks.")
+	auditCmd.PersistentFlags().StringVar(&auditNamespace, "namespace", "", "Namespace to audit. Only applies to in-cluster audits, and to --helm-chart audits, where it's the release namespace used when templating and the default namespace of resources.")
+	auditCmd.PersistentFlags().StringArrayVar(&excludePaths, "exclude", []string{}, "Skip files and directories in --audit-path matching this pattern, in the gitignore syntax of .polarisignore files, e.g. examples/. Can be repeated.")
+	auditCmd.PersistentFlags().StringVar(&defaultNamespace, "default-namespace", "", "Namespace for resources that don't specify one, like kubectl apply -n. Only applies to --audit-path, --helm-chart, and --git-repo audits")
+	auditCmd.PersistentFlags().StringSliceVar(&resourceKinds, "resource-kinds", []string{}, "Only discover and audit these kinds, e.g. Deployment,Ingress. Only applies to in-cluster audits")
 	auditCmd.PersistentFlags().BoolVar(&skipSslValidation, "skip-ssl-validation", false, "Skip https certificate verification")
+	auditCmd.PersistentFlags().BoolVar(&checksFromInsights, "checks-from-insights", false, "Audit with the organization's Polaris configuration from Fairwinds Insights. It's cached for an hour, and --config and --config-url take precedence.")
 	auditCmd.PersistentFlags().BoolVar(&uploadInsights, "upload-insights", false, "Upload scan results to Fairwinds Insights")
+	auditCmd.PersistentFlags().BoolVar(&signResults, "sign", false, "Write a detached ed25519 signature of the results next to --output-file.")
+	auditCmd.PersistentFlags().StringVar(&signingKeyFile, "signing-key", "", "PEM-encoded ed25519 private key used by --sign.")
+	auditCmd.PersistentFlags().StringVar(&signatureFile, "signature-file", "", "Destination file for the signature. Defaults to the output file with a .sig suffix.")
+	auditCmd.PersistentFlags().BoolVar(&storeResults, "store-results", false, "Store a summary of the audit in the cluster as an AuditResult resource.")
+	auditCmd.PersistentFlags().StringVar(&resultsNamespace, "results-namespace", results.DefaultNamespace, "Namespace where AuditResult resources are stored.")
+	auditCmd.PersistentFlags().IntVar(&resultsRetention, "results-retention", results.DefaultRetention, "Number of AuditResult resources kept by --store-results, deleting the oldest ones. Set to 0 to keep all of them.")
+	auditCmd.PersistentFlags().BoolVar(&emitEvents, "emit-events", false, "Record failing checks as Warning Events on the audited resources, so they show up in kubectl describe.")
+	auditCmd.PersistentFlags().IntVar(&maxEvents, "max-events", events.DefaultMaxEvents, "Maximum number of Events written by --emit-events per audit.")
+	auditCmd.PersistentFlags().IntVar(&parallelism, "parallelism", 0, "Number of resources to validate concurrently. Defaults to GOMAXPROCS.")
+	auditCmd.PersistentFlags().IntVar(&concurrency, "concurrency", insights.DefaultConcurrency, "Maximum number of concurrent requests made to the Kubernetes API when fetching workloads for --upload-insights.")
+	auditCmd.PersistentFlags().StringSliceVar(&insightsNamespaces.Include, "include-namespaces", []string{}, "Only upload these namespaces to Fairwinds Insights with --upload-insights, so Insights only displays their workloads and results. Cluster-scoped resources are always uploaded.")
+	auditCmd.PersistentFlags().StringSliceVar(&insightsNamespaces.Exclude, "exclude-namespaces", []string{}, "Namespaces not to upload to Fairwinds Insights with --upload-insights. Takes precedence over --include-namespaces.")
+	auditCmd.PersistentFlags().IntVar(&insightsRetries, "insights-retries", 3, "Number of times requests to Fairwinds Insights which are safe to repeat, e.g. not report uploads, are retried after a network error or a 5xx response, with exponential backoff. Requests rejected with a 4xx response aren't retried.")
+	auditCmd.PersistentFlags().DurationVar(&insightsTimeout, "insights-timeout", 0, "Timeout for each attempt of a request to Fairwinds Insights. Defaults to --http-timeout.")
+	auditCmd.PersistentFlags().BoolVar(&uploadInsightsDryRun, "upload-insights-dry-run", false, "Authenticate and build the Fairwinds Insights reports, then print them (or write them to --output-file) instead of uploading.")
 	auditCmd.PersistentFlags().StringVar(&clusterName, "cluster-name", "", "Set --cluster-name to a descriptive name for the cluster you're auditing")
+	auditCmd.PersistentFlags().BoolVar(&anonymize, "anonymize", false, "Replace cluster, namespace, app, and file identifiers with pseudonyms in the output. Set POLARIS_ANONYMIZE_KEY to keep them the same across runs.")
+	auditCmd.PersistentFlags().StringVar(&anonymizeMapFile, "anonymize-map", "", "Destination file for the mapping of pseudonyms to original identifiers. Requires --anonymize.")
+	auditCmd.PersistentFlags().StringArrayVar(&checkSeverities, "set-check-severity", []string{}, "Override the severity of a check for this run, in the format checkID=danger. Can be repeated.")
+	auditCmd.PersistentFlags().StringArrayVar(&checkPaths, "check-path", []string{}, "Evaluate a check against a different field path of the resource, in the format checkID=some.json.path. Can be repeated.")
+	auditCmd.PersistentFlags().StringVar(&transformExec, "transform-exec", "", "Command that receives the audit as JSON on stdin and prints the transformed audit JSON on stdout.")
+	auditCmd.PersistentFlags().StringVar(&baselineFile, "baseline", "", "Baseline file of known failures to suppress, so only new issues are reported.")
+	auditCmd.PersistentFlags().StringVar(&baselineFromCluster, "baseline-from-cluster", "", "Audit the cluster and write all current failures to this baseline file.")
+	auditCmd.PersistentFlags().StringVar(&diffAgainst, "diff-against", "", "Earlier audit saved as JSON or YAML to compare against. Only the checks which newly fail or pass and the score change are output, and new danger-level failures set an exit code of 7.")
+	auditCmd.PersistentFlags().IntVar(&maxRegression, "max-score-regression-per-namespace", -1, "Set an exit code of 6 when any namespace's score is more than this many points below its score in --baseline.")
 }
 
 var auditCmd = &cobra.Command{
 	Use:   "audit",
 	Short: "Runs a one-time audit.",
 	Long:  `Runs a one-time audit.`,
+	// the exit reason is written before the config is parsed, so a fatal exit doesn't leave an earlier
+	// run's reason behind. It's replaced once the audit completes.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if exitReasonFile != "" {
+			err := writeErrorExitReason(exitReasonFile)
+			if err != nil {
+				logrus.Errorf("Error writing exit reason: %v", err)
+				os.Exit(1)
+			}
+		}
+		rootCmd.PersistentPreRun(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(auditPaths) > 0 {
+			auditPath = auditPaths[0]
+		}
+		if len(auditPaths) > 1 {
+			if helmChart != "" || kustomize {
+				logrus.Error("Multiple --audit-path values can't be used with --helm-chart or --kustomize")
+				os.Exit(1)
+			}
+			for _, path := range auditPaths {
+				if gitops.IsRemote(path) {
+					logrus.Errorf("--audit-path %s is a Git repository, which can't be audited with other --audit-path values", path)
+					os.Exit(1)
+				}
+				if gitops.DetectRenderer(path) == gitops.RendererKustomize {
+					logrus.Warnf("%s has a kustomization, which isn't rendered when auditing several paths, so its files are audited as they are", path)
+				}
+			}
+		}
+		if checksFromInsights {
+			err := loadInsightsConfig()
+			if err != nil {
+				logrus.Errorf("Error loading config from Fairwinds Insights: %v", err)
+				os.Exit(1)
+			}
+		}
 		if displayName != "" {
 			config.DisplayName = displayName
 		}
+		if verboseResults {
+			config.VerboseResults = true
+		}
+		if gateName != "" {
+			var err error
+			gate, err = config.GetGate(gateName)
+			if err != nil {
+				logrus.Errorf("Invalid --gate: %v", err)
+				os.Exit(1)
+			}
+		}
+		if exitCodeStrategy != exitCodeStrategyFirst && exitCodeStrategy != exitCodeStrategyMax {
+			logrus.Errorf("Invalid --exit-code-strategy %s, should be first or max", exitCodeStrategy)
+			os.Exit(1)
+		}
+		if dangerExitCode < 1 || dangerExitCode > 255 {
+			logrus.Errorf("Invalid --danger-exit-code %d, should be between 1 and 255", dangerExitCode)
+			os.Exit(1)
+		}
+		if scoreExitCode < 1 || scoreExitCode > 255 {
+			logrus.Errorf("Invalid --score-exit-code %d, should be between 1 and 255", scoreExitCode)
+			os.Exit(1)
+		}
+		if warningExitCode < 0 || warningExitCode > 255 {
+			logrus.Errorf("Invalid --exit-code-on-warning %d, should be between 1 and 255, or 0 to disable it", warningExitCode)
+			os.Exit(1)
+		}
+		if minPriority != "" {
+			if _, err := cfg.ParsePriority(minPriority); err != nil {
+				logrus.Errorf("Invalid --min-priority: %v", err)
+				os.Exit(1)
+			}
+		}
+		if minSeverity != "" && minSeverity != string(cfg.SeverityDanger) && minSeverity != string(cfg.SeverityWarning) {
+			logrus.Errorf("Invalid --min-severity %s, should be one of danger or warning", minSeverity)
+			os.Exit(1)
+		}
+		granularity := validator.ScoreGranularityContainer
+		if scoreGranularity != "" {
+			var err error
+			granularity, err = validator.ParseScoreGranularity(scoreGranularity)
+			if err != nil {
+				logrus.Errorf("Invalid --score-granularity: %v", err)
+				os.Exit(1)
+			}
+		}
+		ctx := context.TODO()
+		var wasmRuntime *wasm.Runtime
+		if wasmChecksDir != "" {
+			var err error
+			wasmRuntime, err = loadWasmChecks(ctx)
+			if err != nil {
+				logrus.Errorf("Error loading WebAssembly checks: %v", err)
+				os.Exit(1)
+			}
+		}
+		for _, checkSeverity := range checkSeverities {
+			parts := strings.SplitN(checkSeverity, "=", 2)
+			if len(parts) != 2 {
+				logrus.Errorf("Invalid --set-check-severity %s, should be in the format checkID=danger", checkSeverity)
+				os.Exit(1)
+			}
+			if err := config.SetCheckSeverity(parts[0], cfg.Severity(parts[1])); err != nil {
+				logrus.Errorf("Invalid --set-check-severity %s: %v", checkSeverity, err)
+				os.Exit(1)
+			}
+		}
 		if len(checks) > 0 {
 			targetChecks := make(map[string]bool)
 			for _, check := range checks {
@@ -93,207 +374,1341 @@ var auditCmd = &cobra.Command{
 			for key := range config.Checks {
 				if isTarget := targetChecks[key]; !isTarget {
 					config.Checks[key] = cfg.SeverityIgnore
+					for _, envSeverities := range config.EnvironmentSeverities {
+						delete(envSeverities, key)
+					}
 				}
 			}
 		}
-		if auditNamespace != "" {
-			if helmChart != "" {
-				logrus.Warn("--namespace and --helm-chart are mutually exclusive. --namespace will be ignored.")
+		for _, check := range skipChecks {
+			if !config.IsKnownCheck(check) {
+				logrus.Errorf("Invalid --skip-checks %s: unknown check", check)
+				os.Exit(1)
+			}
+			config.Checks[check] = cfg.SeverityIgnore
+			for _, envSeverities := range config.EnvironmentSeverities {
+				delete(envSeverities, check)
+			}
+		}
+		for _, checkPath := range checkPaths {
+			parts := strings.SplitN(checkPath, "=", 2)
+			if len(parts) != 2 {
+				logrus.Errorf("Invalid --check-path %s, should be in the format checkID=some.json.path", checkPath)
+				os.Exit(1)
 			}
+			if _, ok := config.Checks[parts[0]]; !ok {
+				logrus.Errorf("Invalid --check-path %s: check %s is not configured", checkPath, parts[0])
+				os.Exit(1)
+			}
+			if _, err := cfg.ParseFieldPath(parts[1]); err != nil {
+				logrus.Errorf("Invalid --check-path %s: %v", checkPath, err)
+				os.Exit(1)
+			}
+			if config.CheckPaths == nil {
+				config.CheckPaths = map[string]string{}
+			}
+			config.CheckPaths[parts[0]] = parts[1]
+		}
+		if auditNamespace != "" {
 			if auditPath != "" {
 				logrus.Warn("--namespace and --audit-path are mutually exclusive. --namespace will be ignored.")
 			}
+			if gitRepo != "" {
+				logrus.Warn("--namespace and --git-repo are mutually exclusive. --namespace will be ignored.")
+			}
+			if helmChart != "" {
+				// like helm install --namespace, the release's resources which don't specify a namespace are in it
+				config.DefaultNamespace = auditNamespace
+			}
 			config.Namespace = auditNamespace
 		}
-		if helmChart != "" {
-			var err error
-			auditPath, err = ProcessHelmTemplates(helmChart, helmValues)
-			if err != nil {
-				logrus.Errorf("Couldn't process helm chart: %v", err)
+		if defaultNamespace != "" {
+			if auditPath == "" && helmChart == "" && gitRepo == "" {
+				logrus.Warn("--default-namespace only applies to --audit-path, --helm-chart, and --git-repo audits and will be ignored.")
+			}
+			config.DefaultNamespace = defaultNamespace
+		}
+		if targetK8sVersion != "" {
+			if auditPath == "" && helmChart == "" && gitRepo == "" {
+				logrus.Warn("--target-k8s-version only applies to --audit-path, --helm-chart, and --git-repo audits and will be ignored.")
+			}
+			config.DeprecatedAPIPolicy.TargetVersion = targetK8sVersion
+			if err := config.DeprecatedAPIPolicy.Validate(); err != nil {
+				logrus.Errorf("Invalid --target-k8s-version: %v", err)
 				os.Exit(1)
 			}
 		}
-		if uploadInsights && len(clusterName) == 0 {
-			logrus.Error("cluster-name is required when using --upload-insights")
+		if len(excludePaths) > 0 {
+			if auditPath == "" {
+				logrus.Warn("--exclude only applies to --audit-path audits and will be ignored.")
+			}
+			config.ExcludePaths = append(config.ExcludePaths, excludePaths...)
+		}
+		if resourceKind != "" && resourceName == "" {
+			logrus.Error("--resource-kind requires --resource-name")
 			os.Exit(1)
 		}
-		if uploadInsights {
-			if auditPath != "" {
-				logrus.Errorf("upload-insights and audit-path are not supported when used simultaneously")
-				os.Exit(1)
+		if resourceName != "" && (resourceToAudit != "" || auditPath != "" || helmChart != "" || gitRepo != "" || gitopsRepo != "") {
+			logrus.Error("--resource-name only applies to in-cluster audits, and can't be used with --resource")
+			os.Exit(1)
+		}
+		if len(resourceKinds) > 0 {
+			if auditPath != "" || helmChart != "" || gitRepo != "" {
+				logrus.Warn("--resource-kinds only applies to in-cluster audits and will be ignored.")
 			}
-			if !auth.IsLoggedIn() {
-				err := auth.HandleLogin(insightsHost)
-				if err != nil {
-					logrus.Errorf("error handling logging: %v", err)
-					os.Exit(1)
-				}
+			config.ResourceKinds = resourceKinds
+		}
+		if helmValuesFromVault != "" && helmChart == "" {
+			logrus.Error("--helm-values-from-vault requires --helm-chart")
+			os.Exit(1)
+		}
+		if len(helmSetValues) > 0 && helmChart == "" {
+			logrus.Error("--helm-set requires --helm-chart")
+			os.Exit(1)
+		}
+		for _, setValue := range helmSetValues {
+			if !strings.Contains(setValue, "=") {
+				logrus.Errorf("Invalid --helm-set %s, should be in the format key=value", setValue)
+				os.Exit(1)
 			}
 		}
-
-		ctx := context.TODO()
-		k, err := kube.CreateResourceProvider(ctx, auditPath, resourceToAudit, config)
-		if err != nil {
-			logrus.Errorf("Error fetching Kubernetes resources %v", err)
+		if helmChartVersion != "" && !strings.HasPrefix(helmChart, helmOCIPrefix) {
+			logrus.Error("--helm-chart-version requires an oci:// --helm-chart")
 			os.Exit(1)
 		}
-
-		auditData, err := validator.RunAudit(config, k)
-		if err != nil {
-			logrus.Errorf("Error while running audit on resources: %v", err)
+		if helmReleaseName != "" && helmChart == "" {
+			logrus.Warn("--helm-release-name only applies to --helm-chart and will be ignored.")
+		}
+		if kustomize && (auditPath == "" || auditPath == "-" || gitops.IsRemote(auditPath) || kube.IsOCIArtifact(auditPath) || helmChart != "") {
+			logrus.Error("--kustomize requires --audit-path to be a local directory, and can't be used with --helm-chart")
 			os.Exit(1)
 		}
-
-		if uploadInsights {
-			auth, err := auth.GetAuth(insightsHost)
+		if kustomizeEnableHelm && !kustomize && gitRepo == "" && gitopsRepo == "" && !gitops.IsRemote(auditPath) {
+			logrus.Warn("--enable-helm only applies to kustomizations rendered with --kustomize, --git-repo, and --gitops-repo and will be ignored.")
+		}
+		if gitopsRepo != "" && (auditPath != "" || helmChart != "") {
+			logrus.Error("--gitops-repo cannot be used with --audit-path or --helm-chart")
+			os.Exit(1)
+		}
+		if gitRepo != "" && (auditPath != "" || helmChart != "" || gitopsRepo != "") {
+			logrus.Error("--git-repo cannot be used with --audit-path, --helm-chart, or --gitops-repo")
+			os.Exit(1)
+		}
+		if gitRepo == "" && (gitRef != "" || gitPath != "") {
+			logrus.Error("--git-ref and --git-path require --git-repo")
+			os.Exit(1)
+		}
+		if diffAgainst != "" && auditOutputFormat != "json" && auditOutputFormat != "pretty" {
+			logrus.Error("--diff-against only supports the json and pretty formats")
+			os.Exit(1)
+		}
+		if maxRegression >= 0 && baselineFile == "" {
+			logrus.Error("--max-score-regression-per-namespace requires --baseline")
+			os.Exit(1)
+		}
+		if metadataFile != "" {
+			fileMetadata, err := validator.ReadMetadataFromFile(metadataFile)
 			if err != nil {
-				logrus.Errorf("getting auth: %v", err)
+				logrus.Errorf("Error reading --metadata-file: %v", err)
 				os.Exit(1)
 			}
-			// fetch workloads using workload plugin... or should we adapt the workloads from above?
-			dynamicClient, restMapper, clientSet, host, err := kube.GetKubeClient(ctx, "")
+			auditMetadata = fileMetadata
+		}
+		if len(metadataFlags) > 0 {
+			flagMetadata, err := validator.ParseMetadataFlags(metadataFlags)
 			if err != nil {
-				logrus.Errorf("getting the kubernetes client: %v", err)
+				logrus.Errorf("Invalid --metadata: %v", err)
+				os.Exit(1)
+			}
+			if auditMetadata == nil {
+				auditMetadata = map[string]interface{}{}
+			}
+			for key, value := range flagMetadata {
+				auditMetadata[key] = value
+			}
+		}
+		if anonymizeMapFile != "" && !anonymize {
+			logrus.Error("--anonymize-map requires --anonymize")
+			os.Exit(1)
+		}
+		if baselineFromCluster != "" && (auditPath != "" || helmChart != "" || gitopsRepo != "" || gitRepo != "") {
+			logrus.Error("--baseline-from-cluster cannot be used with --audit-path, --helm-chart, --gitops-repo, or --git-repo")
+			os.Exit(1)
+		}
+		if uploadInsightsDryRun {
+			uploadInsights = true
+		}
+		if auditOutputFormat == "compliance" || auditOutputFormat == "compliance-json" {
+			var err error
+			complianceFramework, err = cfg.GetFramework(framework)
+			if err != nil {
+				logrus.Errorf("Invalid --framework: %v", err)
+				os.Exit(1)
+			}
+		}
+		if signResults {
+			if signingKeyFile == "" || auditOutputFile == "" {
+				logrus.Error("--sign requires --signing-key and --output-file")
 				os.Exit(1)
 			}
-			k8sResources, err := workloadsPkg.CreateResourceProviderFromAPI(ctx, dynamicClient, restMapper, clientSet, host)
+			keyBytes, err := os.ReadFile(signingKeyFile)
 			if err != nil {
-				logrus.Errorf("creating resource provider: %v", err)
+				logrus.Errorf("Error reading signing key: %v", err)
 				os.Exit(1)
 			}
-
-			insightsClient := insights.NewHTTPClient(insightsHost, auth.Organization, auth.Token)
-			insightsReporter := insights.NewInsightsReporter(insightsClient)
-			wr := insights.WorkloadsReport{Version: workloads.Version, Payload: *k8sResources}
-			pr := insights.PolarisReport{Version: version, Payload: auditData}
-			logrus.Infof("Uploading to Fairwinds Insights organization '%s/%s'...", auth.Organization, clusterName)
-			err = insightsReporter.ReportAuditToFairwindsInsights(clusterName, wr, pr)
+			signingKey, err = signing.ParsePrivateKey(keyBytes)
 			if err != nil {
-				logrus.Errorf("reporting audit file to insights: %v", err)
+				logrus.Errorf("Error parsing signing key: %v", err)
 				os.Exit(1)
 			}
-			logrus.Println("Success! You can see your results at:")
-			logrus.Printf("%s/orgs/%s/clusters/%s/action-items\n", insightsHost, auth.Organization, clusterName)
-		} else {
-			outputAudit(auditData, auditOutputFile, auditOutputURL, auditOutputFormat, useColor, onlyShowFailedTests)
+			if signatureFile == "" {
+				signatureFile = auditOutputFile + ".sig"
+			}
 		}
-
-		summary := auditData.GetSummary()
-		score := summary.GetScore()
-		if setExitCode && summary.Dangers > 0 {
-			logrus.Infof("%d danger items found in audit", summary.Dangers)
-			os.Exit(3)
-		} else if minScore != 0 && score < uint(minScore) {
-			logrus.Infof("Audit score of %d is less than the provided minimum of %d", score, minScore)
-			os.Exit(4)
+		if outputSQLite != "" && !results.SQLiteAvailable() {
+			logrus.Errorf("--output-sqlite: %v", results.ErrSQLiteUnavailable)
+			os.Exit(1)
 		}
-	},
-}
-
-// ProcessHelmTemplates turns helm into yaml to be processed by Polaris or the other tools.
-func ProcessHelmTemplates(helmChart, helmValues string) (string, error) {
-	cmd := exec.Command("helm", "dependency", "update", helmChart)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logrus.Error(string(output))
-		return "", err
-	}
-
-	dir, err := os.MkdirTemp("", "*")
-	if err != nil {
-		return "", err
-	}
-	params := []string{
-		"template", helmChart,
-		"--generate-name",
-		"--output-dir",
-		dir,
-	}
-	if helmValues != "" {
-		params = append(params, "--values", helmValues)
-	}
-
-	cmd = exec.Command("helm", params...)
-	output, err = cmd.CombinedOutput()
-
-	if err != nil {
-		logrus.Error(string(output))
-		return "", err
-	}
-	return dir, nil
-}
-
-func outputAudit(auditData validator.AuditData, outputFile, outputURL, outputFormat string, useColor bool, onlyShowFailedTests bool) {
-	if onlyShowFailedTests {
-		auditData = auditData.RemoveSuccessfulResults()
-	}
-	var outputBytes []byte
-	var err error
-	if outputFormat == "score" {
-		outputBytes = []byte(fmt.Sprintf("%d\n", auditData.GetSummary().GetScore()))
-	} else if outputFormat == "yaml" {
-		var jsonBytes []byte
-		jsonBytes, err = json.Marshal(auditData)
-		if err == nil {
-			outputBytes, err = yaml.JSONToYAML(jsonBytes)
+		if storeResults && (auditPath != "" || helmChart != "" || gitopsRepo != "" || gitRepo != "") {
+			logrus.Error("--store-results cannot be used with --audit-path, --helm-chart, --gitops-repo, or --git-repo")
+			os.Exit(1)
 		}
-	} else if outputFormat == "pretty" {
-		outputBytes = []byte(auditData.GetPrettyOutput(useColor))
-	} else {
-		outputBytes, err = json.MarshalIndent(auditData, "", "  ")
-	}
-	if err != nil {
-		logrus.Errorf("Error marshalling audit: %v", err)
-		os.Exit(1)
-	}
-	if outputURL == "" && outputFile == "" {
-		os.Stdout.Write(outputBytes)
-	} else {
-		if outputURL != "" {
-			req, err := http.NewRequest("POST", outputURL, bytes.NewBuffer(outputBytes))
-
-			if err != nil {
-				logrus.Errorf("Error building request for output: %v", err)
+		if resultsRetention < 0 {
+			logrus.Errorf("Invalid --results-retention %d, should be at least 0", resultsRetention)
+			os.Exit(1)
+		}
+		if emitEvents && (auditPath != "" || helmChart != "" || gitopsRepo != "" || gitRepo != "") {
+			logrus.Error("--emit-events cannot be used with --audit-path, --helm-chart, --gitops-repo, or --git-repo")
+			os.Exit(1)
+		}
+		if config.SeverityPromotion.AfterFailures > 0 && !storeResults {
+			logrus.Warn("severityPromotion requires --store-results to track failures across audits")
+		}
+		if (reportBranding.Title != "" || reportBranding.Footer != "") && auditOutputFormat != "pretty" && auditOutputFormat != "html" {
+			logrus.Warn("--report-title and --report-footer only apply to the pretty and html formats and will be ignored.")
+		}
+		if groupIdentical && auditOutputFormat != "pretty" {
+			logrus.Warn("--group-identical only applies to the pretty format and will be ignored.")
+		}
+		if compressOutputURL && auditOutputURL == "" {
+			logrus.Warn("--output-compress only applies to --output-url and will be ignored.")
+		} else if compressOutputURL && auditOutputFormat != "json" && auditOutputFormat != "yaml" {
+			logrus.Warn("--output-compress only applies to the json and yaml formats and will be ignored.")
+		}
+		if outputIndentTabs && !cmd.Flags().Changed("indent") {
+			outputIndent = 1
+		}
+		if outputIndent < 1 || outputIndent > 9 {
+			logrus.Errorf("--indent must be between 1 and 9, got %d", outputIndent)
+			os.Exit(1)
+		}
+		if auditOutputFormat == "yaml" && outputIndentTabs {
+			logrus.Error("--indent-tabs cannot be used with the yaml format, YAML can't be indented with tabs")
+			os.Exit(1)
+		}
+		if auditOutputFormat == "yaml" && outputIndent < 2 {
+			logrus.Errorf("--indent must be at least 2 for the yaml format, got %d", outputIndent)
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("parallelism") {
+			if parallelism < 1 {
+				logrus.Errorf("--parallelism must be at least 1, got %d", parallelism)
 				os.Exit(1)
 			}
-
-			if outputFormat == "json" {
-				req.Header.Set("Content-Type", "application/json")
-			} else if outputFormat == "yaml" {
-				req.Header.Set("Content-Type", "application/x-yaml")
-			} else {
-				req.Header.Set("Content-Type", "text/plain")
+			config.Parallelism = parallelism
+		}
+		if concurrency < 1 {
+			logrus.Errorf("--concurrency must be at least 1, got %d", concurrency)
+			os.Exit(1)
+		}
+		if !uploadInsights && !insightsNamespaces.IsEmpty() {
+			logrus.Error("--include-namespaces and --exclude-namespaces require --upload-insights")
+			os.Exit(1)
+		}
+		if insightsRetries < 0 {
+			logrus.Errorf("--insights-retries must be at least 0, got %d", insightsRetries)
+			os.Exit(1)
+		}
+		if insightsTimeout < 0 {
+			logrus.Errorf("--insights-timeout must not be negative, got %s", insightsTimeout)
+			os.Exit(1)
+		}
+		if uploadInsights && len(clusterName) == 0 {
+			logrus.Error("cluster-name is required when using --upload-insights")
+			os.Exit(1)
+		}
+		if uploadInsights {
+			if auditPath != "" || helmChart != "" || gitopsRepo != "" || gitRepo != "" {
+				logrus.Errorf("upload-insights is not supported with audit-path, helm-chart, gitops-repo, or git-repo")
+				os.Exit(1)
+			}
+			if !auth.IsLoggedIn() && !auditDryRun {
+				err := auth.HandleLogin(newRequestHTTPClient(), insightsHost)
+				if err != nil {
+					logrus.Errorf("error handling logging: %v", err)
+					os.Exit(1)
+				}
 			}
+		}
 
-			client := &http.Client{}
-			if skipSslValidation {
-				transport := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
-				client = &http.Client{Transport: transport}
+		// the chart is rendered once the flags are valid, so invalid combinations don't run helm or read from Vault
+		if helmChart != "" && !auditDryRun {
+			var vaultValues map[string]interface{}
+			if helmValuesFromVault != "" {
+				var err error
+				vaultValues, err = vault.ReadSecretFromEnv(newRequestHTTPClient(), helmValuesFromVault)
+				if err != nil {
+					logrus.Errorf("Couldn't read helm values from Vault: %v", err)
+					os.Exit(1)
+				}
+				helmSecrets = vault.GetSecretStrings(vaultValues)
 			}
-			resp, err := client.Do(req)
+			var err error
+			auditPath, err = ProcessHelmTemplates(helmChart, HelmTemplateOptions{
+				Version:     helmChartVersion,
+				ReleaseName: helmReleaseName,
+				Namespace:   auditNamespace,
+				Values:      helmValues,
+				SetValues:   helmSetValues,
+				VaultValues: vaultValues,
+				KubeVersion: helmKubeVersion,
+				APIVersions: helmAPIVersions,
+				Timeout:     helmTimeout,
+			})
 			if err != nil {
-				logrus.Errorf("Error making request for output: %v", err)
+				logrus.Errorf("Couldn't process helm chart: %v", err)
 				os.Exit(1)
 			}
+		}
 
-			defer resp.Body.Close()
-
-			body, err := io.ReadAll(resp.Body)
-
-			if err != nil {
-				logrus.Errorf("Error reading response: %v", err)
+		if auditDryRun {
+			problems := dryRunAudit(ctx)
+			for _, problem := range problems {
+				logrus.Errorf("Dry run: %v", problem)
+			}
+			if len(problems) > 0 {
 				os.Exit(1)
 			}
-
-			logrus.Infof("Received response: %v", body)
+			logrus.Info("Dry run: config and flags are valid, skipping the audit")
+			if exitReasonFile != "" {
+				err := writeExitReasonFile(exitReasonFile, exitReason{Reason: exitReasonClean, Failures: []exitReasonFailure{}})
+				if err != nil {
+					logrus.Errorf("Error writing exit reason: %v", err)
+					os.Exit(1)
+				}
+			}
+			return
 		}
-
-		if outputFile != "" {
-			err := os.WriteFile(outputFile, outputBytes, 0644)
+		var auditData validator.AuditData
+		var k *kube.ResourceProvider
+		var err error
+		if gitopsRepo != "" {
+			auditData, err = auditGitOpsRepo(ctx, gitopsRepo)
 			if err != nil {
-				logrus.Errorf("Error writing output to file: %v", err)
+				logrus.Errorf("Error auditing GitOps repo: %v", err)
 				os.Exit(1)
 			}
-		}
-	}
+		} else {
+			var gitCloneDir string
+			auditSource := auditPath
+			if gitRepo != "" || gitops.IsRemote(auditPath) {
+				remote, err := getGitRemote()
+				if err != nil {
+					logrus.Errorf("Invalid Git repository: %v", err)
+					os.Exit(1)
+				}
+				auditSource = remote.String()
+				gitCloneDir, auditPath, err = ProcessGitRemote(remote, helmTimeout)
+				if err != nil {
+					logrus.Errorf("Couldn't audit %s: %v", auditSource, err)
+					os.Exit(1)
+				}
+			}
+			var kustomizeDir string
+			if gitCloneDir == "" && kustomizeAuditPath() {
+				kustomizeDir, err = ProcessKustomization(auditPath, kustomizeEnableHelm, helmTimeout)
+				if err != nil {
+					logrus.Errorf("Couldn't build kustomization %s: %v", auditPath, err)
+					os.Exit(1)
+				}
+				auditPath = kustomizeDir
+			}
+			if resourceName != "" {
+				k, err = kube.CreateResourceProviderFromResourceName(ctx, resourceName, resourceKind, config)
+			} else if len(auditPaths) > 1 {
+				k, err = kube.CreateResourceProviderFromPaths(ctx, auditPaths, config)
+			} else {
+				k, err = kube.CreateResourceProvider(ctx, auditPath, resourceToAudit, config)
+			}
+			if gitCloneDir != "" {
+				os.RemoveAll(gitCloneDir)
+				os.RemoveAll(auditPath)
+			}
+			if kustomizeDir != "" {
+				os.RemoveAll(kustomizeDir)
+			}
+			if err != nil {
+				logrus.Errorf("Error fetching Kubernetes resources %v", err)
+				os.Exit(1)
+			}
+			if gitCloneDir != "" || kustomizeDir != "" {
+				k.SourceName = auditSource
+			}
+
+			if len(helmSecrets) > 0 {
+				// the rendered templates include values from Vault, so don't leave them on disk
+				os.RemoveAll(auditPath)
+			}
+
+			auditData, err = validator.RunAudit(config, k)
+			if err != nil {
+				logrus.Errorf("Error while running audit on resources: %v", err)
+				os.Exit(1)
+			}
+			auditedLocalFiles = gitCloneDir == "" && kustomizeDir == "" && helmChart == "" && k.SourceType == "Path"
+			for _, path := range auditPaths {
+				if kube.IsOCIArtifact(path) {
+					auditedLocalFiles = false
+				}
+			}
+		}
+		if wasmRuntime != nil {
+			// the checks only run during the audit, so the modules don't need to stay in memory
+			wasmRuntime.Close(ctx)
+		}
+		logrus.WithFields(logrus.Fields{
+			"sourceType": auditData.SourceType,
+			"source":     auditData.SourceName,
+			"resources":  len(auditData.Results),
+			"score":      auditData.Score,
+		}).Info("Audit complete")
+		auditData = auditData.AddMetadata(auditMetadata)
+		if granularity != validator.ScoreGranularityContainer {
+			auditData = auditData.SetScoreGranularity(granularity)
+		}
+		if len(helmSecrets) > 0 {
+			auditData = auditData.Redact(func(str string) string {
+				return vault.Redact(str, helmSecrets)
+			})
+		}
+
+		if storeResults {
+			auditData, err = storeAuditResult(ctx, auditData)
+			if err != nil {
+				logrus.Errorf("Error storing audit result: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		if baselineFromCluster != "" {
+			err = validator.NewBaselineFromAudit(auditData).WriteToFile(baselineFromCluster)
+			if err != nil {
+				logrus.Errorf("Error writing baseline to file: %v", err)
+				os.Exit(1)
+			}
+			logrus.Infof("Wrote baseline of current failures to %s", baselineFromCluster)
+		}
+
+		regressions := []validator.NamespaceRegression{}
+		if baselineFile != "" {
+			baseline, err := validator.ReadBaselineFromFile(baselineFile)
+			if err != nil {
+				logrus.Errorf("Error reading baseline: %v", err)
+				os.Exit(1)
+			}
+			if maxRegression >= 0 {
+				if len(baseline.NamespaceScores) == 0 {
+					logrus.Warnf("Baseline %s has no namespace scores, regenerate it with --baseline-from-cluster", baselineFile)
+				}
+				// compare scores before known failures are suppressed, as the baseline scores include them
+				regressions = auditData.GetNamespaceRegressions(baseline, uint(maxRegression))
+			}
+			auditData = auditData.ApplyBaseline(baseline)
+		}
+
+		if diffAgainst != "" {
+			delta := auditData.GetDelta(validator.ReadAuditFromFile(diffAgainst))
+			auditDelta = &delta
+		}
+
+		if emitEvents {
+			// Events are a side channel, so failing to write them shouldn't lose the audit's output
+			err = emitAuditEvents(ctx, auditData, k)
+			if err != nil {
+				logrus.Warnf("Error emitting Events: %v", err)
+			}
+		}
+
+		if transformExec != "" {
+			auditData, err = validator.TransformWithExec(auditData, transformExec)
+			if err != nil {
+				logrus.Errorf("Error transforming audit: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		if anonymize {
+			anonymizer, err := validator.NewAnonymizer([]byte(os.Getenv(validator.AnonymizeKeyEnvVar)))
+			if err != nil {
+				logrus.Errorf("Error creating anonymize key: %v", err)
+				os.Exit(1)
+			}
+			auditData = anonymizer.Anonymize(auditData)
+			if anonymizeMapFile != "" {
+				mapBytes, err := json.MarshalIndent(anonymizer.Mapping, "", "  ")
+				if err != nil {
+					logrus.Errorf("Error marshalling anonymize map: %v", err)
+					os.Exit(1)
+				}
+				err = os.WriteFile(anonymizeMapFile, mapBytes, 0600)
+				if err != nil {
+					logrus.Errorf("Error writing anonymize map to file: %v", err)
+					os.Exit(1)
+				}
+			}
+		}
+
+		if outputSQLite != "" {
+			err = writeSQLite(auditData)
+			if err != nil {
+				logrus.Errorf("Error writing audit to SQLite: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		if uploadInsights {
+			auth, err := auth.GetAuth(insightsHost)
+			if err != nil {
+				logrus.Errorf("getting auth: %v", err)
+				os.Exit(1)
+			}
+			// fetch workloads using workload plugin... or should we adapt the workloads from above?
+			dynamicClient, restMapper, clientSet, host, err := kube.GetKubeClient(ctx, config.Kubeconfig, config.KubeContext)
+			if err != nil {
+				logrus.Errorf("getting the kubernetes client: %v", err)
+				os.Exit(1)
+			}
+			k8sResources, err := insights.GetWorkloads(ctx, dynamicClient, restMapper, clientSet, host, concurrency)
+			if err != nil {
+				logrus.Errorf("fetching workloads: %v", err)
+				os.Exit(1)
+			}
+
+			insightsClient := newInsightsClient(auth.Organization, auth.Token)
+			insightsReporter := insights.NewInsightsReporterWithReauthentication(insightsClient, func() (insights.Client, error) {
+				auth, err = reauthenticateInsights()
+				if err != nil {
+					return nil, err
+				}
+				logrus.Info("Retrying request to Fairwinds Insights...")
+				return newInsightsClient(auth.Organization, auth.Token), nil
+			})
+			wr := insights.WorkloadsReport{Version: workloads.Version, Payload: *k8sResources}
+			pr := insights.PolarisReport{Version: version, Payload: auditData}
+			wr, pr = insightsNamespaces.FilterReports(wr, pr)
+			if uploadInsightsDryRun {
+				payload, err := insightsReporter.BuildDryRunReport(wr, pr)
+				if err != nil {
+					logrus.Errorf("building insights reports: %v", err)
+					os.Exit(1)
+				}
+				if auditOutputFile != "" {
+					err = os.WriteFile(auditOutputFile, payload, 0644)
+					if err != nil {
+						logrus.Errorf("Error writing output to file: %v", err)
+						os.Exit(1)
+					}
+				} else {
+					os.Stdout.Write(payload)
+				}
+				logrus.Infof("Dry run: reports for Fairwinds Insights organization '%s/%s' were not uploaded", auth.Organization, clusterName)
+			} else {
+				logrus.Infof("Uploading to Fairwinds Insights organization '%s/%s'...", auth.Organization, clusterName)
+				err = insightsReporter.ReportAuditToFairwindsInsights(clusterName, wr, pr)
+				if err != nil {
+					logrus.Errorf("reporting audit file to insights: %v", err)
+					os.Exit(1)
+				}
+				logrus.Println("Success! You can see your results at:")
+				logrus.Printf("%s/orgs/%s/clusters/%s/action-items\n", insightsHost, auth.Organization, clusterName)
+			}
+		} else {
+			outputAudit(auditData, auditOutputFile, auditOutputURL, auditOutputFormat, useColor, onlyShowFailedTests)
+		}
+
+		failures := getAuditFailures(auditData, regressions)
+		if exitReasonFile != "" {
+			err = writeExitReason(exitReasonFile, auditData, failures)
+			if err != nil {
+				logrus.Errorf("Error writing exit reason: %v", err)
+				os.Exit(1)
+			}
+		}
+		exitWithFailures(failures)
+	},
+}
+
+// reasons given by --exit-reason-file for each exit code threshold
+const (
+	exitReasonClean               = "clean"
+	exitReasonError               = "error"
+	exitReasonDangers             = "dangers"
+	exitReasonWarnings            = "warnings"
+	exitReasonScore               = "score"
+	exitReasonGate                = "gate"
+	exitReasonNamespaceRegression = "namespace-regression"
+	exitReasonNewDangers          = "new-dangers"
+)
+
+// auditFailure is an exit code threshold the audit failed, with the messages logged for it
+type auditFailure struct {
+	exitCode int
+	reason   string
+	messages []string
+}
+
+// getAuditFailures returns every exit code threshold the audit fails, in the order they're checked.
+// The danger, warning and score thresholds are checked together, highest exit code first.
+func getAuditFailures(auditData validator.AuditData, regressions []validator.NamespaceRegression) []auditFailure {
+	failures := []auditFailure{}
+	summary := auditData.GetSummary()
+	score := summary.GetScore()
+	if setExitCode && summary.Dangers > 0 {
+		failures = append(failures, auditFailure{dangerExitCode, exitReasonDangers, []string{fmt.Sprintf("%d danger items found in audit", summary.Dangers)}})
+	}
+	if warningExitCode != 0 && summary.Warnings > 0 {
+		failures = append(failures, auditFailure{warningExitCode, exitReasonWarnings, []string{fmt.Sprintf("%d warning items found in audit", summary.Warnings)}})
+	}
+	if minScore != 0 && score < uint(minScore) {
+		failures = append(failures, auditFailure{scoreExitCode, exitReasonScore, []string{fmt.Sprintf("Audit score of %d is less than the provided minimum of %d", score, minScore)}})
+	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].exitCode > failures[j].exitCode
+	})
+	if gateName != "" {
+		for _, failure := range auditData.EvaluateGateFailures(gate) {
+			failures = append(failures, auditFailure{failure.ExitCode, exitReasonGate, []string{fmt.Sprintf("Gate %s failed: %s", gateName, failure.Reason)}})
+		}
+	}
+	if len(regressions) > 0 {
+		messages := []string{}
+		for _, regression := range regressions {
+			messages = append(messages, fmt.Sprintf("Namespace %q scored %d, down from %d in the baseline", regression.Namespace, regression.Score, regression.BaselineScore))
+		}
+		messages = append(messages, fmt.Sprintf("%d namespaces regressed by more than %d points", len(regressions), maxRegression))
+		failures = append(failures, auditFailure{validator.GateExitCodeNamespaceRegression, exitReasonNamespaceRegression, messages})
+	}
+	if auditDelta != nil && auditDelta.NewDangers() > 0 {
+		failures = append(failures, auditFailure{validator.GateExitCodeNewDangers, exitReasonNewDangers, []string{fmt.Sprintf("%d new danger items found since %s", auditDelta.NewDangers(), diffAgainst)}})
+	}
+	return failures
+}
+
+// getExitFailures returns the failures which set the exit code, which is the first failure or,
+// with --exit-code-strategy max, every failure
+func getExitFailures(failures []auditFailure) []auditFailure {
+	if exitCodeStrategy != exitCodeStrategyMax && len(failures) > 1 {
+		return failures[:1]
+	}
+	return failures
+}
+
+// getExitFailure returns the failure with the highest exit code, which the audit exits with
+func getExitFailure(failures []auditFailure) (auditFailure, bool) {
+	exitFailure := auditFailure{}
+	for _, failure := range getExitFailures(failures) {
+		if failure.exitCode > exitFailure.exitCode {
+			exitFailure = failure
+		}
+	}
+	return exitFailure, exitFailure.exitCode > 0
+}
+
+// exitWithFailures exits with the code of the first failure or, with --exit-code-strategy max,
+// logs every failure and exits with the highest code
+func exitWithFailures(failures []auditFailure) {
+	exitFailure, ok := getExitFailure(failures)
+	if !ok {
+		return
+	}
+	for _, failure := range getExitFailures(failures) {
+		for _, message := range failure.messages {
+			logrus.Info(message)
+		}
+	}
+	os.Exit(exitFailure.exitCode)
+}
+
+// exitReason is written to --exit-reason-file, so automation can tell why the audit failed without parsing logs
+type exitReason struct {
+	ExitCode int
+	// Reason is the threshold which set the exit code, e.g. dangers or score, clean if none failed,
+	// or error if the audit couldn't complete
+	Reason   string
+	Score    uint
+	MinScore int `json:",omitempty"`
+	Dangers  uint
+	Warnings uint
+	// Failures are the thresholds which failed and were considered for the exit code, with their messages
+	Failures []exitReasonFailure
+}
+
+type exitReasonFailure struct {
+	ExitCode int
+	Reason   string
+	Messages []string
+}
+
+// writeExitReason writes why the audit is about to exit, along with its score and counts
+func writeExitReason(path string, auditData validator.AuditData, failures []auditFailure) error {
+	summary := auditData.GetSummary()
+	reason := exitReason{
+		Reason:   exitReasonClean,
+		Score:    summary.GetScore(),
+		MinScore: minScore,
+		Dangers:  summary.Dangers,
+		Warnings: summary.Warnings,
+		Failures: []exitReasonFailure{},
+	}
+	if exitFailure, ok := getExitFailure(failures); ok {
+		reason.ExitCode = exitFailure.exitCode
+		reason.Reason = exitFailure.reason
+	}
+	for _, failure := range getExitFailures(failures) {
+		reason.Failures = append(reason.Failures, exitReasonFailure{ExitCode: failure.exitCode, Reason: failure.reason, Messages: failure.messages})
+	}
+	return writeExitReasonFile(path, reason)
+}
+
+// writeErrorExitReason writes that the audit exited with an error, before it has a score or counts
+func writeErrorExitReason(path string) error {
+	return writeExitReasonFile(path, exitReason{ExitCode: 1, Reason: exitReasonError, Failures: []exitReasonFailure{}})
+}
+
+func writeExitReasonFile(path string, reason exitReason) error {
+	contents, err := marshalJSON(reason)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// HelmTemplateOptions are passed through to helm when templating a chart
+type HelmTemplateOptions struct {
+	// Version is the version of an oci:// chart to pull, or empty for the latest
+	Version string
+	// ReleaseName is the name of the release when templating, instead of a generated one
+	ReleaseName string
+	// Namespace is the namespace of the release when templating
+	Namespace string
+	Values    string
+	// SetValues are passed to helm as --set key=value, and take precedence over the values files
+	SetValues   []string
+	KubeVersion string
+	APIVersions []string
+	// VaultValues are passed to helm after Values, and are redacted from any helm output that's logged
+	VaultValues map[string]interface{}
+	// Timeout limits how long each helm command may run. Zero means no limit.
+	Timeout time.Duration
+}
+
+// ProcessHelmTemplates turns helm into yaml to be processed by Polaris or the other tools.
+// Charts in OCI registries, e.g. oci://registry.example.com/charts/myapp, are pulled first using
+// helm's registry login.
+func ProcessHelmTemplates(helmChart string, opts HelmTemplateOptions) (string, error) {
+	secrets := vault.GetSecretStrings(opts.VaultValues)
+	if strings.HasPrefix(helmChart, helmOCIPrefix) {
+		pullDir, err := pullHelmChart(helmChart, opts.Version, opts.Timeout)
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(pullDir)
+		helmChart, err = getPulledChartPath(pullDir)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		err := runHelm(opts.Timeout, secrets, "dependency", "update", helmChart)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "*")
+	if err != nil {
+		return "", err
+	}
+	params := []string{"template"}
+	if opts.ReleaseName != "" {
+		params = append(params, opts.ReleaseName, helmChart)
+	} else {
+		params = append(params, helmChart, "--generate-name")
+	}
+	params = append(params, "--output-dir", dir)
+	if opts.Namespace != "" {
+		params = append(params, "--namespace", opts.Namespace)
+	}
+	if opts.Values != "" {
+		params = append(params, "--values", opts.Values)
+	}
+	if opts.VaultValues != nil {
+		valuesFile, err := os.CreateTemp("", "polaris-vault-values-*.yaml")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(valuesFile.Name())
+		// JSON is valid YAML, and CreateTemp makes the file readable only by the current user
+		err = json.NewEncoder(valuesFile).Encode(opts.VaultValues)
+		valuesFile.Close()
+		if err != nil {
+			return "", err
+		}
+		params = append(params, "--values", valuesFile.Name())
+	}
+	for _, setValue := range opts.SetValues {
+		params = append(params, "--set", setValue)
+	}
+	if opts.KubeVersion != "" {
+		params = append(params, "--kube-version", opts.KubeVersion)
+	}
+	for _, apiVersion := range opts.APIVersions {
+		params = append(params, "--api-versions", apiVersion)
+	}
+
+	err = runHelm(opts.Timeout, secrets, params...)
+	if err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// pullHelmChart pulls and unpacks an OCI chart into a new temporary directory, which the caller should remove.
+// Packaged charts include their dependencies, so they don't need a dependency update.
+func pullHelmChart(ref, version string, timeout time.Duration) (string, error) {
+	dir, err := os.MkdirTemp("", "polaris-helm-pull-*")
+	if err != nil {
+		return "", err
+	}
+	params := []string{"pull", ref, "--untar", "--untardir", dir}
+	if version != "" {
+		params = append(params, "--version", version)
+	}
+	err = runHelm(timeout, nil, params...)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// getPulledChartPath returns the directory helm pull unpacked the chart into, which is named after the chart
+func getPulledChartPath(pullDir string) (string, error) {
+	entries, err := os.ReadDir(pullDir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return filepath.Join(pullDir, entry.Name()), nil
+		}
+	}
+	return "", errors.New("helm pull didn't unpack a chart")
+}
+
+// runHelm runs a helm command, killing it if it runs longer than the timeout.
+// Any output, including partial output from a killed command, is logged on failure with the secrets redacted.
+func runHelm(timeout time.Duration, secrets []string, args ...string) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	// don't wait forever on output pipes held open by children of a killed helm
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		logrus.Error(vault.Redact(string(output), secrets))
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("helm %s timed out after %s", args[0], timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// newInsightsClient returns a Fairwinds Insights client which retries idempotent requests as set by
+// --insights-retries and --insights-timeout
+func newInsightsClient(organization, token string) insights.Client {
+	transport := newRetryTransport()
+	transport.Retries = insightsRetries
+	transport.AttemptTimeout = insightsTimeout
+	return insights.NewHTTPClientWithClient(&http.Client{Transport: transport}, insightsHost, organization, token)
+}
+
+// reauthenticateInsights logs in again after Insights rejected the cached token. Without a
+// terminal to prompt on, it fails with an explanation instead.
+func reauthenticateInsights() (*auth.Host, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, errors.New("Fairwinds Insights token expired, run `polaris auth login` to authenticate again")
+	}
+	logrus.Warn("Fairwinds Insights token expired, please log in again")
+	err := auth.HandleLogin(newRequestHTTPClient(), insightsHost)
+	if err != nil {
+		return nil, fmt.Errorf("error handling login: %w", err)
+	}
+	return auth.GetAuth(insightsHost)
+}
+
+func storeAuditResult(ctx context.Context, auditData validator.AuditData) (validator.AuditData, error) {
+	dynamicClient, _, _, _, err := kube.GetKubeClient(ctx, config.Kubeconfig, config.KubeContext)
+	if err != nil {
+		return auditData, err
+	}
+	store := results.NewStore(dynamicClient, resultsNamespace)
+	previous, err := store.Latest(ctx)
+	if err != nil {
+		return auditData, err
+	}
+	previousStreaks := map[string]int{}
+	if previous != nil {
+		previousStreaks = previous.GetFailureStreaks()
+	}
+	auditData, streaks := auditData.ApplySeverityPromotion(previousStreaks, config.SeverityPromotion.AfterFailures)
+	stored, err := store.Save(ctx, results.NewAuditResultSpec(auditData, streaks))
+	if err != nil {
+		return auditData, err
+	}
+	logrus.Infof("Stored audit result %s/%s", resultsNamespace, stored.Name)
+	deleted, err := store.Prune(ctx, resultsRetention)
+	if err != nil {
+		return auditData, err
+	}
+	if deleted > 0 {
+		logrus.Infof("Deleted %d old audit results, keeping the latest %d", deleted, resultsRetention)
+	}
+	return auditData, nil
+}
+
+// loadInsightsConfig replaces the configuration with the organization's configuration from Fairwinds Insights,
+// unless one was given with --config
+func loadInsightsConfig() error {
+	if len(configPaths) > 0 {
+		logrus.Infof("Using the config at %s instead of the config from Fairwinds Insights", strings.Join(configPaths, ", "))
+		return nil
+	}
+	if configURL != "" {
+		logrus.Infof("Using the config from %s instead of the config from Fairwinds Insights", configURL)
+		return nil
+	}
+	insightsAuth, err := auth.GetAuth(insightsHost)
+	if err != nil {
+		return fmt.Errorf("getting auth, run `polaris auth login`: %w", err)
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return err
+	}
+	cachePath := filepath.Join(cacheDir, "polaris", "insights-config-"+insightsAuth.Organization+".yaml")
+	client := newInsightsClient(insightsAuth.Organization, insightsAuth.Token)
+	contents, err := insights.LoadPolarisConfig(client, cachePath)
+	if err != nil {
+		return err
+	}
+	insightsConfig, err := cfg.Parse(contents)
+	if err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	if err := applyConfigFlags(&insightsConfig); err != nil {
+		return err
+	}
+	config = insightsConfig
+	logrus.Infof("Using the Polaris config of organization %s from Fairwinds Insights", insightsAuth.Organization)
+	return nil
+}
+
+// loadWasmChecks adds the WebAssembly checks to the config, and returns their runtime to close after the audit
+func loadWasmChecks(ctx context.Context) (*wasm.Runtime, error) {
+	runtime, err := wasm.LoadChecks(ctx, wasmChecksDir, wasmCheckTimeout)
+	if err != nil {
+		return nil, err
+	}
+	for _, module := range runtime.Checks {
+		if err := config.AddWasmModule(module); err != nil {
+			runtime.Close(ctx)
+			return nil, err
+		}
+	}
+	logrus.Infof("Loaded %d WebAssembly checks from %s", len(runtime.Checks), wasmChecksDir)
+	return runtime, nil
+}
+
+func emitAuditEvents(ctx context.Context, auditData validator.AuditData, k *kube.ResourceProvider) error {
+	_, _, clientSet, _, err := kube.GetKubeClient(ctx, config.Kubeconfig, config.KubeContext)
+	if err != nil {
+		return err
+	}
+	emitted, err := events.NewEmitter(clientSet, maxEvents).Emit(ctx, auditData, k)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Emitted %d Events", emitted)
+	return nil
+}
+
+func writeSQLite(auditData validator.AuditData) error {
+	db, err := results.OpenSQLite(outputSQLite)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runID, err := results.SaveToSQLite(db, auditData)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Wrote audit to %s as run %d", outputSQLite, runID)
+	return nil
+}
+
+// dryRunAudit checks everything an audit depends on without fetching or auditing resources,
+// returning every problem found rather than stopping at the first one
+func dryRunAudit(ctx context.Context) []error {
+	problems := []error{}
+	if !funk.ContainsString(auditFormats, auditOutputFormat) {
+		problems = append(problems, fmt.Errorf("unknown --format %s, should be one of %s", auditOutputFormat, strings.Join(auditFormats, ", ")))
+	}
+	if err := config.ValidateChecks(); err != nil {
+		problems = append(problems, fmt.Errorf("invalid config: %v", err))
+	}
+	if helmChart != "" {
+		if _, err := exec.LookPath("helm"); err != nil {
+			problems = append(problems, fmt.Errorf("--helm-chart requires helm: %v", err))
+		}
+		// charts in OCI registries aren't pulled until the audit runs
+		if !strings.HasPrefix(helmChart, helmOCIPrefix) {
+			if _, err := os.Stat(helmChart); err != nil {
+				problems = append(problems, fmt.Errorf("reading --helm-chart: %v", err))
+			}
+		}
+	}
+	if kustomize {
+		if _, err := exec.LookPath("kustomize"); err != nil {
+			problems = append(problems, fmt.Errorf("--kustomize requires kustomize: %v", err))
+		}
+	}
+	if gitRepo != "" || gitops.IsRemote(auditPath) {
+		if _, err := exec.LookPath("git"); err != nil {
+			problems = append(problems, fmt.Errorf("auditing a Git repository requires git: %v", err))
+		}
+		if _, err := getGitRemote(); err != nil {
+			problems = append(problems, fmt.Errorf("invalid Git repository: %v", err))
+		}
+	} else {
+		for _, path := range auditPaths {
+			if kube.IsOCIArtifact(path) {
+				if _, err := kube.ParseOCIReference(path); err != nil {
+					problems = append(problems, fmt.Errorf("invalid OCI artifact: %v", err))
+				}
+			} else if path != "-" {
+				if _, err := os.Stat(path); err != nil {
+					problems = append(problems, fmt.Errorf("reading --audit-path: %v", err))
+				}
+			}
+		}
+	}
+	if gitopsRepo != "" {
+		apps, err := gitops.Discover(gitopsRepo)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("reading --gitops-repo: %v", err))
+		}
+		for _, app := range apps {
+			if app.Renderer == gitops.RendererDirectory {
+				continue
+			}
+			if _, err := exec.LookPath(string(app.Renderer)); err != nil {
+				problems = append(problems, fmt.Errorf("%s %s requires %s: %v", app.Kind, app.Name, app.Renderer, err))
+			}
+		}
+	}
+	if baselineFile != "" {
+		if _, err := validator.ReadBaselineFromFile(baselineFile); err != nil {
+			problems = append(problems, fmt.Errorf("reading --baseline: %v", err))
+		}
+	}
+	if diffAgainst != "" {
+		contents, err := os.ReadFile(diffAgainst)
+		if err == nil {
+			_, err = validator.ParseAudit(contents)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("reading --diff-against: %v", err))
+		}
+	}
+	if (auditPath == "" && helmChart == "" && gitopsRepo == "" && gitRepo == "") || uploadInsights {
+		if _, _, _, _, err := kube.GetKubeClient(ctx, config.Kubeconfig, config.KubeContext); err != nil {
+			problems = append(problems, fmt.Errorf("cluster is not reachable: %v", err))
+		}
+	}
+	if uploadInsights {
+		insightsAuth, err := auth.GetAuth(insightsHost)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("getting Fairwinds Insights auth, run `polaris auth login`: %v", err))
+		} else {
+			valid, err := newInsightsClient(insightsAuth.Organization, insightsAuth.Token).IsTokenValid()
+			if err != nil {
+				problems = append(problems, fmt.Errorf("Fairwinds Insights is not reachable: %v", err))
+			} else if !valid {
+				problems = append(problems, errors.New("Fairwinds Insights token is not valid, run `polaris auth login`"))
+			}
+		}
+	}
+	return problems
+}
+
+// marshalJSON indents json output with --indent spaces, or tabs with --indent-tabs
+func marshalJSON(v interface{}) ([]byte, error) {
+	indentChar := " "
+	if outputIndentTabs {
+		indentChar = "\t"
+	}
+	return json.MarshalIndent(v, "", strings.Repeat(indentChar, outputIndent))
+}
+
+// marshalYAML converts v through JSON so yaml output has the same field names as json output.
+// The default indent is written as before, other indents need an encoder that supports them.
+func marshalYAML(v interface{}) ([]byte, error) {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if outputIndent == defaultOutputIndent {
+		return yaml.JSONToYAML(jsonBytes)
+	}
+	var obj interface{}
+	err = yamlv3.Unmarshal(jsonBytes, &obj)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.Buffer{}
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(outputIndent)
+	err = encoder.Encode(obj)
+	if err == nil {
+		err = encoder.Close()
+	}
+	return buf.Bytes(), err
+}
+
+func outputAudit(auditData validator.AuditData, outputFile, outputURL, outputFormat string, useColor bool, onlyShowFailedTests bool) {
+	if minSeverity != "" {
+		auditData = auditData.FilterBySeverity(cfg.Severity(minSeverity))
+	}
+	if minPriority != "" {
+		auditData = auditData.FilterByPriority(cfg.Priority(minPriority))
+	}
+	if onlyShowFailedTests {
+		auditData = auditData.RemoveSuccessfulResults()
+	}
+	// when streaming, ndjson output is written to the request as it's encoded rather than buffered
+	streaming := streamOutputURL && outputURL != "" && outputFormat == "ndjson"
+	var outputBytes []byte
+	var err error
+	if auditDelta != nil && outputFormat == "json" {
+		outputBytes, err = marshalJSON(auditDelta)
+	} else if auditDelta != nil {
+		outputBytes = []byte(auditDelta.GetPrettyOutput())
+	} else if outputFormat == "score" {
+		outputBytes = []byte(fmt.Sprintf("%d\n", auditData.GetSummary().GetScore()))
+	} else if outputFormat == "yaml" {
+		outputBytes, err = marshalYAML(auditData)
+	} else if outputFormat == "pretty" {
+		if groupIdentical {
+			outputBytes = []byte(auditData.GetGroupedPrettyOutput(useColor, reportBranding))
+		} else {
+			outputBytes = []byte(auditData.GetBrandedPrettyOutput(useColor, reportBranding))
+		}
+	} else if outputFormat == "status" {
+		outputBytes = []byte(auditData.GetStatusOutput())
+	} else if outputFormat == "ndjson" {
+		if !streaming || outputFile != "" {
+			outputBytes, err = auditData.GetNDJSONOutput()
+		}
+	} else if outputFormat == "team-summary" {
+		outputBytes = []byte(validator.GetTeamSummaryPrettyOutput(auditData.GetTeamSummaries(config.GetTeams())))
+	} else if outputFormat == "team-summary-json" {
+		outputBytes, err = marshalJSON(auditData.GetTeamSummaries(config.GetTeams()))
+	} else if outputFormat == "app-summary" {
+		outputBytes = []byte(validator.GetAppSummaryPrettyOutput(auditData.GetAppSummaries(gitopsApps)))
+	} else if outputFormat == "app-summary-json" {
+		outputBytes, err = marshalJSON(auditData.GetAppSummaries(gitopsApps))
+	} else if outputFormat == "score-by-namespace" {
+		outputBytes = []byte(validator.GetNamespaceSummaryPrettyOutput(auditData.GetNamespaceSummaries()))
+	} else if outputFormat == "score-by-namespace-json" {
+		outputBytes, err = marshalJSON(auditData.GetNamespaceSummaries())
+	} else if outputFormat == "compliance" {
+		outputBytes = []byte(auditData.GetComplianceReport(complianceFramework, config.GetControlChecks(complianceFramework.ID)).GetPrettyOutput())
+	} else if outputFormat == "compliance-json" {
+		outputBytes, err = marshalJSON(auditData.GetComplianceReport(complianceFramework, config.GetControlChecks(complianceFramework.ID)))
+	} else if outputFormat == "failed-checks" {
+		outputBytes, err = marshalJSON(auditData.GetFailedChecks())
+	} else if outputFormat == "runbook" {
+		outputBytes = []byte(validator.GetRunbookPrettyOutput(auditData.GetRunbook(config.GetRemediations())))
+	} else if outputFormat == "runbook-json" {
+		outputBytes, err = marshalJSON(auditData.GetRunbook(config.GetRemediations()))
+	} else if outputFormat == "sarif" {
+		outputBytes, err = marshalJSON(auditData.GetSARIFOutput(config, version))
+	} else if outputFormat == "heatmap" {
+		heatmap := auditData.GetHeatmap()
+		if heatmap.FitsGrid() {
+			outputBytes = []byte(heatmap.GetPrettyOutput())
+		} else {
+			logrus.Warnf("The heatmap has %d namespaces and %d checks, which is too large for a grid, writing it as JSON", len(heatmap.Namespaces), len(heatmap.Checks))
+			outputBytes, err = marshalJSON(heatmap)
+		}
+	} else if outputFormat == "heatmap-json" {
+		outputBytes, err = marshalJSON(auditData.GetHeatmap())
+	} else if outputFormat == "prometheus" {
+		outputBytes = []byte(auditData.GetPrometheusOutput())
+	} else if outputFormat == "csv" {
+		outputBytes, err = auditData.GetCSVOutput()
+	} else if outputFormat == "html" {
+		outputBytes, err = auditData.GetHTMLOutput(reportBranding)
+	} else if outputFormat == "junit" {
+		outputBytes, err = xml.MarshalIndent(auditData.GetJUnitOutput(config.GetRemediations()), "", "  ")
+		outputBytes = append([]byte(xml.Header), append(outputBytes, '\n')...)
+	} else {
+		if auditedLocalFiles {
+			auditData.FileIndex = auditData.GetFileIndex()
+		}
+		outputBytes, err = marshalJSON(auditData)
+	}
+	if err != nil {
+		logrus.Errorf("Error marshalling audit: %v", err)
+		os.Exit(1)
+	}
+	if outputURL == "" && outputFile == "" {
+		os.Stdout.Write(outputBytes)
+	} else {
+		if outputURL != "" {
+			contentType := "text/plain"
+			if outputFormat == "json" || outputFormat == "team-summary-json" || outputFormat == "app-summary-json" || outputFormat == "score-by-namespace-json" || outputFormat == "compliance-json" || outputFormat == "failed-checks" || outputFormat == "runbook-json" || outputFormat == "sarif" || outputFormat == "heatmap-json" {
+				contentType = "application/json"
+			} else if outputFormat == "yaml" {
+				contentType = "application/x-yaml"
+			} else if outputFormat == "ndjson" {
+				contentType = "application/x-ndjson"
+			} else if outputFormat == "junit" {
+				contentType = "application/xml"
+			} else if outputFormat == "prometheus" {
+				contentType = validator.PrometheusContentType
+			} else if outputFormat == "csv" {
+				contentType = validator.CSVContentType
+			} else if outputFormat == "html" {
+				contentType = validator.HTMLContentType
+			}
+			if streaming {
+				reader, writer := io.Pipe()
+				go func() {
+					writer.CloseWithError(auditData.WriteNDJSON(writer))
+				}()
+				postOutput(outputURL, contentType, "", reader)
+			} else {
+				if streamOutputURL {
+					logrus.Warnf("Streaming to --output-url is only supported for the ndjson format, sending %s output in a single request", outputFormat)
+				}
+				body, contentEncoding := outputBytes, ""
+				if compressOutputURL && (outputFormat == "json" || outputFormat == "yaml") {
+					body, err = gzipOutput(outputBytes)
+					if err != nil {
+						logrus.Errorf("Error compressing output: %v", err)
+						os.Exit(1)
+					}
+					contentEncoding = "gzip"
+				}
+				postOutput(outputURL, contentType, contentEncoding, bytes.NewBuffer(body))
+			}
+		}
+
+		if outputFile != "" {
+			err := os.WriteFile(outputFile, outputBytes, 0644)
+			if err != nil {
+				logrus.Errorf("Error writing output to file: %v", err)
+				os.Exit(1)
+			}
+			if signingKey != nil {
+				err = os.WriteFile(signatureFile, signing.Sign(outputBytes, signingKey), 0644)
+				if err != nil {
+					logrus.Errorf("Error writing signature to file: %v", err)
+					os.Exit(1)
+				}
+			}
+		}
+	}
+}
+
+// gzipOutput compresses the audit output for --output-compress
+func gzipOutput(output []byte) ([]byte, error) {
+	buf := bytes.Buffer{}
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(output); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newHTTPClient returns a client for outbound requests which skips certificate verification with --skip-ssl-validation
+func newHTTPClient() *http.Client {
+	return &http.Client{Transport: newRetryTransport()}
+}
+
+// newRequestHTTPClient returns a client for requests without large uploads, e.g. fetching config or logging in.
+// --http-timeout limits each attempt until its response body has been read, so a stalled body can't hang.
+func newRequestHTTPClient() *http.Client {
+	transport := newRetryTransport()
+	transport.AttemptTimeout = httpTimeout
+	return &http.Client{Transport: transport}
+}
+
+// newRetryTransport returns the transport of newHTTPClient, which retries rate-limited requests
+func newRetryTransport() *retry.Transport {
+	transport := newHTTPTransport()
+	if skipSslValidation {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return retry.NewTransport(transport, httpMaxRetryWait)
+}
+
+// postOutput sends the audit output to outputURL. The body is sent with chunked transfer encoding
+// when its length isn't known up front.
+func postOutput(outputURL, contentType, contentEncoding string, body io.Reader) {
+	req, err := http.NewRequest("POST", outputURL, body)
+	if err != nil {
+		logrus.Errorf("Error building request for output: %v", err)
+		os.Exit(1)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+
+	resp, err := newHTTPClient().Do(req)
+	if err != nil {
+		logrus.Errorf("Error making request for output: %v", err)
+		os.Exit(1)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		logrus.Errorf("Error reading response: %v", err)
+		os.Exit(1)
+	}
+
+	logrus.Infof("Received response: %v", respBody)
 }
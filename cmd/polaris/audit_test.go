@@ -0,0 +1,90 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+// runAudit runs the audit command with args, and resets the flags afterwards since they're package variables
+func runAudit(t *testing.T, args ...string) {
+	t.Cleanup(func() {
+		for _, flags := range []*pflag.FlagSet{rootCmd.PersistentFlags(), auditCmd.PersistentFlags()} {
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+					assert.NoError(t, sliceValue.Replace(nil))
+				} else {
+					assert.NoError(t, flag.Value.Set(flag.DefValue))
+				}
+				flag.Changed = false
+			})
+		}
+	})
+	rootCmd.SetArgs(append([]string{"audit"}, args...))
+	assert.NoError(t, rootCmd.Execute())
+}
+
+func TestDryRunExitReason(t *testing.T) {
+	dir := t.TempDir()
+	reasonPath := filepath.Join(dir, "reason.json")
+	assert.NoError(t, os.WriteFile(reasonPath, []byte(`{"ExitCode": 3, "Reason": "dangers"}`), 0644))
+	manifestPath := filepath.Join(dir, "deploy.yaml")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"), 0644))
+
+	runAudit(t, "--dry-run", "--quiet", "--audit-path", manifestPath, "--exit-reason-file", reasonPath)
+
+	contents, err := os.ReadFile(reasonPath)
+	assert.NoError(t, err)
+	reason := exitReason{}
+	assert.NoError(t, json.Unmarshal(contents, &reason))
+	assert.Equal(t, 0, reason.ExitCode)
+	assert.Equal(t, exitReasonClean, reason.Reason)
+	assert.Empty(t, reason.Failures)
+}
+
+func TestMinPriorityOnlyFiltersOutput(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, os.WriteFile(configPath, []byte("checks:\n  tagNotSpecified: danger\npriorities:\n  tagNotSpecified: nice-to-have\n"), 0644))
+	manifestPath := filepath.Join(dir, "pod.yaml")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: api\nspec:\n  containers:\n  - name: api\n    image: api\n"), 0644))
+	outputPath := filepath.Join(dir, "audit.json")
+	reasonPath := filepath.Join(dir, "reason.json")
+
+	runAudit(t, "--quiet", "--config", configPath, "--audit-path", manifestPath, "--min-priority", "must-fix",
+		"--format", "json", "--output-file", outputPath, "--exit-reason-file", reasonPath)
+
+	contents, err := os.ReadFile(outputPath)
+	assert.NoError(t, err)
+	auditData := validator.AuditData{}
+	assert.NoError(t, json.Unmarshal(contents, &auditData))
+	assert.Equal(t, uint(0), auditData.GetSummary().Dangers, "nice-to-have failures should be left out of the output")
+
+	contents, err = os.ReadFile(reasonPath)
+	assert.NoError(t, err)
+	reason := exitReason{}
+	assert.NoError(t, json.Unmarshal(contents, &reason))
+	assert.Equal(t, uint(1), reason.Dangers, "the exit code should still count nice-to-have failures")
+	assert.Equal(t, reason.Score, auditData.Score)
+	assert.Less(t, auditData.Score, uint(100))
+}
@@ -25,7 +25,7 @@ var loginCmd = &cobra.Command{
 	Short: "Authenticate polaris with Fairwinds Insights.",
 	Long:  `Authenticate polaris with Fairwinds Insights.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := auth.HandleLogin(insightsHost)
+		err := auth.HandleLogin(newRequestHTTPClient(), insightsHost)
 		if err != nil {
 			logrus.Fatal(err)
 		}
@@ -55,7 +55,7 @@ var statusCmd = &cobra.Command{
 	Short: "View authentication status.",
 	Long:  `View authentication status.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := auth.PrintStatus(insightsHost)
+		err := auth.PrintStatus(newRequestHTTPClient(), insightsHost)
 		if err != nil {
 			logrus.Fatalf("printing status: %v", err)
 		}
@@ -0,0 +1,84 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	rootCmd.AddCommand(checkConfigCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with Polaris configuration files.",
+	Long:  `Work with Polaris configuration files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logrus.Error("You must specify a sub-command.")
+		err := cmd.Help()
+		if err != nil {
+			logrus.Error(err)
+		}
+		os.Exit(1)
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema of the configuration file.",
+	Long:  `Print a JSON Schema of the configuration file, e.g. for editors to validate and complete config files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		schema, err := json.MarshalIndent(conf.GetJSONSchema(), "", "  ")
+		if err != nil {
+			logrus.Errorf("Error marshalling the config schema: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(schema))
+	},
+}
+
+var checkConfigCmd = &cobra.Command{
+	Use:   "check-config",
+	Short: "Check configuration files for problems.",
+	Long:  `Check the configuration files passed with --config for unknown keys, checks which don't exist, invalid severities, and custom check schemas which don't parse, e.g. before deploying them to the admission controller.`,
+	// the config is validated here rather than parsed like other commands, to report every problem with it
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		configureLogging()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(configPaths) == 0 {
+			logrus.Error("check-config requires --config")
+			os.Exit(1)
+		}
+		problems := conf.ValidateFilesWithClient(newRequestHTTPClient(), configPaths)
+		for _, problem := range problems {
+			logrus.Error(problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		logrus.Infof("The config at %s is valid", strings.Join(configPaths, ", "))
+	},
+}
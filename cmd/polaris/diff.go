@@ -0,0 +1,65 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffIgnoreFields []string
+	diffOutputFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.PersistentFlags().StringArrayVar(&diffIgnoreFields, "ignore-field", []string{}, "Field to exclude from the comparison, e.g. AuditTime, Score, or Message. Can be repeated.")
+	diffCmd.PersistentFlags().StringVarP(&diffOutputFormat, "format", "f", "pretty", "Output format for the diff - json or pretty.")
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [before audit file] [after audit file]",
+	Short: "Compare the findings of two audits.",
+	Long:  `Compare two audits saved as JSON or YAML, and list the findings that were added, removed, or changed.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		before := validator.ReadAuditFromFile(args[0])
+		after := validator.ReadAuditFromFile(args[1])
+		diff, err := validator.DiffAudits(before, after, diffIgnoreFields)
+		if err != nil {
+			logrus.Errorf("Error comparing audits: %v", err)
+			os.Exit(1)
+		}
+		if diffOutputFormat == "json" {
+			outputBytes, err := json.MarshalIndent(diff, "", "  ")
+			if err != nil {
+				logrus.Errorf("Error marshalling diff: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(outputBytes))
+		} else {
+			fmt.Print(diff.GetPrettyOutput())
+		}
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
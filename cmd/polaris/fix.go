@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/fairwindsops/polaris/pkg/kube"
@@ -36,12 +37,16 @@ var (
 	checksToFix []string
 	fixAll      bool
 	isTemplate  bool
+	fixDryRun   bool
+	fixOutput   string
 )
 
 func init() {
 	rootCmd.AddCommand(fixCommand)
 	fixCommand.PersistentFlags().StringVar(&filesPath, "files-path", "", "mutate and fix one or more YAML files in a specified folder")
 	fixCommand.PersistentFlags().BoolVar(&isTemplate, "template", false, "set to true when modifyng a YAML template, like a Helm chart (experimental)")
+	fixCommand.PersistentFlags().BoolVar(&fixDryRun, "fix-dry-run", false, "Write the fixes as a unified diff instead of modifying the files. Each hunk is annotated with the checks that changed it.")
+	fixCommand.PersistentFlags().StringVar(&fixOutput, "fix-output", "", "Destination file for the diff written with --fix-dry-run. Defaults to stdout.")
 	fixCommand.PersistentFlags().StringSliceVar(&checksToFix, "checks", []string{}, "Optional flag to specify specific checks to fix eg. checks=hostIPCSet,hostPIDSet and checks=all applies fix to all defined checks mutations")
 }
 
@@ -57,6 +62,10 @@ var fixCommand = &cobra.Command{
 			cmd.Help()
 			os.Exit(1)
 		}
+		if fixOutput != "" && !fixDryRun {
+			logrus.Error("--fix-output can only be used with --fix-dry-run")
+			os.Exit(1)
+		}
 		var yamlFiles []string
 		fileInfo, err := os.Stat(filesPath)
 		if err != nil {
@@ -91,42 +100,50 @@ var fixCommand = &cobra.Command{
 			}
 		}
 
+		patch := ""
 		for _, fullFilePath := range yamlFiles {
 			yamlContent, err := os.ReadFile(fullFilePath)
 			if err != nil {
 				logrus.Fatalf("Error reading file with file path %s: %v", fullFilePath, err)
 			}
-
-			if err != nil {
-				logrus.Fatalf("Error marshalling %s: %v", fullFilePath, err)
-			}
+			originalYamlContent := string(yamlContent)
 
 			if isTemplate {
 				yamlContent = []byte(detemplate(string(yamlContent)))
 			}
-			kubeResources := kube.CreateResourceProviderFromYaml(string(yamlContent))
+			kubeResources := kube.CreateResourceProviderFromFileContents(fullFilePath, string(yamlContent))
 			results, err := validator.ApplyAllSchemaChecksToResourceProvider(&config, kubeResources)
 			if err != nil {
 				logrus.Fatalf("Error applying schema check to the resources %s: %v", fullFilePath, err)
 			}
 			allMutations := mutation.GetMutationsFromResults(results)
+			mutatedChecks := map[string][]string{}
+			for _, result := range results {
+				key := fmt.Sprintf("%s/%s/%s", result.Kind, result.Name, result.Namespace)
+				mutatedChecks[key] = mutation.GetMutatedChecksFromResult(&result)
+			}
 
 			updatedYamlContent := ""
+			annotations := []mutation.DiffAnnotation{}
 			if len(allMutations) > 0 {
-				for _, resources := range kubeResources.Resources {
-					for _, resource := range resources {
-						key := fmt.Sprintf("%s/%s/%s", resource.Kind, resource.Resource.GetName(), resource.Resource.GetNamespace())
-						mutations := allMutations[key]
-						mutatedYamlContent, err := mutation.ApplyAllMutations(string(resource.OriginalObjectYAML), mutations)
-						if err != nil {
-							logrus.Errorf("Error applying schema mutations to the resource %s: %v", key, err)
-							os.Exit(1)
-						}
-						if updatedYamlContent != "" {
-							updatedYamlContent += "\n---\n"
-						}
-						updatedYamlContent += mutatedYamlContent
+				for _, resource := range getResourcesInFileOrder(kubeResources) {
+					key := fmt.Sprintf("%s/%s/%s", resource.Kind, resource.Resource.GetName(), resource.Resource.GetNamespace())
+					mutations := allMutations[key]
+					mutatedYamlContent, err := mutation.ApplyAllMutations(string(resource.OriginalObjectYAML), mutations)
+					if err != nil {
+						logrus.Errorf("Error applying schema mutations to the resource %s: %v", key, err)
+						os.Exit(1)
+					}
+					if updatedYamlContent != "" {
+						updatedYamlContent += "\n---\n"
 					}
+					firstLine := strings.Count(updatedYamlContent, "\n") + 1
+					updatedYamlContent += mutatedYamlContent
+					annotations = append(annotations, mutation.DiffAnnotation{
+						FirstLine: firstLine,
+						LastLine:  strings.Count(updatedYamlContent, "\n"),
+						Checks:    mutatedChecks[key],
+					})
 				}
 			}
 
@@ -134,17 +151,44 @@ var fixCommand = &cobra.Command{
 				updatedYamlContent = retemplate(updatedYamlContent)
 			}
 
-			if updatedYamlContent != "" {
-				err = os.WriteFile(fullFilePath, []byte(updatedYamlContent), 0644)
-				if err != nil {
-					logrus.Fatalf("Error writing output to file: %v", err)
-				}
+			if updatedYamlContent == "" {
+				continue
+			}
+			if fixDryRun {
+				patch += mutation.GetUnifiedDiff(filepath.ToSlash(filepath.Clean(fullFilePath)), originalYamlContent, updatedYamlContent, annotations)
+				continue
+			}
+			err = os.WriteFile(fullFilePath, []byte(updatedYamlContent), 0644)
+			if err != nil {
+				logrus.Fatalf("Error writing output to file: %v", err)
 			}
 		}
 
+		if fixDryRun {
+			if fixOutput == "" {
+				fmt.Print(patch)
+				return
+			}
+			err = os.WriteFile(fixOutput, []byte(patch), 0644)
+			if err != nil {
+				logrus.Fatalf("Error writing diff to file: %v", err)
+			}
+		}
 	},
 }
 
+// getResourcesInFileOrder returns the resources in the order they're defined in their file
+func getResourcesInFileOrder(kubeResources *kube.ResourceProvider) []kube.GenericResource {
+	resources := []kube.GenericResource{}
+	for _, kindResources := range kubeResources.Resources {
+		resources = append(resources, kindResources...)
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].SourceLine < resources[j].SourceLine
+	})
+	return resources
+}
+
 func detemplate(content string) string {
 	lines := strings.Split(content, "\n")
 	for idx, line := range lines {
@@ -0,0 +1,200 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fairwindsops/polaris/pkg/gitops"
+	"github.com/fairwindsops/polaris/pkg/kube"
+	"github.com/fairwindsops/polaris/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// auditGitOpsRepo renders and audits every app in a GitOps repository, tagging each result with its app
+func auditGitOpsRepo(ctx context.Context, repoDir string) (validator.AuditData, error) {
+	apps, err := gitops.Discover(repoDir)
+	if err != nil {
+		return validator.AuditData{}, err
+	}
+	if len(apps) == 0 {
+		return validator.AuditData{}, fmt.Errorf("no Argo CD Applications or Flux Kustomizations found in %s", repoDir)
+	}
+	var auditData validator.AuditData
+	for idx, app := range apps {
+		appAudit, err := auditGitOpsApp(ctx, app)
+		if err != nil {
+			return auditData, fmt.Errorf("auditing %s %s: %w", app.Kind, app.Name, err)
+		}
+		logrus.WithFields(logrus.Fields{
+			"app":       app.Name,
+			"kind":      app.Kind,
+			"renderer":  app.Renderer,
+			"resources": len(appAudit.Results),
+			"score":     appAudit.Score,
+		}).Info("Audited GitOps app")
+		gitopsApps = append(gitopsApps, app.Name)
+		if idx == 0 {
+			auditData = appAudit
+			continue
+		}
+		auditData.Results = append(auditData.Results, appAudit.Results...)
+		auditData.ClusterInfo.Namespaces += appAudit.ClusterInfo.Namespaces
+		auditData.ClusterInfo.Controllers += appAudit.ClusterInfo.Controllers
+	}
+	auditData.SourceType = "GitOps"
+	auditData.SourceName = repoDir
+	auditData.DisplayName = config.DisplayName
+	if auditData.DisplayName == "" {
+		auditData.DisplayName = repoDir
+	}
+	auditData.Score = auditData.GetSummary().GetScore()
+	return auditData, nil
+}
+
+func auditGitOpsApp(ctx context.Context, app gitops.App) (validator.AuditData, error) {
+	appDir, err := renderGitOpsApp(app)
+	if err != nil {
+		return validator.AuditData{}, err
+	}
+	if appDir != app.Path {
+		defer os.RemoveAll(appDir)
+	}
+	appConfig := config
+	if appConfig.DefaultNamespace == "" {
+		appConfig.DefaultNamespace = app.Namespace
+	}
+	k, err := kube.CreateResourceProvider(ctx, appDir, "", appConfig)
+	if err != nil {
+		return validator.AuditData{}, err
+	}
+	appAudit, err := validator.RunAudit(appConfig, k)
+	if err != nil {
+		return validator.AuditData{}, err
+	}
+	return appAudit.SetApp(app.Name), nil
+}
+
+// renderGitOpsApp returns a directory with the app's rendered manifests. Plain directories are
+// audited in place, while helm and kustomize output is written to a new temporary directory.
+func renderGitOpsApp(app gitops.App) (string, error) {
+	switch app.Renderer {
+	case gitops.RendererHelm:
+		valueFiles := []string{}
+		for _, valueFile := range app.ValueFiles {
+			valueFiles = append(valueFiles, filepath.Join(app.Path, valueFile))
+		}
+		return ProcessHelmTemplates(app.Path, HelmTemplateOptions{
+			Values:      strings.Join(valueFiles, ","),
+			Namespace:   app.Namespace,
+			KubeVersion: helmKubeVersion,
+			APIVersions: helmAPIVersions,
+			Timeout:     helmTimeout,
+		})
+	case gitops.RendererKustomize:
+		return ProcessKustomization(app.Path, kustomizeEnableHelm, helmTimeout)
+	}
+	return app.Path, nil
+}
+
+// ProcessKustomization renders a kustomization into a temporary directory with kustomize build.
+// With enableHelm, helm charts referenced by the kustomization are inflated.
+func ProcessKustomization(path string, enableHelm bool, timeout time.Duration) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	dir, err := os.MkdirTemp("", "*")
+	if err != nil {
+		return "", err
+	}
+	args := []string{"build", path, "--output", filepath.Join(dir, "rendered.yaml")}
+	if enableHelm {
+		args = append(args, "--enable-helm")
+	}
+	cmd := exec.CommandContext(ctx, "kustomize", args...)
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+		if ctx.Err() == context.DeadlineExceeded {
+			logrus.Error(string(output))
+			return "", fmt.Errorf("kustomize build timed out after %s", timeout)
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("kustomize isn't installed: %w", err)
+		}
+		// kustomize explains what's wrong with the kustomization on stderr, which is clearer than its exit status
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("kustomize build failed: %s", message)
+	}
+	return dir, nil
+}
+
+// kustomizeAuditPath returns true if --audit-path should be rendered with kustomize build. Rendering is opt-in with
+// --kustomize, so what's audited doesn't depend on whether kustomize happens to be installed.
+func kustomizeAuditPath() bool {
+	if kustomize {
+		logrus.Infof("Rendering the kustomization in %s with kustomize build", auditPath)
+		return true
+	}
+	if auditPath == "" || auditPath == "-" || len(auditPaths) > 1 || helmChart != "" || gitops.IsRemote(auditPath) || kube.IsOCIArtifact(auditPath) {
+		return false
+	}
+	if gitops.DetectRenderer(auditPath) == gitops.RendererKustomize {
+		logrus.Warnf("%s has a kustomization, which is only rendered with --kustomize, so its files are audited as they are", auditPath)
+	}
+	return false
+}
+
+// getGitRemote returns the repository to audit from --git-repo, or from a git:: --audit-path
+func getGitRemote() (gitops.Remote, error) {
+	if gitRepo != "" {
+		return gitops.NewRemote(gitRepo, gitPath, gitRef)
+	}
+	return gitops.ParseRemote(auditPath)
+}
+
+// ProcessGitRemote clones a remote repository and renders its subpath like a GitOps app. It returns the
+// clone, which the caller should remove, and the directory with the manifests to audit.
+func ProcessGitRemote(remote gitops.Remote, timeout time.Duration) (string, string, error) {
+	cloneDir, err := remote.Clone(timeout)
+	if err != nil {
+		return "", "", fmt.Errorf("cloning %s: %w", remote.URL, err)
+	}
+	dir := remote.Path(cloneDir)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		os.RemoveAll(cloneDir)
+		return "", "", fmt.Errorf("%s is not a directory in %s", remote.Subpath, remote.URL)
+	}
+	manifestDir, err := renderGitOpsApp(gitops.App{Path: dir, Renderer: gitops.DetectRenderer(dir)})
+	if err != nil {
+		os.RemoveAll(cloneDir)
+		return "", "", fmt.Errorf("rendering %s: %w", remote, err)
+	}
+	return cloneDir, manifestDir, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fairwindsops/polaris/pkg/kube"
+	"github.com/fairwindsops/polaris/pkg/results"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resultsSince         string
+	resultsOutputFormat  string
+	resultsListNamespace string
+)
+
+func init() {
+	rootCmd.AddCommand(resultsCmd)
+	resultsCmd.AddCommand(resultsListCmd)
+	resultsListCmd.PersistentFlags().StringVar(&resultsSince, "since", "", "Only list audits within this time window, e.g. 7d or 12h.")
+	resultsListCmd.PersistentFlags().StringVarP(&resultsOutputFormat, "format", "f", "pretty", "Output format - json or pretty.")
+	resultsListCmd.PersistentFlags().StringVar(&resultsListNamespace, "results-namespace", results.DefaultNamespace, "Namespace where AuditResult resources are stored.")
+}
+
+var resultsCmd = &cobra.Command{
+	Use:   "results",
+	Short: "Query audit results stored in the cluster.",
+	Long:  `Query audit results stored in the cluster with audit --store-results.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logrus.Error("You must specify a sub-command.")
+		err := cmd.Help()
+		if err != nil {
+			logrus.Error(err)
+		}
+		os.Exit(1)
+	},
+}
+
+var resultsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored audits and their scores.",
+	Long:  `List stored audits and their scores, oldest first.`,
+	// the list is read by scripts, so it isn't followed by the banner
+	Annotations: map[string]string{noBannerAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.TODO()
+		dynamicClient, _, _, _, err := kube.GetKubeClient(ctx, config.Kubeconfig, config.KubeContext)
+		if err != nil {
+			logrus.Errorf("Error connecting to the cluster: %v", err)
+			os.Exit(1)
+		}
+		stored, err := results.NewStore(dynamicClient, resultsListNamespace).List(ctx)
+		if err != nil {
+			logrus.Errorf("Error listing audit results: %v", err)
+			os.Exit(1)
+		}
+		if resultsSince != "" {
+			window, err := results.ParseSince(resultsSince)
+			if err != nil {
+				logrus.Error(err)
+				os.Exit(1)
+			}
+			stored = results.FilterSince(stored, time.Now().Add(-window))
+		}
+		if resultsOutputFormat == "json" {
+			outputBytes, err := json.MarshalIndent(stored, "", "  ")
+			if err != nil {
+				logrus.Errorf("Error marshalling audit results: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(outputBytes))
+		} else {
+			fmt.Print(results.GetPrettyOutput(stored))
+		}
+	},
+}
@@ -15,24 +15,39 @@
 package cmd
 
 import (
+	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	conf "github.com/fairwindsops/polaris/pkg/config"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	configPath                   string
+	configPaths                  []string
+	configURL                    string
+	configProfile                string
+	checksDir                    string
 	disallowExemptions           bool
 	disallowConfigExemptions     bool
 	disallowAnnotationExemptions bool
 	fixChecks                    bool
 	logLevel                     string
+	logFormat                    string
+	quiet                        bool
 	auditPath                    string
 	displayName                  string
 	kubeContext                  string
+	kubeconfig                   string
 	insightsHost                 string
+	httpTimeout                  time.Duration
+	httpConnectTimeout           time.Duration
+	httpMaxRetryWait             time.Duration
 )
 
 var (
@@ -41,39 +56,73 @@ var (
 
 func init() {
 	// Flags
-	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "Location of Polaris configuration file.")
-	rootCmd.PersistentFlags().StringVarP(&kubeContext, "context", "x", "", "Set the kube context.")
+	rootCmd.PersistentFlags().StringArrayVarP(&configPaths, "config", "c", nil, "Location of Polaris configuration file. Can be repeated to merge several files, with later files taking precedence.")
+	rootCmd.PersistentFlags().StringVar(&configURL, "config-url", "", "URL of a Polaris configuration file to fetch, e.g. from an artifact server. The Authorization header is set from "+conf.AuthorizationEnvVar+". Ignored if --config is set.")
+	rootCmd.PersistentFlags().StringVarP(&kubeContext, "context", "x", "", "Name of the kubeconfig context to audit. Can also be given as --kube-context.")
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster and not the default kubeconfig.")
+	rootCmd.PersistentFlags().StringVar(&checksDir, "checks-dir", "", "Directory of custom checks to add to the configuration, one per .yaml file. They replace built-in checks with the same ID.")
+	rootCmd.PersistentFlags().StringVar(&configProfile, "profile", "", "Name of a profile from the configuration file to apply, e.g. strict.")
 	rootCmd.PersistentFlags().BoolVarP(&disallowExemptions, "disallow-exemptions", "", false, "Disallow any configured exemption.")
 	rootCmd.PersistentFlags().BoolVarP(&disallowConfigExemptions, "disallow-config-exemptions", "", false, "Disallow exemptions set within the configuration file.")
 	rootCmd.PersistentFlags().BoolVarP(&disallowAnnotationExemptions, "disallow-annotation-exemptions", "", false, "Disallow any exemption defined as a controller annotation.")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "", logrus.InfoLevel.String(), "Logrus log level to be output (trace, debug, info, warning, error, fatal, panic).")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of the logs, either text or json, e.g. for a centralized logging pipeline.")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log errors, e.g. when scripting. Results written to stdout or --output-file aren't affected.")
 	rootCmd.PersistentFlags().StringVar(&insightsHost, "insights-host", "https://insights.fairwinds.com", "Fairwinds Insights host URL")
+	rootCmd.PersistentFlags().DurationVar(&httpTimeout, "http-timeout", 30*time.Second, "Timeout for responses to outbound HTTP requests, e.g. to --output-url or Fairwinds Insights, after the request has been sent. Fetching config and logging in must also read the whole response within it. Set to 0 to disable.")
+	rootCmd.PersistentFlags().DurationVar(&httpConnectTimeout, "http-connect-timeout", 10*time.Second, "Timeout for establishing outbound HTTP connections. Set to 0 to disable.")
+	rootCmd.PersistentFlags().DurationVar(&httpMaxRetryWait, "http-max-retry-wait", time.Minute, "Maximum total time to wait before retrying outbound HTTP requests rate limited with a Retry-After header. Set to 0 to disable retries.")
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+}
+
+// flagAliases are alternative names accepted for flags, e.g. to match kubectl and helm
+var flagAliases = map[string]string{
+	"kube-context": "context",
+}
+
+// normalizeFlagName maps aliases to the flag they stand for
+func normalizeFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if alias, ok := flagAliases[name]; ok {
+		name = alias
+	}
+	return pflag.NormalizedName(name)
 }
 
 var config conf.Configuration
 
+// noBannerAnnotation is set to true on commands which shouldn't be followed by the Fairwinds Insights banner
+const noBannerAnnotation = "polaris.fairwinds.com/no-banner"
+
 var rootCmd = &cobra.Command{
 	Use:   "polaris",
 	Short: "polaris",
 	Long:  `Validation of best practices in your Kubernetes clusters.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		parsedLevel, err := logrus.ParseLevel(logLevel)
-		if err != nil {
-			logrus.Errorf("log-level flag has invalid value %s", logLevel)
+		configureLogging()
+
+		var err error
+
+		if configURL != "" && len(configPaths) > 0 {
+			logrus.Warnf("Both --config and --config-url are set, using the config at %s", strings.Join(configPaths, ", "))
+		}
+		if configURL != "" && len(configPaths) == 0 {
+			config, err = conf.ParseURL(newRequestHTTPClient(), configURL)
+			if err != nil {
+				logrus.Errorf("Error parsing config from %s: %v", configURL, err)
+				os.Exit(1)
+			}
 		} else {
-			logrus.SetLevel(parsedLevel)
+			config, err = conf.ParseFilesWithClient(newRequestHTTPClient(), configPaths)
+			if err != nil {
+				logrus.Errorf("Error parsing config at %s: %v", strings.Join(configPaths, ", "), err)
+				os.Exit(1)
+			}
 		}
-
-		config, err = conf.ParseFile(configPath)
+		err = applyConfigFlags(&config)
 		if err != nil {
-			logrus.Errorf("Error parsing config at %s: %v", configPath, err)
+			logrus.Errorf("Error applying config flags: %v", err)
 			os.Exit(1)
 		}
-
-		config.DisallowExemptions = disallowExemptions
-		config.DisallowConfigExemptions = disallowConfigExemptions
-		config.DisallowAnnotationExemptions = disallowAnnotationExemptions
-		config.KubeContext = kubeContext
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		logrus.Error("You must specify a sub-command.")
@@ -84,10 +133,66 @@ var rootCmd = &cobra.Command{
 		os.Exit(1)
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// the banner isn't a log line, so it's left out of JSON logs
+		if quiet || logFormat == "json" || cmd.Annotations[noBannerAnnotation] == "true" {
+			return
+		}
 		os.Stderr.WriteString("\n\nWant more? Automate Polaris for free with Fairwinds Insights!\n🚀 https://fairwinds.com/insights-signup/polaris 🚀 \n")
 	},
 }
 
+// applyConfigFlags applies the global flags which change the configuration, e.g. --profile
+func applyConfigFlags(c *conf.Configuration) error {
+	if checksDir != "" {
+		if err := c.LoadChecksDir(checksDir); err != nil {
+			return fmt.Errorf("loading checks from %s: %w", checksDir, err)
+		}
+	}
+	if configProfile != "" {
+		if err := c.ApplyProfile(configProfile); err != nil {
+			return err
+		}
+	}
+	c.DisallowExemptions = disallowExemptions
+	c.DisallowConfigExemptions = disallowConfigExemptions
+	c.DisallowAnnotationExemptions = disallowAnnotationExemptions
+	c.KubeContext = kubeContext
+	c.Kubeconfig = kubeconfig
+	return nil
+}
+
+// configureLogging applies the --log-format, --log-level, and --quiet flags
+func configureLogging() {
+	switch logFormat {
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	case "text":
+	default:
+		logrus.Errorf("log-format flag has invalid value %s, it should be text or json", logFormat)
+	}
+	parsedLevel, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		logrus.Errorf("log-level flag has invalid value %s", logLevel)
+	} else {
+		logrus.SetLevel(parsedLevel)
+	}
+	if quiet && logrus.GetLevel() > logrus.ErrorLevel {
+		logrus.SetLevel(logrus.ErrorLevel)
+	}
+}
+
+// newHTTPTransport returns a transport for outbound requests with the timeout flags applied. --http-timeout
+// limits the wait for the response once the request is sent, so large uploads aren't cut off.
+func newHTTPTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   httpConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.ResponseHeaderTimeout = httpTimeout
+	return transport
+}
+
 // Execute the stuff
 func Execute(VERSION string) {
 	version = VERSION
@@ -0,0 +1,43 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// getBanner returns what the root command writes to stderr after cmd runs
+func getBanner(t *testing.T, cmd *cobra.Command) string {
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = writer
+	rootCmd.PersistentPostRun(cmd, nil)
+	os.Stderr = stderr
+	assert.NoError(t, writer.Close())
+	banner, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(banner)
+}
+
+func TestBanner(t *testing.T) {
+	assert.Contains(t, getBanner(t, auditCmd), "Fairwinds Insights")
+	assert.Empty(t, getBanner(t, resultsListCmd))
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fairwindsops/polaris/pkg/signing"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var verifyKey string
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.PersistentFlags().StringVar(&verifyKey, "key", "", "PEM-encoded ed25519 public key (or the signing key) used to verify the signature.")
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [results file] [signature file]",
+	Short: "Verify the signature of audit results.",
+	Long:  `Verify that audit results written with --sign have not been altered.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if verifyKey == "" {
+			logrus.Error("Please specify a --key to verify the signature with")
+			os.Exit(1)
+		}
+		keyBytes, err := os.ReadFile(verifyKey)
+		if err != nil {
+			logrus.Errorf("Error reading key: %v", err)
+			os.Exit(1)
+		}
+		publicKey, err := signing.ParsePublicKey(keyBytes)
+		if err != nil {
+			logrus.Errorf("Error parsing key: %v", err)
+			os.Exit(1)
+		}
+		results, err := os.ReadFile(args[0])
+		if err != nil {
+			logrus.Errorf("Error reading results: %v", err)
+			os.Exit(1)
+		}
+		signature, err := os.ReadFile(args[1])
+		if err != nil {
+			logrus.Errorf("Error reading signature: %v", err)
+			os.Exit(1)
+		}
+		err = signing.Verify(results, signature, publicKey)
+		if err != nil {
+			logrus.Errorf("Verification failed: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println("Signature verified")
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
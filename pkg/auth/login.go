@@ -42,7 +42,8 @@ type Host struct {
 
 var paramsOrErrorChan = make(chan paramsOrError)
 
-func HandleLogin(insightsHost string) error {
+// HandleLogin authenticates with Fairwinds Insights, sending requests to it with client
+func HandleLogin(client *http.Client, insightsHost string) error {
 	if _, err := os.Stat(polarisHostsFilepath); err == nil {
 		content, err := readPolarisHostsFile()
 		if err != nil {
@@ -51,7 +52,7 @@ func HandleLogin(insightsHost string) error {
 
 		if len(content) > 0 {
 			if h, ok := content[insightsHost]; ok {
-				c := insights.NewHTTPClient(insightsHost, h.Organization, h.Token)
+				c := insights.NewHTTPClientWithClient(client, insightsHost, h.Organization, h.Token)
 				isValid, err := c.IsTokenValid()
 				if err != nil {
 					return err
@@ -101,7 +102,7 @@ func HandleLogin(insightsHost string) error {
 		var router *mux.Router
 		go func() {
 			router = mux.NewRouter()
-			router.HandleFunc("/auth/login/callback", callbackHandler(insightsHost, localServerPort))
+			router.HandleFunc("/auth/login/callback", callbackHandler(client, insightsHost, localServerPort))
 			if err := http.Serve(listener, router); err != nil {
 				paramsOrErrorChan <- paramsOrError{err: fmt.Errorf("starting the local http server: %w", err)}
 			}
@@ -120,7 +121,7 @@ func HandleLogin(insightsHost string) error {
 	} else {
 		var answer string
 		var bot bot
-		err := survey.AskOne(&survey.Password{Message: "Paste your authentication token:"}, &answer, survey.WithValidator(validateToken(insightsHost, &bot)))
+		err := survey.AskOne(&survey.Password{Message: "Paste your authentication token:"}, &answer, survey.WithValidator(validateToken(client, insightsHost, &bot)))
 		if err != nil {
 			return fmt.Errorf("asking how to authenticate: %w", err)
 		}
@@ -153,7 +154,7 @@ func HandleLogin(insightsHost string) error {
 	return nil
 }
 
-func fetchAuthToken(insightsHost, organization, code string) (string, error) {
+func fetchAuthToken(client *http.Client, insightsHost, organization, code string) (string, error) {
 	authTokenURL := fmt.Sprintf("%s/v0/organizations/%s/auth/token", insightsHost, organization)
 	body := map[string]any{"grantType": "authorization_code", "code": code}
 	b, err := json.Marshal(body)
@@ -167,7 +168,7 @@ func fetchAuthToken(insightsHost, organization, code string) (string, error) {
 	}
 	r.Header.Add("Content-Type", "application/json")
 
-	res, err := http.DefaultClient.Do(r)
+	res, err := client.Do(r)
 	if err != nil {
 		return "", err
 	}
@@ -191,7 +192,7 @@ func fetchAuthToken(insightsHost, organization, code string) (string, error) {
 	return token, nil
 }
 
-func callbackHandler(insightsHost string, localServerPort int) func(w http.ResponseWriter, r *http.Request) {
+func callbackHandler(client *http.Client, insightsHost string, localServerPort int) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// checks for error in the params
 		errMsg := r.URL.Query().Get("error")
@@ -215,7 +216,7 @@ func callbackHandler(insightsHost string, localServerPort int) func(w http.Respo
 		if len(organization) == 0 {
 			err = errors.New("organization query param is required in callback")
 		}
-		token, err := fetchAuthToken(insightsHost, organization, code)
+		token, err := fetchAuthToken(client, insightsHost, organization, code)
 		if err != nil {
 			err = fmt.Errorf("fetching auth token: %w", err)
 		}
@@ -231,7 +232,7 @@ func callbackHandler(insightsHost string, localServerPort int) func(w http.Respo
 	}
 }
 
-func validateToken(insightsHost string, bot *bot) func(args any) error {
+func validateToken(client *http.Client, insightsHost string, bot *bot) func(args any) error {
 	return func(args any) error {
 		token, ok := args.(string)
 		if !ok {
@@ -240,7 +241,7 @@ func validateToken(insightsHost string, bot *bot) func(args any) error {
 		if len(strings.TrimSpace(token)) <= 0 {
 			return errors.New("token is required")
 		}
-		return fetchOrganizationBot(insightsHost, token, bot)
+		return fetchOrganizationBot(client, insightsHost, token, bot)
 	}
 }
 
@@ -253,7 +254,7 @@ type bot struct {
 	CreatedAt    time.Time
 }
 
-func fetchOrganizationBot(insightsHost, authToken string, bot *bot) error {
+func fetchOrganizationBot(client *http.Client, insightsHost, authToken string, bot *bot) error {
 	authTokenURL := fmt.Sprintf("%s/v0/bots/from-request", insightsHost)
 	r, err := http.NewRequest("GET", authTokenURL, nil)
 	if err != nil {
@@ -262,7 +263,7 @@ func fetchOrganizationBot(insightsHost, authToken string, bot *bot) error {
 	r.Header.Add("Content-Type", "application/json")
 	r.Header.Add("Authorization", "Bearer "+authToken)
 
-	res, err := http.DefaultClient.Do(r)
+	res, err := client.Do(r)
 	if err != nil {
 		return err
 	}
@@ -2,16 +2,18 @@ package auth
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/fairwindsops/polaris/pkg/insights"
 )
 
-func PrintStatus(insightsHost string) error {
+// PrintStatus prints whether the token for the host is still valid, checking it with client
+func PrintStatus(client *http.Client, insightsHost string) error {
 	if content, err := readPolarisHostsFile(); err == nil {
 		if len(content) > 0 {
 			if h, ok := content[insightsHost]; ok {
-				c := insights.NewHTTPClient(insightsHost, h.Organization, h.Token)
+				c := insights.NewHTTPClientWithClient(client, insightsHost, h.Organization, h.Token)
 				isValid, err := c.IsTokenValid()
 				if err != nil {
 					return err
@@ -0,0 +1,68 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+// RequiredAnnotations configures annotations which resources of a kind must have, e.g. ones an ingress
+// controller or cert-manager needs to handle the resource
+type RequiredAnnotations struct {
+	// Kinds are the annotations required on each kind of resource
+	Kinds map[string][]RequiredAnnotation `json:"kinds"`
+}
+
+// RequiredAnnotation is an annotation which must be set, or one of several interchangeable annotations
+type RequiredAnnotation struct {
+	// AnyOf are the annotation keys which satisfy the requirement, at least one of which must be set
+	AnyOf []string `json:"anyOf"`
+	// IfAnnotationPrefix only requires the annotation on resources with another annotation starting with this
+	// prefix, e.g. cert-manager.io/ for resources managed by cert-manager. It's required on every resource when empty.
+	IfAnnotationPrefix string `json:"ifAnnotationPrefix"`
+}
+
+// GetAnnotationsForResource returns the annotations required on a resource of a kind with the given annotations
+func (r RequiredAnnotations) GetAnnotationsForResource(kind string, annotations map[string]string) []RequiredAnnotation {
+	required := []RequiredAnnotation{}
+	for _, annotation := range r.Kinds[kind] {
+		if annotation.IfAnnotationPrefix == "" || hasAnnotationPrefix(annotations, annotation.IfAnnotationPrefix) {
+			required = append(required, annotation)
+		}
+	}
+	return required
+}
+
+func hasAnnotationPrefix(annotations map[string]string, prefix string) bool {
+	for key := range annotations {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that every required annotation has at least one key
+func (r RequiredAnnotations) Validate() error {
+	for kind, annotations := range r.Kinds {
+		for _, annotation := range annotations {
+			if len(annotation.AnyOf) == 0 {
+				return errors.New("Required annotations for " + kind + " must specify anyOf")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// celEnv declares what CEL checks can use: object is the checked value, e.g. a container for checks with
+// target Container, resource is the whole resource being audited, and quantity() parses a Kubernetes quantity
+var celEnv = newCELEnv()
+
+func newCELEnv() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("resource", cel.DynType),
+		cel.Function("quantity",
+			cel.Overload("quantity_string", []*cel.Type{cel.StringType}, cel.DoubleType, cel.UnaryBinding(parseCELQuantity)),
+		),
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return env
+}
+
+// parseCELQuantity converts a quantity such as 500m or 1Gi to a number, so quantities can be compared
+func parseCELQuantity(value ref.Val) ref.Val {
+	str, ok := value.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(value)
+	}
+	quantity, err := resource.ParseQuantity(string(str))
+	if err != nil {
+		return types.NewErr("invalid quantity %s: %v", str, err)
+	}
+	return types.Double(quantity.AsApproximateFloat64())
+}
+
+func compileCEL(expression string) (cel.Program, error) {
+	ast, issues := celEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression returns %s, should return a bool", ast.OutputType())
+	}
+	return celEnv.Program(ast)
+}
+
+// checkCEL evaluates the check's CEL expression against an object, which passes if the expression is true
+func (check SchemaCheck) checkCEL(obj interface{}) (bool, error) {
+	if check.celProgram == nil {
+		program, err := compileCEL(check.CEL)
+		if err != nil {
+			return false, fmt.Errorf("Invalid CEL expression for check %s: %v", check.ID, err)
+		}
+		check.celProgram = program
+	}
+	// typed objects like containers are converted to the same maps and lists as the resource
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		return false, err
+	}
+	var object interface{}
+	if err := json.Unmarshal(jsonBytes, &object); err != nil {
+		return false, err
+	}
+	out, _, err := check.celProgram.Eval(map[string]interface{}{
+		"object":   object,
+		"resource": check.celResource,
+	})
+	if err != nil {
+		return false, fmt.Errorf("Evaluating CEL expression for check %s failed: %v", check.ID, err)
+	}
+	passes, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("CEL expression for check %s returned %v, should return true or false", check.ID, out.Value())
+	}
+	return passes, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var confCELCheck = `
+checks:
+  cpuLimitAtLeastRequest: warning
+customChecks:
+  cpuLimitAtLeastRequest:
+    successMessage: CPU limit is at least the request
+    failureMessage: CPU limit of {{ .metadata.name }} should be at least the request
+    templateMessages: true
+    category: Efficiency
+    target: Container
+    cel: >
+      !has(object.resources.limits) || !has(object.resources.limits.cpu) ||
+      !has(object.resources.requests) || !has(object.resources.requests.cpu) ||
+      quantity(object.resources.limits.cpu) >= quantity(object.resources.requests.cpu)
+`
+
+func TestCELCheck(t *testing.T) {
+	parsedConf, err := Parse([]byte(confCELCheck))
+	assert.NoError(t, err)
+	check, err := parsedConf.CustomChecks["cpuLimitAtLeastRequest"].TemplateForResource(map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "api"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "CPU limit of api should be at least the request", check.FailureMessage)
+
+	container := func(limit, request string) map[string]interface{} {
+		return map[string]interface{}{
+			"resources": map[string]interface{}{
+				"limits":   map[string]interface{}{"cpu": limit},
+				"requests": map[string]interface{}{"cpu": request},
+			},
+		}
+	}
+	passes, _, err := check.CheckObject(container("1", "500m"))
+	assert.NoError(t, err)
+	assert.True(t, passes)
+	passes, _, err = check.CheckObject(container("250m", "0.5"))
+	assert.NoError(t, err)
+	assert.False(t, passes)
+	passes, _, err = check.CheckObject(map[string]interface{}{"resources": map[string]interface{}{}})
+	assert.NoError(t, err)
+	assert.True(t, passes)
+	_, _, err = check.CheckObject(container("lots", "1"))
+	assert.ErrorContains(t, err, "invalid quantity lots")
+}
+
+func TestCELCheckResource(t *testing.T) {
+	check := SchemaCheck{CEL: `object.image.startsWith(resource.metadata.labels.registry)`}
+	assert.NoError(t, check.Initialize("registryLabel"))
+	templated, err := check.TemplateForResource(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"registry": "registry.example.com/"}},
+	})
+	assert.NoError(t, err)
+	passes, _, err := templated.CheckObject(map[string]interface{}{"image": "registry.example.com/api:1.0"})
+	assert.NoError(t, err)
+	assert.True(t, passes)
+	passes, _, err = templated.CheckObject(map[string]interface{}{"image": "quay.io/api:1.0"})
+	assert.NoError(t, err)
+	assert.False(t, passes)
+	_, _, err = templated.CheckObject(map[string]interface{}{})
+	assert.ErrorContains(t, err, "Evaluating CEL expression for check registryLabel failed")
+}
+
+func TestInvalidCELCheck(t *testing.T) {
+	check := SchemaCheck{CEL: `object.replicas +`}
+	assert.ErrorContains(t, check.Initialize("broken"), "Invalid CEL expression for check broken")
+
+	check = SchemaCheck{CEL: `object.replicas + 1`}
+	assert.ErrorContains(t, check.Initialize("notBool"), "Invalid CEL expression for check notBool")
+
+	check = SchemaCheck{CEL: `true`, Schema: map[string]interface{}{"type": "object"}}
+	assert.EqualError(t, check.Initialize("both"), "Check both can only specify one of schema, schemaString, and cel")
+
+	check = SchemaCheck{CEL: `object.name`}
+	assert.NoError(t, check.Initialize("dynamic"))
+	_, _, err := check.CheckObject(map[string]interface{}{"name": "api"})
+	assert.EqualError(t, err, "CEL expression for check dynamic returned api, should return true or false")
+}
@@ -27,12 +27,15 @@ var (
 	checkOrder = []string{
 		// Controller Checks
 		"deploymentMissingReplicas",
+		"volumeClaimStorageClassMissing",
+		"volumeClaimAccessModeRisky",
 		// Pod checks
 		"hostIPCSet",
 		"hostPIDSet",
 		"hostNetworkSet",
 		"automountServiceAccountToken",
 		"topologySpreadConstraint",
+		"ephemeralContainersSet",
 		// Container checks
 		"memoryLimitsMissing",
 		"memoryRequestsMissing",
@@ -40,6 +43,7 @@ var (
 		"cpuRequestsMissing",
 		"readinessProbeMissing",
 		"livenessProbeMissing",
+		"probeMisconfigured",
 		"pullPolicyNotAlways",
 		"tagNotSpecified",
 		"hostPortSet",
@@ -68,8 +72,37 @@ var (
 		"rolebindingClusterAdminClusterRole",
 		"rolebindingClusterAdminRole",
 	}
+	// PolicyChecks are the checks implemented in the validator package rather than with a schema. Their
+	// settings are read from their own blocks, e.g. namingConventions, but like other checks they're
+	// turned on by giving them a severity in checks.
+	PolicyChecks = map[string]bool{
+		"namingConventionMismatched": true,
+		"hostPortUsed":               true,
+		"duplicateResource":          true,
+		"imageScanMissing":           true,
+		"requiredAnnotationsMissing": true,
+		"requiredLabelsMissing":      true,
+		"imagePinnedByDigest":        true,
+		"deprecatedAPIVersion":       true,
+		"requestsDeviateFromVPA":     true,
+		"resourceProfileMismatch":    true,
+	}
 )
 
+// IsKnownCheck returns true if the check is a built-in, custom, policy, or loaded WebAssembly check
+func (conf Configuration) IsKnownCheck(checkID string) bool {
+	if conf.IsWasmCheck(checkID) {
+		return true
+	}
+	if _, ok := conf.CustomChecks[checkID]; ok {
+		return true
+	}
+	if _, ok := BuiltInChecks[checkID]; ok {
+		return true
+	}
+	return PolicyChecks[checkID]
+}
+
 func init() {
 	schemaBox := packr.New("Schemas", "../../checks")
 	for _, checkID := range checkOrder {
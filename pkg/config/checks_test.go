@@ -10,6 +10,7 @@ func TestRequiredFieldsOnBuiltInChecks(t *testing.T) {
 	for _, v := range BuiltInChecks {
 		assert.NotEmpty(t, v.SuccessMessage)
 		assert.NotEmpty(t, v.FailureMessage)
+		assert.NotEmpty(t, v.Remediation)
 		assert.NotEmpty(t, v.Category)
 		assert.NotEmpty(t, v.Target)
 	}
@@ -0,0 +1,112 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// checkFile is a custom check defined in its own file, with the severity and kinds that would
+// otherwise be set under checks in the config
+type checkFile struct {
+	SchemaCheck
+	Severity Severity `json:"severity"`
+	Kinds    []string `json:"kinds"`
+}
+
+// LoadChecksDir adds the custom checks in a directory, one per .yaml file. A check's ID is its id, or
+// else its file name, and it replaces a built-in check with the same ID. Its severity takes precedence
+// over the severity under checks in the config. Two files with the same ID, or a file with the same ID
+// as a custom check in the config, are an error.
+func (conf *Configuration) LoadChecksDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	files := map[string]string{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file := checkFile{}
+		if err := UnmarshalYAMLOrJSON(contents, &file); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		checkID := file.ID
+		if checkID == "" {
+			checkID = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if other, ok := files[checkID]; ok {
+			return fmt.Errorf("%s and %s both define check %s", other, path, checkID)
+		}
+		if _, ok := conf.CustomChecks[checkID]; ok {
+			return fmt.Errorf("%s defines check %s, which is already a custom check in the config", path, checkID)
+		}
+		files[checkID] = path
+		if err := conf.addCheckFile(checkID, file); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	checkIDs := []string{}
+	for checkID := range files {
+		checkIDs = append(checkIDs, checkID)
+	}
+	sort.Strings(checkIDs)
+	for _, checkID := range checkIDs {
+		if _, ok := BuiltInChecks[checkID]; ok {
+			logrus.Infof("Check %s from %s replaces the built-in check", checkID, files[checkID])
+		}
+	}
+	logrus.Debugf("Loaded %d checks from %s", len(files), dir)
+	return conf.initialize()
+}
+
+func (conf *Configuration) addCheckFile(checkID string, file checkFile) error {
+	if file.Severity != "" {
+		if file.Severity != SeverityIgnore && file.Severity != SeverityWarning && file.Severity != SeverityDanger {
+			return fmt.Errorf("Invalid severity %s for check %s, should be one of ignore, warning, or danger", file.Severity, checkID)
+		}
+		if conf.Checks == nil {
+			conf.Checks = map[string]Severity{}
+		}
+		conf.Checks[checkID] = file.Severity
+	} else if _, ok := conf.Checks[checkID]; !ok {
+		return fmt.Errorf("no severity specified for check %s, set severity in the file or under checks in the config", checkID)
+	}
+	if file.Kinds != nil {
+		if conf.CheckKinds == nil {
+			conf.CheckKinds = map[string][]string{}
+		}
+		conf.CheckKinds[checkID] = file.Kinds
+	}
+	if conf.CustomChecks == nil {
+		conf.CustomChecks = map[string]SchemaCheck{}
+	}
+	// the check is initialized along with the config's custom checks
+	conf.CustomChecks[checkID] = file.SchemaCheck
+	return nil
+}
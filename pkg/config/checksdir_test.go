@@ -0,0 +1,121 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var checkFileSecurityContext = `
+successMessage: Security context is set
+failureMessage: Security context should be set
+category: Security
+target: Container
+severity: danger
+kinds:
+- Deployment
+schema:
+  '$schema': http://json-schema.org/draft-07/schema
+  type: object
+  required:
+  - securityContext
+`
+
+var checkFileHostIPC = `
+successMessage: Host IPC is off
+failureMessage: Host IPC must be off
+category: Security
+target: PodSpec
+schema:
+  '$schema': http://json-schema.org/draft-07/schema
+  type: object
+  properties:
+    hostIPC:
+      const: false
+`
+
+func writeCheckFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+	return dir
+}
+
+func TestLoadChecksDir(t *testing.T) {
+	dir := writeCheckFiles(t, map[string]string{
+		"securityContextMissing.yaml": checkFileSecurityContext,
+		"hostIPCSet.yml":              checkFileHostIPC,
+		"README.md":                   "not a check",
+	})
+	conf, err := Parse([]byte(confValidYAML))
+	assert.NoError(t, err)
+	conf.Checks["hostIPCSet"] = SeverityWarning
+
+	assert.NoError(t, conf.LoadChecksDir(dir))
+	assert.Len(t, conf.CustomChecks, 2)
+	assert.NoError(t, conf.ValidateChecks())
+
+	check := conf.CustomChecks["securityContextMissing"]
+	assert.Equal(t, "securityContextMissing", check.ID)
+	assert.Equal(t, TargetContainer, check.Target)
+	assert.Contains(t, check.SchemaString, "securityContext")
+	assert.Equal(t, SeverityDanger, conf.Checks["securityContextMissing"])
+	assert.True(t, conf.IsCheckEnabledForKind("securityContextMissing", "Deployment"))
+	assert.False(t, conf.IsCheckEnabledForKind("securityContextMissing", "StatefulSet"))
+
+	hostIPC := conf.CustomChecks["hostIPCSet"]
+	assert.Equal(t, "Host IPC must be off", hostIPC.FailureMessage)
+	assert.Equal(t, SeverityWarning, conf.Checks["hostIPCSet"], "the config's severity is kept when the file has none")
+	templated, err := hostIPC.TemplateForResource(map[string]interface{}{})
+	assert.NoError(t, err)
+	isValid, _, err := templated.CheckObject(map[string]interface{}{"hostIPC": true})
+	assert.NoError(t, err)
+	assert.False(t, isValid)
+}
+
+func TestLoadChecksDirConflicts(t *testing.T) {
+	dir := writeCheckFiles(t, map[string]string{
+		"a.yaml": "id: securityContextMissing\n" + checkFileSecurityContext,
+		"b.yaml": "id: securityContextMissing\n" + checkFileSecurityContext,
+	})
+	conf, err := Parse([]byte(confValidYAML))
+	assert.NoError(t, err)
+	err = conf.LoadChecksDir(dir)
+	assert.EqualError(t, err, filepath.Join(dir, "a.yaml")+" and "+filepath.Join(dir, "b.yaml")+" both define check securityContextMissing")
+
+	dir = writeCheckFiles(t, map[string]string{"foo.yaml": checkFileSecurityContext})
+	conf, err = Parse([]byte(confCustomChecks))
+	assert.NoError(t, err)
+	err = conf.LoadChecksDir(dir)
+	assert.EqualError(t, err, filepath.Join(dir, "foo.yaml")+" defines check foo, which is already a custom check in the config")
+
+	dir = writeCheckFiles(t, map[string]string{"newCheck.yaml": checkFileHostIPC})
+	conf, err = Parse([]byte(confValidYAML))
+	assert.NoError(t, err)
+	err = conf.LoadChecksDir(dir)
+	assert.Error(t, err, "Expected error when check has no severity set")
+
+	dir = writeCheckFiles(t, map[string]string{"newCheck.yaml": checkFileHostIPC + "severity: high\n"})
+	err = conf.LoadChecksDir(dir)
+	assert.Error(t, err)
+
+	err = conf.LoadChecksDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
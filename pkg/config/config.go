@@ -16,29 +16,182 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/gobuffalo/packr/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 // Configuration contains all of the config for the validation checks.
 type Configuration struct {
-	DisplayName                  string                 `json:"displayName"`
-	Checks                       map[string]Severity    `json:"checks"`
-	CustomChecks                 map[string]SchemaCheck `json:"customChecks"`
-	Exemptions                   []Exemption            `json:"exemptions"`
-	DisallowExemptions           bool                   `json:"disallowExemptions"`
-	DisallowConfigExemptions     bool                   `json:"disallowConfigExemptions"`
-	DisallowAnnotationExemptions bool                   `json:"disallowAnnotationExemptions"`
-	Mutations                    []string               `json:"mutations"`
-	KubeContext                  string                 `json:"kubeContext"`
-	Namespace                    string                 `json:"namespace"`
+	DisplayName                  string                         `json:"displayName"`
+	Checks                       map[string]Severity            `json:"checks"`
+	CustomChecks                 map[string]SchemaCheck         `json:"customChecks"`
+	Exemptions                   []Exemption                    `json:"exemptions"`
+	DisallowExemptions           bool                           `json:"disallowExemptions"`
+	DisallowConfigExemptions     bool                           `json:"disallowConfigExemptions"`
+	DisallowAnnotationExemptions bool                           `json:"disallowAnnotationExemptions"`
+	Mutations                    []string                       `json:"mutations"`
+	Kubeconfig                   string                         `json:"kubeconfig"`
+	KubeContext                  string                         `json:"kubeContext"`
+	Namespace                    string                         `json:"namespace"`
+	DefaultNamespace             string                         `json:"defaultNamespace"`
+	ExcludePaths                 []string                       `json:"excludePaths"`
+	CheckPaths                   map[string]string              `json:"checkPaths"`
+	VPARecommendations           VPARecommendations             `json:"vpaRecommendations"`
+	ImageDigestPolicy            ImageDigestPolicy              `json:"imageDigestPolicy"`
+	HostPortPolicy               HostPortPolicy                 `json:"hostPortPolicy"`
+	ImageScanPolicy              ImageScanPolicy                `json:"imageScanPolicy"`
+	RequiredLabels               RequiredLabels                 `json:"requiredLabels"`
+	RequiredAnnotations          RequiredAnnotations            `json:"requiredAnnotations"`
+	NamingConventions            NamingConventions              `json:"namingConventions"`
+	DeprecatedAPIPolicy          DeprecatedAPIPolicy            `json:"deprecatedAPIPolicy"`
+	EnvironmentLabel             string                         `json:"environmentLabel"`
+	EnvironmentSeverities        map[string]map[string]Severity `json:"environmentSeverities"`
+	OwnerLabel                   string                         `json:"ownerLabel"`
+	Owners                       []Owner                        `json:"owners"`
+	SeverityPromotion            SeverityPromotion              `json:"severityPromotion"`
+	ResourceKinds                []string                       `json:"resourceKinds"`
+	ResourceProfiles             map[string]ResourceProfile     `json:"resourceProfiles"`
+	Profiles                     map[string]json.RawMessage     `json:"profiles"`
+	VerboseResults               bool                           `json:"verboseResults"`
+	Parallelism                  int                            `json:"parallelism"`
+	Gates                        map[string]Gate                `json:"gates"`
+	Priorities                   map[string]Priority            `json:"priorities"`
+	WasmChecks                   map[string]Severity            `json:"wasmChecks"`
+	// CheckKinds limits checks to particular kinds, from checks entries such as `{severity: warning, kinds: [Deployment]}`
+	CheckKinds map[string][]string `json:"-"`
+	// WasmModules are the WebAssembly checks loaded with --wasm-checks
+	WasmModules []WasmModule `json:"-"`
+}
+
+// checkKinds holds the entries in checks, to read kinds from those in object form
+type checkKinds struct {
+	Checks map[string]json.RawMessage `json:"checks"`
+}
+
+func (conf *Configuration) addCheckKinds(kinds checkKinds) error {
+	for checkID, raw := range kinds.Checks {
+		if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			continue
+		}
+		setting := struct {
+			Kinds []string `json:"kinds"`
+		}{}
+		if err := json.Unmarshal(raw, &setting); err != nil {
+			return fmt.Errorf("Invalid kinds for check %s: %v", checkID, err)
+		}
+		if setting.Kinds == nil {
+			continue
+		}
+		if conf.CheckKinds == nil {
+			conf.CheckKinds = map[string][]string{}
+		}
+		conf.CheckKinds[checkID] = setting.Kinds
+	}
+	return nil
+}
+
+// IsCheckEnabledForKind returns false if the check is limited to other kinds
+func (conf Configuration) IsCheckEnabledForKind(checkID string, kind string) bool {
+	kinds, ok := conf.CheckKinds[checkID]
+	if !ok {
+		return true
+	}
+	for _, enabledKind := range kinds {
+		if enabledKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceProfile is a standard container size, e.g. small, medium, or large
+type ResourceProfile struct {
+	Requests map[string]string `json:"requests"`
+	Limits   map[string]string `json:"limits"`
+}
+
+// SeverityPromotion configures promoting warnings to dangers when they keep failing across audits
+type SeverityPromotion struct {
+	AfterFailures int `json:"afterFailures"`
+}
+
+// VPARecommendations configures comparing container requests against VerticalPodAutoscaler recommendations.
+// The comparison is turned on by giving requestsDeviateFromVPA a severity in checks.
+type VPARecommendations struct {
+	MaxDeviationPercent int `json:"maxDeviationPercent"`
+}
+
+// DefaultVPAMaxDeviationPercent is used when no maximum deviation is configured
+const DefaultVPAMaxDeviationPercent = 50
+
+// GetMaxDeviationPercent returns the configured maximum deviation, or the default
+func (v VPARecommendations) GetMaxDeviationPercent() int {
+	if v.MaxDeviationPercent <= 0 {
+		return DefaultVPAMaxDeviationPercent
+	}
+	return v.MaxDeviationPercent
+}
+
+// ImageDigestPolicy configures requiring images to be pinned by digest rather than by tag
+type ImageDigestPolicy struct {
+	// AllowedImages are registries or images which may be referenced by tag, matched by prefix, e.g. gcr.io/distroless/
+	AllowedImages []string `json:"allowedImages"`
+}
+
+// GetParallelism returns the number of resources to validate concurrently, which is GOMAXPROCS by default
+func (conf Configuration) GetParallelism() int {
+	if conf.Parallelism < 1 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return conf.Parallelism
+}
+
+// HostPortPolicy configures the hostPortUsed check, which reports containers declaring host ports
+// unless they're allowed
+type HostPortPolicy struct {
+	// AllowedPorts are host ports which containers may use
+	AllowedPorts []int32 `json:"allowedPorts"`
+}
+
+// DefaultImageScanAnnotation is the annotation with the time a workload's images were last scanned
+const DefaultImageScanAnnotation = "scanned-at"
+
+// DefaultImageScanMaxAgeDays is how old an image scan may be by default
+const DefaultImageScanMaxAgeDays = 7
+
+// ImageScanPolicy configures requiring workloads to be annotated with a recent image scan
+type ImageScanPolicy struct {
+	// Annotation has the RFC 3339 time of the last scan, scanned-at by default
+	Annotation string `json:"annotation"`
+	// MaxAgeDays is how old the last scan may be, 7 by default
+	MaxAgeDays int `json:"maxAgeDays"`
+}
+
+// GetAnnotation returns the configured annotation, or scanned-at
+func (p ImageScanPolicy) GetAnnotation() string {
+	if p.Annotation == "" {
+		return DefaultImageScanAnnotation
+	}
+	return p.Annotation
+}
+
+// GetMaxAgeDays returns the configured maximum age of a scan, or 7 days
+func (p ImageScanPolicy) GetMaxAgeDays() int {
+	if p.MaxAgeDays == 0 {
+		return DefaultImageScanMaxAgeDays
+	}
+	return p.MaxAgeDays
 }
 
 // Exemption represents an exemption to normal rules
@@ -60,29 +213,45 @@ func getConfigBox() *packr.Box {
 
 // ParseFile parses config from a file.
 func ParseFile(path string) (Configuration, error) {
-	var rawBytes []byte
-	var err error
+	return parseFile(http.DefaultClient, path)
+}
+
+func parseFile(client *http.Client, path string) (Configuration, error) {
+	rawBytes, err := readFile(client, path)
+	if err != nil {
+		return Configuration{}, err
+	}
+	return Parse(rawBytes)
+}
+
+// readFile reads a config file, a URL with the given client, or the default config when path is empty
+func readFile(client *http.Client, path string) ([]byte, error) {
 	if path == "" {
-		rawBytes, err = getConfigBox().Find("config.yaml")
+		return getConfigBox().Find("config.yaml")
 	} else if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
 		// path is a url
-		response, err2 := http.Get(path)
-		if err2 != nil {
-			return Configuration{}, err2
+		response, err := client.Get(path)
+		if err != nil {
+			return nil, err
 		}
-		rawBytes, err = io.ReadAll(response.Body)
-	} else {
-		// path is local
-		rawBytes, err = os.ReadFile(path)
-	}
-	if err != nil {
-		return Configuration{}, err
+		defer response.Body.Close()
+		return io.ReadAll(response.Body)
 	}
-	return Parse(rawBytes)
+	// path is local
+	return os.ReadFile(path)
 }
 
 // Parse parses config from a byte array.
 func Parse(rawBytes []byte) (Configuration, error) {
+	conf, err := decode(rawBytes)
+	if err != nil {
+		return conf, err
+	}
+	return conf, conf.initialize()
+}
+
+// decode reads config from a byte array without validating it, e.g. to merge it with other config first
+func decode(rawBytes []byte) (Configuration, error) {
 	reader := bytes.NewReader(rawBytes)
 	conf := Configuration{}
 	d := yaml.NewYAMLOrJSONDecoder(reader, 4096)
@@ -94,23 +263,210 @@ func Parse(rawBytes []byte) (Configuration, error) {
 			return conf, fmt.Errorf("Decoding config failed: %v", err)
 		}
 	}
-	for key, check := range conf.CustomChecks {
+	d = yaml.NewYAMLOrJSONDecoder(bytes.NewReader(rawBytes), 4096)
+	for {
+		kinds := checkKinds{}
+		if err := d.Decode(&kinds); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return conf, fmt.Errorf("Decoding config failed: %v", err)
+		}
+		if err := conf.addCheckKinds(kinds); err != nil {
+			return conf, err
+		}
+	}
+	return conf, nil
+}
+
+// initialize prepares custom checks and the label and naming patterns, and validates the config
+func (conf *Configuration) initialize() error {
+	if problems := conf.initializeAll(); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// initializeAll initializes the custom checks and policies, and validates the config, returning every problem
+// rather than only the first one
+func (conf *Configuration) initializeAll() []error {
+	problems := []error{}
+	keys := []string{}
+	for key := range conf.CustomChecks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if PolicyChecks[key] {
+			problems = append(problems, fmt.Errorf("Custom check %s has the same ID as a built-in check", key))
+			continue
+		}
+		check := conf.CustomChecks[key]
 		err := check.Initialize(key)
 		if err != nil {
-			return conf, err
+			problems = append(problems, err)
+			continue
 		}
 		conf.CustomChecks[key] = check
 		if _, ok := conf.Checks[key]; !ok {
-			return conf, fmt.Errorf("no severity specified for custom check %s. Please add the following to your configuration:\n\nchecks:\n  %s: warning # or danger/ignore\n\nto enable your check", key, key)
+			problems = append(problems, fmt.Errorf("no severity specified for custom check %s. Please add the following to your configuration:\n\nchecks:\n  %s: warning # or danger/ignore\n\nto enable your check", key, key))
+		}
+	}
+	problems = append(problems, conf.validateAll()...)
+	if len(problems) > 0 {
+		// invalid patterns were already reported by validateAll
+		return problems
+	}
+	if err := conf.RequiredLabels.Initialize(); err != nil {
+		problems = append(problems, err)
+	}
+	if err := conf.NamingConventions.Initialize(); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
+// ApplyProfile overlays the named profile onto the config. Maps such as checks are merged
+// with the base config, while lists and other values set in the profile replace the base values.
+func (conf *Configuration) ApplyProfile(name string) error {
+	profile, ok := conf.Profiles[name]
+	if !ok {
+		profiles := []string{}
+		for profileName := range conf.Profiles {
+			profiles = append(profiles, profileName)
 		}
+		sort.Strings(profiles)
+		return fmt.Errorf("Unknown config profile %s, available profiles are: %s", name, strings.Join(profiles, ", "))
+	}
+	err := json.Unmarshal(profile, conf)
+	if err != nil {
+		return fmt.Errorf("Decoding config profile %s failed: %v", name, err)
+	}
+	kinds := checkKinds{}
+	err = json.Unmarshal(profile, &kinds)
+	if err != nil {
+		return fmt.Errorf("Decoding config profile %s failed: %v", name, err)
 	}
-	return conf, conf.Validate()
+	err = conf.addCheckKinds(kinds)
+	if err != nil {
+		return err
+	}
+	return conf.initialize()
 }
 
 // Validate checks if a config is valid
 func (conf Configuration) Validate() error {
+	if problems := conf.validateAll(); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// validateAll returns every problem with the config, rather than only the first one
+func (conf Configuration) validateAll() []error {
+	problems := []error{}
 	if len(conf.Checks) == 0 {
-		return errors.New("No checks were enabled")
+		problems = append(problems, errors.New("No checks were enabled"))
+	}
+	for checkID, path := range conf.CheckPaths {
+		if _, err := ParseFieldPath(path); err != nil {
+			problems = append(problems, fmt.Errorf("Invalid path override for check %s: %v", checkID, err))
+		}
+	}
+	for name, profile := range conf.ResourceProfiles {
+		for resourceName, quantity := range profile.Requests {
+			if _, err := resource.ParseQuantity(quantity); err != nil {
+				problems = append(problems, fmt.Errorf("Invalid %s request in resource profile %s: %v", resourceName, name, err))
+			}
+		}
+		for resourceName, quantity := range profile.Limits {
+			if _, err := resource.ParseQuantity(quantity); err != nil {
+				problems = append(problems, fmt.Errorf("Invalid %s limit in resource profile %s: %v", resourceName, name, err))
+			}
+		}
+	}
+	if conf.ImageScanPolicy.MaxAgeDays < 0 {
+		problems = append(problems, fmt.Errorf("Invalid maxAgeDays %d for imageScanPolicy, should be at least 1", conf.ImageScanPolicy.MaxAgeDays))
+	}
+	if conf.Parallelism < 0 {
+		problems = append(problems, fmt.Errorf("Invalid parallelism %d, should be at least 1", conf.Parallelism))
+	}
+	if err := conf.RequiredLabels.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	if err := conf.RequiredAnnotations.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	if err := conf.NamingConventions.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	if err := conf.DeprecatedAPIPolicy.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	for name, gate := range conf.Gates {
+		if err := gate.Validate(name); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	for checkID, priority := range conf.Priorities {
+		if _, err := ParsePriority(string(priority)); err != nil {
+			problems = append(problems, fmt.Errorf("Invalid priority for check %s: %v", checkID, err))
+		}
+	}
+	for checkID, severity := range conf.WasmChecks {
+		if severity != SeverityIgnore && severity != SeverityWarning && severity != SeverityDanger {
+			problems = append(problems, fmt.Errorf("Invalid severity %s for WebAssembly check %s, should be one of ignore, warning, or danger", severity, checkID))
+		}
+	}
+	for _, owner := range conf.Owners {
+		if owner.Team == "" {
+			problems = append(problems, errors.New("Owners must specify a team"))
+		}
+	}
+	return problems
+}
+
+// ValidateChecks makes sure every configured check exists and has a known severity. This isn't
+// part of Validate, since unknown checks otherwise only cause an error once they're evaluated.
+func (conf Configuration) ValidateChecks() error {
+	if problems := conf.validateAllChecks(); len(problems) > 0 {
+		return problems[0]
 	}
 	return nil
 }
+
+// validateAllChecks returns every problem with the configured checks, rather than only the first one
+func (conf Configuration) validateAllChecks() []error {
+	problems := []error{}
+	severities := map[string]map[string]Severity{"": conf.Checks}
+	for env, envSeverities := range conf.EnvironmentSeverities {
+		severities[env] = envSeverities
+	}
+	envs := []string{}
+	for env := range severities {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	for _, env := range envs {
+		checkIDs := []string{}
+		for checkID := range severities[env] {
+			checkIDs = append(checkIDs, checkID)
+		}
+		sort.Strings(checkIDs)
+		for _, checkID := range checkIDs {
+			if !conf.IsKnownCheck(checkID) {
+				problems = append(problems, fmt.Errorf("Unknown check %s", checkID))
+				continue
+			}
+			severity := severities[env][checkID]
+			if severity != SeverityIgnore && severity != SeverityWarning && severity != SeverityDanger {
+				if env != "" {
+					problems = append(problems, fmt.Errorf("Invalid severity %s for check %s in environment %s, should be one of ignore, warning, or danger", severity, checkID, env))
+				} else {
+					problems = append(problems, fmt.Errorf("Invalid severity %s for check %s, should be one of ignore, warning, or danger", severity, checkID))
+				}
+			}
+		}
+	}
+	return problems
+}
@@ -20,11 +20,14 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var confInvalid = `test`
@@ -134,6 +137,20 @@ func TestConfigFromURL(t *testing.T) {
 
 }
 
+func TestConfigFromURLWithClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	_, err := ParseFilesWithClient(client, []string{server.URL + "/config.yaml"})
+	assert.Error(t, err, "a body which stalls should time out")
+	assert.NotEmpty(t, ValidateFilesWithClient(client, []string{server.URL + "/config.yaml"}))
+}
+
 func TestConfigNoServerError(t *testing.T) {
 	var err error
 	_, err = ParseFile("http://localhost:8081/exampleURL")
@@ -175,6 +192,23 @@ func TestConfigWithCustomChecks(t *testing.T) {
 	assert.Equal(t, false, isValid)
 }
 
+func TestTemplateMessages(t *testing.T) {
+	resource := map[string]interface{}{"metadata": map[string]interface{}{"name": "api"}}
+	check := SchemaCheck{
+		ID:             "literalBraces",
+		FailureMessage: "Value should not contain {{ .metadata.name }}",
+		SchemaString:   `{"type": "object"}`,
+	}
+	templated, err := check.TemplateForResource(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, "Value should not contain {{ .metadata.name }}", templated.FailureMessage, "messages should only be templated when the check opts in")
+
+	check.TemplateMessages = true
+	templated, err = check.TemplateForResource(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, "Value should not contain api", templated.FailureMessage)
+}
+
 func TestCustomChecksMissingSeverity(t *testing.T) {
 	_, err := Parse([]byte(confCustomChecksMissing))
 	assert.Error(t, err, "Expected error when check has no severity set")
@@ -184,3 +218,564 @@ func testParsedConfig(t *testing.T, config *Configuration) {
 	assert.Equal(t, SeverityWarning, config.Checks["cpuRequestsMissing"])
 	assert.Equal(t, Severity(""), config.Checks["cpuLimitsMissing"])
 }
+
+func TestEnvironmentSeverities(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostPortSet: warning
+  tagNotSpecified: ignore
+environmentSeverities:
+  prod:
+    hostPortSet: danger
+    tagNotSpecified: danger
+  dev:
+    hostPortSet: ignore
+`))
+	assert.NoError(t, err)
+	meta := func(labels map[string]string) metav1.Object {
+		return &metav1.ObjectMeta{Name: "foo", Labels: labels}
+	}
+
+	severity, ok := parsedConf.GetSeverity("hostPortSet", meta(map[string]string{"environment": "prod"}))
+	assert.True(t, ok)
+	assert.Equal(t, SeverityDanger, severity)
+	severity, _ = parsedConf.GetSeverity("hostPortSet", meta(map[string]string{"environment": "staging"}))
+	assert.Equal(t, SeverityWarning, severity)
+	severity, _ = parsedConf.GetSeverity("hostPortSet", meta(nil))
+	assert.Equal(t, SeverityWarning, severity)
+
+	assert.True(t, parsedConf.IsActionable("tagNotSpecified", meta(map[string]string{"environment": "prod"}), ""))
+	assert.False(t, parsedConf.IsActionable("tagNotSpecified", meta(nil), ""))
+	assert.False(t, parsedConf.IsActionable("hostPortSet", meta(map[string]string{"environment": "dev"}), ""))
+
+	assert.True(t, parsedConf.IsCheckEnabled("hostPortSet"))
+	assert.True(t, parsedConf.IsCheckEnabled("tagNotSpecified"), "should be enabled by the prod severity")
+	assert.False(t, parsedConf.IsCheckEnabled("hostIPCSet"))
+
+	parsedConf.EnvironmentLabel = "tier"
+	severity, _ = parsedConf.GetSeverity("hostPortSet", meta(map[string]string{"environment": "prod"}))
+	assert.Equal(t, SeverityWarning, severity)
+	severity, _ = parsedConf.GetSeverity("hostPortSet", meta(map[string]string{"tier": "prod"}))
+	assert.Equal(t, SeverityDanger, severity)
+}
+
+func TestGetOwner(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostPortSet: warning
+ownerLabel: team
+owners:
+- team: payments
+  namespaces: [payments, billing]
+- team: platform
+  namespaces: [kube-system]
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payments", "platform"}, parsedConf.GetTeams())
+	assert.Equal(t, "payments", parsedConf.GetOwner(&metav1.ObjectMeta{Namespace: "billing"}))
+	assert.Equal(t, "search", parsedConf.GetOwner(&metav1.ObjectMeta{Namespace: "billing", Labels: map[string]string{"team": "search"}}))
+	assert.Equal(t, "", parsedConf.GetOwner(&metav1.ObjectMeta{Namespace: "default"}))
+
+	_, err = Parse([]byte(`
+checks:
+  hostPortSet: warning
+owners:
+- namespaces: [payments]
+`))
+	assert.Error(t, err)
+}
+
+func TestInvalidResourceProfile(t *testing.T) {
+	_, err := Parse([]byte(`
+checks:
+  hostPortSet: warning
+resourceProfiles:
+  small:
+    requests:
+      cpu: lots
+`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "resource profile small")
+}
+
+func TestApplyProfile(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostPortSet: warning
+  tagNotSpecified: danger
+exemptions:
+- controllerNames: [dns]
+  rules: [hostPortSet]
+profiles:
+  strict:
+    checks:
+      hostPortSet: danger
+    exemptions: []
+  lenient:
+    checks:
+      tagNotSpecified: ignore
+`))
+	assert.NoError(t, err)
+
+	strict := parsedConf
+	strict.Checks = map[string]Severity{}
+	for k, v := range parsedConf.Checks {
+		strict.Checks[k] = v
+	}
+	assert.NoError(t, strict.ApplyProfile("strict"))
+	assert.Equal(t, SeverityDanger, strict.Checks["hostPortSet"])
+	assert.Equal(t, SeverityDanger, strict.Checks["tagNotSpecified"], "checks not in the profile should be kept")
+	assert.Empty(t, strict.Exemptions)
+
+	assert.NoError(t, parsedConf.ApplyProfile("lenient"))
+	assert.Equal(t, SeverityIgnore, parsedConf.Checks["tagNotSpecified"])
+	assert.Equal(t, SeverityWarning, parsedConf.Checks["hostPortSet"])
+	assert.Len(t, parsedConf.Exemptions, 1)
+
+	err = parsedConf.ApplyProfile("relaxed")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "lenient, strict")
+}
+
+func TestGetFramework(t *testing.T) {
+	framework, err := GetFramework("cis")
+	assert.NoError(t, err)
+	assert.Equal(t, "cis", framework.ID)
+	assert.NotEmpty(t, framework.Controls)
+
+	_, err = GetFramework("pci")
+	assert.EqualError(t, err, "Unknown compliance framework pci, available frameworks are: cis, nsa")
+
+	conf := Configuration{Checks: map[string]Severity{
+		"runAsRootAllowed":          SeverityDanger,
+		"notReadOnlyRootFilesystem": SeverityIgnore,
+	}}
+	controlChecks := conf.GetControlChecks("cis")
+	assert.Equal(t, map[string][]string{
+		"5.2.7": {"runAsRootAllowed"},
+		"5.7.3": {"runAsRootAllowed"},
+	}, controlChecks)
+}
+
+func TestBuiltInCheckControlsExist(t *testing.T) {
+	for _, frameworkID := range GetFrameworks() {
+		framework, err := GetFramework(frameworkID)
+		assert.NoError(t, err)
+		controls := map[string]bool{}
+		for _, control := range framework.Controls {
+			controls[control.ID] = true
+		}
+		for checkID, check := range BuiltInChecks {
+			for _, controlID := range check.Controls[frameworkID] {
+				assert.True(t, controls[controlID], "check %s references unknown %s control %s", checkID, frameworkID, controlID)
+			}
+		}
+	}
+}
+
+func TestValidateChecks(t *testing.T) {
+	conf := Configuration{Checks: map[string]Severity{
+		"hostIPCSet": SeverityDanger,
+		"hostPIDSet": SeverityIgnore,
+	}}
+	assert.NoError(t, conf.ValidateChecks())
+
+	conf.Checks["hostPortSet"] = "error"
+	assert.EqualError(t, conf.ValidateChecks(), "Invalid severity error for check hostPortSet, should be one of ignore, warning, or danger")
+
+	conf.Checks["hostPortSet"] = SeverityWarning
+	conf.EnvironmentSeverities = map[string]map[string]Severity{"prod": {"hostPIDSet": "high"}}
+	assert.EqualError(t, conf.ValidateChecks(), "Invalid severity high for check hostPIDSet in environment prod, should be one of ignore, warning, or danger")
+
+	conf.EnvironmentSeverities = nil
+	conf.Checks["hostIPCSett"] = SeverityWarning
+	assert.EqualError(t, conf.ValidateChecks(), "Unknown check hostIPCSett")
+}
+
+func TestGetGate(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+gates:
+  pr:
+    maxDangers: 5
+  release:
+    maxDangers: 0
+    minScore: 80
+`))
+	assert.NoError(t, err)
+	gate, err := parsedConf.GetGate("release")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *gate.MaxDangers)
+	assert.Nil(t, gate.MaxWarnings)
+	assert.Equal(t, 80, gate.MinScore)
+
+	_, err = parsedConf.GetGate("nightly")
+	assert.EqualError(t, err, "Unknown gate nightly, available gates are: pr, release")
+}
+
+func TestNegativeGateMaximums(t *testing.T) {
+	_, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+gates:
+  pr:
+    maxDangers: -1
+`))
+	assert.EqualError(t, err, "Invalid maxDangers -1 for gate pr, should be at least 0")
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+gates:
+  pr:
+    maxWarnings: -2
+`))
+	assert.EqualError(t, err, "Invalid maxWarnings -2 for gate pr, should be at least 0")
+}
+
+func TestCheckKinds(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  deploymentMissingReplicas:
+    severity: warning
+    kinds:
+    - Deployment
+  hostIPCSet: danger
+profiles:
+  strict:
+    checks:
+      deploymentMissingReplicas:
+        severity: danger
+        kinds:
+        - Deployment
+        - StatefulSet
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityWarning, parsedConf.Checks["deploymentMissingReplicas"])
+	assert.True(t, parsedConf.IsCheckEnabledForKind("deploymentMissingReplicas", "Deployment"))
+	assert.False(t, parsedConf.IsCheckEnabledForKind("deploymentMissingReplicas", "StatefulSet"))
+	assert.True(t, parsedConf.IsCheckEnabledForKind("hostIPCSet", "DaemonSet"))
+
+	assert.NoError(t, parsedConf.ApplyProfile("strict"))
+	assert.Equal(t, SeverityDanger, parsedConf.Checks["deploymentMissingReplicas"])
+	assert.True(t, parsedConf.IsCheckEnabledForKind("deploymentMissingReplicas", "StatefulSet"))
+	assert.Equal(t, SeverityDanger, parsedConf.Checks["hostIPCSet"])
+}
+
+func TestGetPriority(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+  tagNotSpecified: warning
+priorities:
+  hostIPCSet: must-fix
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, PriorityMustFix, parsedConf.GetPriority("hostIPCSet"))
+	assert.Equal(t, PriorityNeutral, parsedConf.GetPriority("tagNotSpecified"))
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+priorities:
+  hostIPCSet: urgent
+`))
+	assert.EqualError(t, err, "Invalid priority for check hostIPCSet: Invalid priority urgent, should be one of must-fix, neutral, or nice-to-have")
+
+	assert.True(t, PriorityMustFix.IsAtLeast(PriorityNeutral))
+	assert.True(t, Priority("").IsAtLeast(PriorityNeutral))
+	assert.False(t, PriorityNiceToHave.IsAtLeast(PriorityNeutral))
+}
+
+func TestImageDigestPolicy(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+`))
+	assert.NoError(t, err)
+	assert.Empty(t, parsedConf.ImageDigestPolicy.AllowedImages)
+
+	parsedConf, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+  imagePinnedByDigest: warning
+imageDigestPolicy:
+  allowedImages:
+  - gcr.io/distroless/
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, parsedConf.ValidateChecks())
+	assert.Equal(t, []string{"gcr.io/distroless/"}, parsedConf.ImageDigestPolicy.AllowedImages)
+}
+
+func TestSetCheckSeverity(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostPortSet: warning
+environmentSeverities:
+  dev:
+    hostPortSet: ignore
+`))
+	assert.NoError(t, err)
+	dev := &metav1.ObjectMeta{Labels: map[string]string{"environment": "dev"}}
+
+	assert.NoError(t, parsedConf.SetCheckSeverity("hostPortSet", SeverityDanger))
+	severity, ok := parsedConf.GetSeverity("hostPortSet", dev)
+	assert.True(t, ok)
+	assert.Equal(t, SeverityDanger, severity, "should replace environment severities")
+
+	assert.NoError(t, parsedConf.SetCheckSeverity("runAsRootAllowed", SeverityWarning))
+	assert.Equal(t, SeverityWarning, parsedConf.Checks["runAsRootAllowed"], "should enable checks the config doesn't list")
+
+	assert.NoError(t, parsedConf.SetCheckSeverity("hostPortUsed", SeverityDanger))
+	assert.Equal(t, SeverityDanger, parsedConf.Checks["hostPortUsed"], "should enable policy checks")
+
+	assert.EqualError(t, parsedConf.SetCheckSeverity("notACheck", SeverityDanger), "Unknown check notACheck")
+	assert.EqualError(t, parsedConf.SetCheckSeverity("hostPortSet", "critical"), "Invalid severity critical for check hostPortSet, should be one of ignore, warning, or danger")
+}
+
+func TestHostPortPolicy(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+  hostPortUsed: warning
+hostPortPolicy:
+  allowedPorts:
+  - 9100
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, parsedConf.ValidateChecks())
+	assert.Equal(t, []int32{9100}, parsedConf.HostPortPolicy.AllowedPorts)
+}
+
+func TestImageScanPolicy(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "scanned-at", parsedConf.ImageScanPolicy.GetAnnotation())
+	assert.Equal(t, 7, parsedConf.ImageScanPolicy.GetMaxAgeDays())
+
+	parsedConf, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+imageScanPolicy:
+  annotation: security.example.com/scanned
+  maxAgeDays: 30
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "security.example.com/scanned", parsedConf.ImageScanPolicy.GetAnnotation())
+	assert.Equal(t, 30, parsedConf.ImageScanPolicy.GetMaxAgeDays())
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+imageScanPolicy:
+  maxAgeDays: -1
+`))
+	assert.EqualError(t, err, "Invalid maxAgeDays -1 for imageScanPolicy, should be at least 1")
+}
+
+func TestRequiredLabels(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+requiredLabels:
+  labels:
+  - key: team
+  - key: cost-center
+    pattern: '^[0-9]+$'
+    kinds:
+    - Deployment
+`))
+	assert.NoError(t, err)
+	assert.Len(t, parsedConf.RequiredLabels.GetLabelsForKind("Deployment"), 2)
+	assert.Len(t, parsedConf.RequiredLabels.GetLabelsForKind("Service"), 1)
+	costCenter := parsedConf.RequiredLabels.Labels[1]
+	assert.NotNil(t, costCenter.pattern, "patterns should be compiled when the config is parsed")
+	assert.True(t, costCenter.MatchesPattern("1234"))
+	assert.False(t, costCenter.MatchesPattern("finance"))
+	assert.True(t, parsedConf.RequiredLabels.Labels[0].MatchesPattern("anything"))
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+requiredLabels:
+  labels:
+  - pattern: '.*'
+`))
+	assert.EqualError(t, err, "Required labels must specify a key")
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+requiredLabels:
+  labels:
+  - key: team
+    pattern: '['
+`))
+	assert.ErrorContains(t, err, "Invalid pattern for required label team")
+}
+
+func TestRequiredAnnotations(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+requiredAnnotations:
+  kinds:
+    Ingress:
+    - anyOf:
+      - cert-manager.io/cluster-issuer
+      - cert-manager.io/issuer
+      ifAnnotationPrefix: cert-manager.io/
+    - anyOf:
+      - kubernetes.io/ingress.class
+`))
+	assert.NoError(t, err)
+	assert.Len(t, parsedConf.RequiredAnnotations.GetAnnotationsForResource("Ingress", nil), 1)
+	assert.Len(t, parsedConf.RequiredAnnotations.GetAnnotationsForResource("Ingress", map[string]string{"cert-manager.io/common-name": "example.com"}), 2)
+	assert.Len(t, parsedConf.RequiredAnnotations.GetAnnotationsForResource("Service", nil), 0)
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+requiredAnnotations:
+  kinds:
+    Ingress:
+    - ifAnnotationPrefix: cert-manager.io/
+`))
+	assert.EqualError(t, err, "Required annotations for Ingress must specify anyOf")
+}
+
+func TestNamingConventions(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+namingConventions:
+  conventions:
+  - pattern: '^[a-z0-9]([-a-z0-9]*[a-z0-9])?$'
+  - pattern: '^acme-'
+    kinds:
+    - Deployment
+`))
+	assert.NoError(t, err)
+	assert.Len(t, parsedConf.NamingConventions.GetConventionsForKind("Deployment"), 2)
+	assert.Len(t, parsedConf.NamingConventions.GetConventionsForKind("Service"), 1)
+	acme := parsedConf.NamingConventions.Conventions[1]
+	assert.NotNil(t, acme.pattern, "patterns should be compiled when the config is parsed")
+	assert.True(t, acme.Matches("acme-api"))
+	assert.False(t, acme.Matches("api"))
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+namingConventions:
+  conventions:
+  - kinds:
+    - Deployment
+`))
+	assert.EqualError(t, err, "Naming conventions must specify a pattern")
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+namingConventions:
+  conventions:
+  - pattern: '['
+`))
+	assert.ErrorContains(t, err, "Invalid pattern for naming convention [")
+}
+
+func TestPolicyChecks(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+  namingConventionMismatched: warning
+environmentSeverities:
+  production:
+    namingConventionMismatched: danger
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, parsedConf.ValidateChecks())
+	assert.True(t, parsedConf.IsKnownCheck("namingConventionMismatched"))
+	assert.True(t, parsedConf.IsKnownCheck("hostIPCSet"))
+	assert.False(t, parsedConf.IsKnownCheck("namingConventionMismatch"))
+
+	_, err = Parse([]byte(`
+checks:
+  namingConventionMismatched: warning
+customChecks:
+  namingConventionMismatched:
+    successMessage: ok
+    failureMessage: bad
+    category: Reliability
+    target: Controller
+    schema:
+      type: object
+`))
+	assert.EqualError(t, err, "Custom check namingConventionMismatched has the same ID as a built-in check")
+}
+
+func TestWasmChecks(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+wasmChecks:
+  teamLabel: danger
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityDanger, parsedConf.GetWasmCheckSeverity("teamLabel"))
+	assert.Equal(t, SeverityWarning, parsedConf.GetWasmCheckSeverity("otherCheck"))
+
+	_, err = Parse([]byte(`
+checks:
+  hostIPCSet: danger
+wasmChecks:
+  teamLabel: critical
+`))
+	assert.EqualError(t, err, "Invalid severity critical for WebAssembly check teamLabel, should be one of ignore, warning, or danger")
+}
+
+type testWasmModule string
+
+func (m testWasmModule) ID() string {
+	return string(m)
+}
+
+func (m testWasmModule) Run(ctx context.Context, resource []byte) (WasmResult, error) {
+	return WasmResult{Success: true}, nil
+}
+
+func TestAddWasmModule(t *testing.T) {
+	parsedConf, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+wasmChecks:
+  teamLabel: danger
+`))
+	assert.NoError(t, err)
+	assert.False(t, parsedConf.IsKnownCheck("teamLabel"))
+	assert.NoError(t, parsedConf.AddWasmModule(testWasmModule("teamLabel")))
+	assert.NoError(t, parsedConf.AddWasmModule(testWasmModule("otherCheck")))
+	assert.True(t, parsedConf.IsKnownCheck("teamLabel"))
+	assert.Equal(t, SeverityDanger, parsedConf.Checks["teamLabel"])
+	assert.Equal(t, SeverityWarning, parsedConf.Checks["otherCheck"], "severity should default to warning")
+	assert.NoError(t, parsedConf.SetCheckSeverity("otherCheck", SeverityIgnore))
+
+	assert.EqualError(t, parsedConf.AddWasmModule(testWasmModule("hostIPCSet")), "hostIPCSet has the same ID as an existing check")
+	assert.EqualError(t, parsedConf.AddWasmModule(testWasmModule("teamLabel")), "teamLabel has the same ID as an existing check")
+}
+
+func TestParallelism(t *testing.T) {
+	assert.Equal(t, runtime.GOMAXPROCS(0), Configuration{}.GetParallelism())
+	assert.Equal(t, 3, Configuration{Parallelism: 3}.GetParallelism())
+
+	_, err := Parse([]byte(`
+checks:
+  hostIPCSet: danger
+parallelism: -1
+`))
+	assert.EqualError(t, err, "Invalid parallelism -1, should be at least 1")
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	packr "github.com/gobuffalo/packr/v2"
+	"github.com/thoas/go-funk"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DeprecatedAPIPolicy configures reporting resources which use APIs removed in the Kubernetes version being upgraded to.
+// The check is turned on by giving deprecatedAPIVersion a severity in checks.
+type DeprecatedAPIPolicy struct {
+	// TargetVersion is the Kubernetes version to check against, e.g. 1.25. The check is off when it's empty.
+	TargetVersion string `json:"targetVersion"`
+}
+
+// RemovedAPI is an API version which some kinds can't be used with since a Kubernetes version
+type RemovedAPI struct {
+	APIVersion string   `json:"apiVersion"`
+	Kinds      []string `json:"kinds"`
+	RemovedIn  string   `json:"removedIn"`
+	// Replacement is the API version to use instead, or empty if the kind was removed
+	Replacement string `json:"replacement"`
+}
+
+type removedAPITable struct {
+	APIs []RemovedAPI `json:"apis"`
+}
+
+var removedAPIs []RemovedAPI
+
+func init() {
+	box := packr.New("Deprecations", "../../checks/deprecations")
+	contents, err := box.Find("apis.yaml")
+	if err != nil {
+		panic(err)
+	}
+	table := removedAPITable{}
+	if err := yaml.Unmarshal(contents, &table); err != nil {
+		panic(fmt.Errorf("Decoding removed APIs failed: %v", err))
+	}
+	removedAPIs = table.APIs
+}
+
+// Validate checks the target version
+func (p DeprecatedAPIPolicy) Validate() error {
+	if p.TargetVersion != "" {
+		if _, _, err := parseKubernetesVersion(p.TargetVersion); err != nil {
+			return fmt.Errorf("Invalid targetVersion for deprecatedAPIPolicy: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetRemovedAPI returns the removed API the kind uses, if it was removed in or before the target version
+func (p DeprecatedAPIPolicy) GetRemovedAPI(apiVersion, kind string) *RemovedAPI {
+	targetMajor, targetMinor, err := parseKubernetesVersion(p.TargetVersion)
+	if err != nil {
+		return nil
+	}
+	for _, api := range removedAPIs {
+		if api.APIVersion != apiVersion || !funk.ContainsString(api.Kinds, kind) {
+			continue
+		}
+		major, minor, err := parseKubernetesVersion(api.RemovedIn)
+		if err != nil {
+			continue
+		}
+		if major < targetMajor || major == targetMajor && minor <= targetMinor {
+			removed := api
+			return &removed
+		}
+	}
+	return nil
+}
+
+// parseKubernetesVersion returns the major and minor version of a Kubernetes version, e.g. 1.25, v1.25, or 1.25.3
+func parseKubernetesVersion(version string) (int, int, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, fmt.Errorf("%s should be a Kubernetes version like 1.25", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s should be a Kubernetes version like 1.25", version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s should be a Kubernetes version like 1.25", version)
+	}
+	return major, minor, nil
+}
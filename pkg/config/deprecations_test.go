@@ -0,0 +1,41 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRemovedAPI(t *testing.T) {
+	policy := DeprecatedAPIPolicy{TargetVersion: "1.25"}
+	removed := policy.GetRemovedAPI("batch/v1beta1", "CronJob")
+	if assert.NotNil(t, removed) {
+		assert.Equal(t, "1.25", removed.RemovedIn)
+		assert.Equal(t, "batch/v1", removed.Replacement)
+	}
+	assert.Nil(t, policy.GetRemovedAPI("batch/v1", "CronJob"))
+	assert.Nil(t, policy.GetRemovedAPI("autoscaling/v2beta2", "HorizontalPodAutoscaler"), "removed in 1.26")
+	assert.Nil(t, policy.GetRemovedAPI("extensions/v1beta1", "Job"), "only the listed kinds were removed")
+	assert.NotNil(t, DeprecatedAPIPolicy{TargetVersion: "1.26.1"}.GetRemovedAPI("autoscaling/v2beta2", "HorizontalPodAutoscaler"))
+	assert.NotNil(t, DeprecatedAPIPolicy{TargetVersion: "2.0"}.GetRemovedAPI("apps/v1beta1", "Deployment"))
+}
+
+func TestValidateDeprecatedAPIPolicy(t *testing.T) {
+	assert.NoError(t, DeprecatedAPIPolicy{}.Validate())
+	assert.NoError(t, DeprecatedAPIPolicy{TargetVersion: "v1.27.2"}.Validate())
+	assert.EqualError(t, DeprecatedAPIPolicy{TargetVersion: "latest"}.Validate(), "Invalid targetVersion for deprecatedAPIPolicy: latest should be a Kubernetes version like 1.25")
+}
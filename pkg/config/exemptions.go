@@ -22,11 +22,16 @@ import (
 
 // IsActionable determines whether a check is actionable given the current configuration
 func (conf Configuration) IsActionable(ruleID string, objMeta metav1.Object, containerName string) bool {
-	if severity, ok := conf.Checks[ruleID]; !ok || !severity.IsActionable() {
+	if severity, ok := conf.GetSeverity(ruleID, objMeta); !ok || !severity.IsActionable() {
 		return false
 	}
+	return !conf.IsExempt(ruleID, objMeta, containerName)
+}
+
+// IsExempt determines whether the configured exemptions apply to a check for the given resource
+func (conf Configuration) IsExempt(ruleID string, objMeta metav1.Object, containerName string) bool {
 	if conf.DisallowExemptions || conf.DisallowConfigExemptions {
-		return true
+		return false
 	}
 	for _, exemption := range conf.Exemptions {
 		if exemption.Namespace != "" && exemption.Namespace != objMeta.GetNamespace() {
@@ -47,11 +52,11 @@ func (conf Configuration) IsActionable(ruleID string, objMeta metav1.Object, con
 				continue
 			}
 			if isExemptionCheckMatched(exemption.ContainerNames, containerName) {
-				return false
+				return true
 			}
 		}
 	}
-	return true
+	return false
 }
 
 func isExemptionCheckMatched(arr []string, predicate string) bool {
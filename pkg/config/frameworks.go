@@ -0,0 +1,97 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gobuffalo/packr/v2"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Control is a single requirement of a compliance framework
+type Control struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Framework is a compliance framework, such as the CIS Kubernetes Benchmark, and the controls it defines
+type Framework struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Controls []Control `json:"controls"`
+}
+
+var frameworkBox = (*packr.Box)(nil)
+
+func getFrameworkBox() *packr.Box {
+	if frameworkBox == (*packr.Box)(nil) {
+		frameworkBox = packr.New("Frameworks", "../../checks/frameworks")
+	}
+	return frameworkBox
+}
+
+// GetFrameworks returns the IDs of the built-in compliance frameworks
+func GetFrameworks() []string {
+	frameworks := []string{}
+	for _, file := range getFrameworkBox().List() {
+		if strings.HasSuffix(file, ".yaml") {
+			frameworks = append(frameworks, strings.TrimSuffix(file, ".yaml"))
+		}
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+// GetFramework loads the control table for a built-in compliance framework
+func GetFramework(id string) (Framework, error) {
+	framework := Framework{}
+	contents, err := getFrameworkBox().Find(id + ".yaml")
+	if err != nil {
+		return framework, fmt.Errorf("Unknown compliance framework %s, available frameworks are: %s", id, strings.Join(GetFrameworks(), ", "))
+	}
+	err = yaml.Unmarshal(contents, &framework)
+	if err != nil {
+		return framework, fmt.Errorf("Decoding compliance framework %s failed: %v", id, err)
+	}
+	framework.ID = id
+	return framework, nil
+}
+
+// GetControlChecks maps each control ID of a framework to the enabled checks that cover it
+func (conf Configuration) GetControlChecks(frameworkID string) map[string][]string {
+	controlChecks := map[string][]string{}
+	for checkID, severity := range conf.Checks {
+		if severity == SeverityIgnore {
+			continue
+		}
+		check, ok := conf.CustomChecks[checkID]
+		if !ok {
+			check, ok = BuiltInChecks[checkID]
+		}
+		if !ok {
+			continue
+		}
+		for _, controlID := range check.Controls[frameworkID] {
+			controlChecks[controlID] = append(controlChecks[controlID], checkID)
+		}
+	}
+	for _, checkIDs := range controlChecks {
+		sort.Strings(checkIDs)
+	}
+	return controlChecks
+}
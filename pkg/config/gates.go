@@ -0,0 +1,54 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Gate holds the thresholds an audit has to meet, e.g. for a particular CI stage.
+// Unset maximums aren't enforced.
+type Gate struct {
+	MaxDangers  *int `json:"maxDangers"`
+	MaxWarnings *int `json:"maxWarnings"`
+	MinScore    int  `json:"minScore"`
+}
+
+// GetGate returns the named gate
+func (conf Configuration) GetGate(name string) (Gate, error) {
+	gate, ok := conf.Gates[name]
+	if !ok {
+		gates := []string{}
+		for gateName := range conf.Gates {
+			gates = append(gates, gateName)
+		}
+		sort.Strings(gates)
+		return gate, fmt.Errorf("Unknown gate %s, available gates are: %s", name, strings.Join(gates, ", "))
+	}
+	return gate, nil
+}
+
+// Validate makes sure the gate's maximums aren't negative
+func (g Gate) Validate(name string) error {
+	if g.MaxDangers != nil && *g.MaxDangers < 0 {
+		return fmt.Errorf("Invalid maxDangers %d for gate %s, should be at least 0", *g.MaxDangers, name)
+	}
+	if g.MaxWarnings != nil && *g.MaxWarnings < 0 {
+		return fmt.Errorf("Invalid maxWarnings %d for gate %s, should be at least 0", *g.MaxWarnings, name)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// JSONSchemaURI is the JSON Schema draft the config schema is written for
+const JSONSchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	severityType   = reflect.TypeOf(Severity(""))
+	priorityType   = reflect.TypeOf(Priority(""))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// typeEnums lists the values accepted by string types with a fixed set of values
+var typeEnums = map[reflect.Type][]interface{}{
+	severityType: {SeverityIgnore, SeverityWarning, SeverityDanger},
+	priorityType: {PriorityMustFix, PriorityNeutral, PriorityNiceToHave},
+}
+
+// GetJSONSchema returns a JSON Schema describing the config file, generated from the Configuration
+// struct, so editors can validate and complete config files.
+func GetJSONSchema() map[string]interface{} {
+	schema := getTypeJSONSchema(reflect.TypeOf(Configuration{}))
+	schema["$schema"] = JSONSchemaURI
+	schema["title"] = "Polaris configuration"
+	properties := schema["properties"].(map[string]interface{})
+	// checks entries are either a severity or an object which also limits the check to some kinds
+	properties["checks"] = map[string]interface{}{
+		"type": "object",
+		"additionalProperties": map[string]interface{}{
+			"oneOf": []interface{}{
+				getTypeJSONSchema(severityType),
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"severity": getTypeJSONSchema(severityType),
+						"kinds":    getTypeJSONSchema(reflect.TypeOf([]string{})),
+					},
+					"required":             []string{"severity"},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+	// profiles are overlays of the config itself
+	properties["profiles"] = map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"$ref": "#"},
+	}
+	return schema
+}
+
+// getTypeJSONSchema returns the JSON Schema of values decoded into the type
+func getTypeJSONSchema(t reflect.Type) map[string]interface{} {
+	if enum, ok := typeEnums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": enum}
+	}
+	if t == rawMessageType {
+		return map[string]interface{}{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return getTypeJSONSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": getTypeJSONSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": getTypeJSONSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := getJSONSchemaFieldName(field)
+			if name == "" {
+				continue
+			}
+			properties[name] = getTypeJSONSchema(field.Type)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+	return map[string]interface{}{}
+}
+
+// getJSONSchemaFieldName returns the key a struct field is read from, or an empty string if it isn't read
+// from the config. Fields without tags are matched case-insensitively, so they're documented in lower camel case.
+func getJSONSchemaFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	for _, tag := range []string{"json", "yaml"} {
+		if value, ok := field.Tag.Lookup(tag); ok {
+			name := strings.Split(value, ",")[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+	}
+	runes := []rune(field.Name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
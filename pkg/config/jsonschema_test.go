@@ -0,0 +1,93 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/qri-io/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func validateAgainstJSONSchema(t *testing.T, configYAML []byte) []jsonschema.ValError {
+	schemaJSON, err := json.Marshal(GetJSONSchema())
+	assert.NoError(t, err)
+	rs := jsonschema.RootSchema{}
+	assert.NoError(t, json.Unmarshal(schemaJSON, &rs))
+	configJSON, err := yaml.YAMLToJSON(configYAML)
+	assert.NoError(t, err)
+	errs, err := rs.ValidateBytes(configJSON)
+	assert.NoError(t, err)
+	return errs
+}
+
+func TestGetJSONSchema(t *testing.T) {
+	schema := GetJSONSchema()
+	assert.Equal(t, JSONSchemaURI, schema["$schema"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.NotContains(t, properties, "CheckKinds")
+	assert.NotContains(t, properties, "WasmModules")
+
+	customChecks := properties["customChecks"].(map[string]interface{})
+	customCheck := customChecks["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, customCheck, "schemaString")
+	assert.NotContains(t, customCheck, "Validator")
+	controllers := customCheck["controllers"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, controllers, "include")
+	assert.Contains(t, controllers, "exclude")
+	mutations := customCheck["mutations"].(map[string]interface{})["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, mutations, "path")
+	assert.Contains(t, mutations, "op")
+	assert.Equal(t, []interface{}{PriorityMustFix, PriorityNeutral, PriorityNiceToHave}, customCheck["priority"].(map[string]interface{})["enum"])
+}
+
+func TestExampleConfigsMatchJSONSchema(t *testing.T) {
+	for _, path := range []string{"../../examples/config.yaml", "../../examples/config-full.yaml"} {
+		configYAML, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Empty(t, validateAgainstJSONSchema(t, configYAML), path)
+	}
+}
+
+func TestJSONSchemaRejectsInvalidConfig(t *testing.T) {
+	assert.Empty(t, validateAgainstJSONSchema(t, []byte(`
+checks:
+  hostIPCSet: danger
+  runAsRootAllowed: {severity: warning, kinds: [Deployment]}
+profiles:
+  strict:
+    checks:
+      runAsRootAllowed: danger
+`)))
+	assert.NotEmpty(t, validateAgainstJSONSchema(t, []byte(`
+checks:
+  hostIPCSet: critical
+`)))
+	assert.NotEmpty(t, validateAgainstJSONSchema(t, []byte(`
+checks:
+  hostIPCSet: danger
+chekcs:
+  hostPIDSet: danger
+`)))
+	assert.NotEmpty(t, validateAgainstJSONSchema(t, []byte(`
+profiles:
+  strict:
+    checks:
+      hostIPCSet: critical
+`)))
+}
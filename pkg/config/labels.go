@@ -0,0 +1,95 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/thoas/go-funk"
+)
+
+// RequiredLabels configures labels which resources must have, e.g. for a tagging standard
+type RequiredLabels struct {
+	Labels []RequiredLabel `json:"labels"`
+}
+
+// RequiredLabel is a label key which must be set, optionally with a value matching a pattern
+type RequiredLabel struct {
+	Key string `json:"key"`
+	// Pattern is a regular expression the value has to match
+	Pattern string `json:"pattern"`
+	// Kinds limits the label to resources of these kinds. It's required on every kind when empty.
+	Kinds []string `json:"kinds"`
+
+	pattern *regexp.Regexp
+}
+
+// GetLabelsForKind returns the labels required on resources of a kind
+func (r RequiredLabels) GetLabelsForKind(kind string) []RequiredLabel {
+	labels := []RequiredLabel{}
+	for _, label := range r.Labels {
+		if len(label.Kinds) == 0 || funk.ContainsString(label.Kinds, kind) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Initialize compiles the label patterns, so they aren't compiled again for every resource
+func (r *RequiredLabels) Initialize() error {
+	for idx, label := range r.Labels {
+		if label.Pattern == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(label.Pattern)
+		if err != nil {
+			return fmt.Errorf("Invalid pattern for required label %s: %v", label.Key, err)
+		}
+		r.Labels[idx].pattern = pattern
+	}
+	return nil
+}
+
+// MatchesPattern checks that the value matches the label's pattern, if it has one
+func (l RequiredLabel) MatchesPattern(value string) bool {
+	if l.Pattern == "" {
+		return true
+	}
+	pattern := l.pattern
+	if pattern == nil {
+		// the config wasn't initialized, e.g. when it's built in code
+		var err error
+		pattern, err = regexp.Compile(l.Pattern)
+		if err != nil {
+			return false
+		}
+	}
+	return pattern.MatchString(value)
+}
+
+// Validate checks that every label has a key and a valid pattern
+func (r RequiredLabels) Validate() error {
+	for _, label := range r.Labels {
+		if label.Key == "" {
+			return errors.New("Required labels must specify a key")
+		}
+		if _, err := regexp.Compile(label.Pattern); err != nil {
+			return fmt.Errorf("Invalid pattern for required label %s: %v", label.Key, err)
+		}
+	}
+	return nil
+}
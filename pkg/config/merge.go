@@ -0,0 +1,108 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// ParseFiles parses and merges config files in order, so later files override earlier ones as described by Merge.
+// Without any paths, the default config is used.
+func ParseFiles(paths []string) (Configuration, error) {
+	return ParseFilesWithClient(http.DefaultClient, paths)
+}
+
+// ParseFilesWithClient is ParseFiles, fetching any config given as a URL with client, e.g. one with a timeout
+func ParseFilesWithClient(client *http.Client, paths []string) (Configuration, error) {
+	if len(paths) == 0 {
+		return parseFile(client, "")
+	}
+	if len(paths) == 1 {
+		return parseFile(client, paths[0])
+	}
+	merged := Configuration{}
+	for _, path := range paths {
+		rawBytes, err := readFile(client, path)
+		if err != nil {
+			return Configuration{}, err
+		}
+		conf, err := decode(rawBytes)
+		if err != nil {
+			return Configuration{}, fmt.Errorf("%s: %w", path, err)
+		}
+		merged.Merge(conf)
+	}
+	return merged, merged.initialize()
+}
+
+// Merge overlays other onto the config, e.g. a team's overrides onto an organization's base config.
+// Values set in other take precedence:
+//   - Scalars, e.g. displayName or a check's severity, are overwritten when they're set in other. Unset values,
+//     like false or empty strings, don't overwrite the config's values.
+//   - Maps, e.g. checks and customChecks, are merged by key, and an entry in other replaces the config's entry.
+//     Maps of maps, like environmentSeverities, are merged the same way at each level.
+//   - Lists, e.g. exemptions and excludePaths, accumulate, with other's entries added after the config's.
+//   - Policies, e.g. requiredLabels, are merged field by field with these rules.
+//
+// Custom checks aren't initialized until the config is parsed or initialized again.
+func (conf *Configuration) Merge(other Configuration) {
+	mergeValues(reflect.ValueOf(conf).Elem(), reflect.ValueOf(other))
+}
+
+func mergeValues(dst, src reflect.Value) {
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Type().Field(i).IsExported() {
+				mergeValues(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+			if existing := dst.MapIndex(iter.Key()); value.Kind() == reflect.Map && existing.IsValid() && !existing.IsNil() {
+				// copy the entry so the maps of either config aren't changed
+				merged := reflect.MakeMap(value.Type())
+				mergeValues(merged, existing)
+				mergeValues(merged, value)
+				value = merged
+			}
+			dst.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.Slice:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.Type() == rawMessageType {
+			dst.Set(src)
+			return
+		}
+		merged := reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len())
+		dst.Set(reflect.AppendSlice(reflect.AppendSlice(merged, dst), src))
+	default:
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var confMergeBase = `
+displayName: acme
+checks:
+  hostIPCSet: danger
+  cpuLimitsMissing: warning
+exemptions:
+- rules: [hostIPCSet]
+  controllerNames: [node-exporter]
+requiredLabels:
+  labels:
+  - key: team
+imageScanPolicy:
+  maxAgeDays: 30
+environmentSeverities:
+  prod:
+    cpuLimitsMissing: danger
+`
+
+var confMergeTeam = `
+checks:
+  cpuLimitsMissing: ignore
+  teamImageRegistry: danger
+customChecks:
+  teamImageRegistry:
+    successMessage: Images are from the team registry
+    failureMessage: Images should be from the team registry
+    category: Security
+    target: Container
+    schema:
+      '$schema': http://json-schema.org/draft-07/schema
+      type: object
+      properties:
+        image:
+          type: string
+          pattern: ^registry.example.com/payments/
+exemptions:
+- rules: [cpuLimitsMissing]
+  controllerNames: [batch]
+requiredLabels:
+  labels:
+  - key: cost-center
+environmentSeverities:
+  prod:
+    hostIPCSet: warning
+  dev:
+    hostIPCSet: ignore
+`
+
+func TestMerge(t *testing.T) {
+	base, err := decode([]byte(confMergeBase))
+	assert.NoError(t, err)
+	team, err := decode([]byte(confMergeTeam))
+	assert.NoError(t, err)
+	base.Merge(team)
+
+	assert.Equal(t, "acme", base.DisplayName, "unset scalars shouldn't overwrite")
+	assert.Equal(t, map[string]Severity{"hostIPCSet": SeverityDanger, "cpuLimitsMissing": SeverityIgnore, "teamImageRegistry": SeverityDanger}, base.Checks)
+	assert.Contains(t, base.CustomChecks, "teamImageRegistry")
+	assert.Equal(t, 2, len(base.Exemptions))
+	assert.Equal(t, []string{"node-exporter"}, base.Exemptions[0].ControllerNames)
+	assert.Equal(t, []string{"batch"}, base.Exemptions[1].ControllerNames)
+	assert.Equal(t, 30, base.ImageScanPolicy.MaxAgeDays)
+	assert.Equal(t, 2, len(base.RequiredLabels.Labels))
+	assert.Equal(t, map[string]map[string]Severity{
+		"prod": {"cpuLimitsMissing": SeverityDanger, "hostIPCSet": SeverityWarning},
+		"dev":  {"hostIPCSet": SeverityIgnore},
+	}, base.EnvironmentSeverities)
+	assert.Equal(t, map[string]Severity{"hostIPCSet": SeverityWarning}, team.EnvironmentSeverities["prod"], "the merged config shouldn't be changed")
+}
+
+func TestParseFiles(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.yaml")
+	teamPath := filepath.Join(dir, "team.yaml")
+	assert.NoError(t, os.WriteFile(basePath, []byte(confMergeBase), 0644))
+	assert.NoError(t, os.WriteFile(teamPath, []byte(confMergeTeam), 0644))
+
+	parsedConf, err := ParseFiles([]string{basePath, teamPath})
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityIgnore, parsedConf.Checks["cpuLimitsMissing"])
+	assert.Equal(t, "teamImageRegistry", parsedConf.CustomChecks["teamImageRegistry"].ID, "custom checks should be initialized")
+
+	parsedConf, err = ParseFiles([]string{teamPath, basePath})
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityWarning, parsedConf.Checks["cpuLimitsMissing"])
+
+	_, err = ParseFiles([]string{basePath, filepath.Join(dir, "missing.yaml")})
+	assert.ErrorContains(t, err, "missing.yaml")
+
+	defaultConf, err := ParseFiles(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityDanger, defaultConf.Checks["hostIPCSet"])
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/thoas/go-funk"
+)
+
+// NamingConventions configures patterns which resource names must match, e.g. DNS-1123 with a team prefix.
+// They're checked when namingConventionMismatched has a severity in checks.
+type NamingConventions struct {
+	Conventions []NamingConvention `json:"conventions"`
+}
+
+// NamingConvention is a regular expression names have to match
+type NamingConvention struct {
+	Pattern string `json:"pattern"`
+	// Kinds limits the convention to resources of these kinds. It applies to every kind when empty.
+	Kinds []string `json:"kinds"`
+
+	pattern *regexp.Regexp
+}
+
+// GetConventionsForKind returns the conventions the names of resources of a kind must follow
+func (n NamingConventions) GetConventionsForKind(kind string) []NamingConvention {
+	conventions := []NamingConvention{}
+	for _, convention := range n.Conventions {
+		if len(convention.Kinds) == 0 || funk.ContainsString(convention.Kinds, kind) {
+			conventions = append(conventions, convention)
+		}
+	}
+	return conventions
+}
+
+// Initialize compiles the convention patterns, so they aren't compiled again for every resource
+func (n *NamingConventions) Initialize() error {
+	for idx, convention := range n.Conventions {
+		pattern, err := regexp.Compile(convention.Pattern)
+		if err != nil {
+			return fmt.Errorf("Invalid pattern for naming convention %s: %v", convention.Pattern, err)
+		}
+		n.Conventions[idx].pattern = pattern
+	}
+	return nil
+}
+
+// Matches checks that the name matches the convention's pattern
+func (c NamingConvention) Matches(name string) bool {
+	pattern := c.pattern
+	if pattern == nil {
+		// the config wasn't initialized, e.g. when it's built in code
+		var err error
+		pattern, err = regexp.Compile(c.Pattern)
+		if err != nil {
+			return false
+		}
+	}
+	return pattern.MatchString(name)
+}
+
+// Validate checks that every convention has a valid pattern
+func (n NamingConventions) Validate() error {
+	for _, convention := range n.Conventions {
+		if convention.Pattern == "" {
+			return errors.New("Naming conventions must specify a pattern")
+		}
+		if _, err := regexp.Compile(convention.Pattern); err != nil {
+			return fmt.Errorf("Invalid pattern for naming convention %s: %v", convention.Pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Owner assigns the resources in a set of namespaces to a team
+type Owner struct {
+	Team       string   `json:"team"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// GetOwner returns the team owning a resource. The owner label takes precedence
+// over namespace-based owners. An empty string is returned for unowned resources.
+func (conf Configuration) GetOwner(objMeta metav1.Object) string {
+	if objMeta == nil {
+		return ""
+	}
+	if conf.OwnerLabel != "" {
+		if team := objMeta.GetLabels()[conf.OwnerLabel]; team != "" {
+			return team
+		}
+	}
+	for _, owner := range conf.Owners {
+		for _, namespace := range owner.Namespaces {
+			if namespace == objMeta.GetNamespace() {
+				return owner.Team
+			}
+		}
+	}
+	return ""
+}
+
+// GetTeams returns the teams listed in the owners configuration
+func (conf Configuration) GetTeams() []string {
+	teams := []string{}
+	for _, owner := range conf.Owners {
+		teams = append(teams, owner.Team)
+	}
+	return teams
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var fieldPathSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_\-/]+$`)
+
+// ParseFieldPath splits a dot-separated field path (e.g. spec.template.spec) into its segments
+func ParseFieldPath(path string) ([]string, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("field path cannot be empty")
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if !fieldPathSegmentRegex.MatchString(segment) {
+			return nil, fmt.Errorf("invalid segment %q in field path %s", segment, path)
+		}
+	}
+	return segments, nil
+}
+
+// GetFieldAtPath looks up the value at a dot-separated field path in arbitrary data.
+// Numeric segments index into lists. Returns nil if the path does not exist.
+func GetFieldAtPath(obj interface{}, segments []string) interface{} {
+	current := obj
+	for _, segment := range segments {
+		switch typed := current.(type) {
+		case map[string]interface{}:
+			current = typed[segment]
+		case []interface{}:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(typed) {
+				return nil
+			}
+			current = typed[idx]
+		default:
+			return nil
+		}
+	}
+	return current
+}
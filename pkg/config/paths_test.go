@@ -0,0 +1,46 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	segments, err := ParseFieldPath("spec.workload.containers.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"spec", "workload", "containers", "0"}, segments)
+
+	for _, invalid := range []string{"", "spec..template", ".spec", "spec.", "spec.$ref", "spec[0]"} {
+		_, err = ParseFieldPath(invalid)
+		assert.Error(t, err, "expected error for path %q", invalid)
+	}
+}
+
+func TestGetFieldAtPath(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "first"},
+			},
+		},
+	}
+	assert.Equal(t, "first", GetFieldAtPath(obj, []string{"spec", "containers", "0", "name"}))
+	assert.Nil(t, GetFieldAtPath(obj, []string{"spec", "containers", "1"}))
+	assert.Nil(t, GetFieldAtPath(obj, []string{"spec", "missing", "name"}))
+	assert.Equal(t, obj, GetFieldAtPath(obj, []string{}))
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Priority describes how actionable a finding is, independent of its severity
+type Priority string
+
+const (
+	// PriorityMustFix marks findings which should be fixed before anything else
+	PriorityMustFix Priority = "must-fix"
+
+	// PriorityNeutral is the default priority
+	PriorityNeutral Priority = "neutral"
+
+	// PriorityNiceToHave marks findings which can be fixed when convenient
+	PriorityNiceToHave Priority = "nice-to-have"
+)
+
+var priorityRanks = map[Priority]int{
+	PriorityNiceToHave: 0,
+	PriorityNeutral:    1,
+	PriorityMustFix:    2,
+}
+
+// ParsePriority returns the priority with the given name
+func ParsePriority(name string) (Priority, error) {
+	priority := Priority(name)
+	if _, ok := priorityRanks[priority]; !ok {
+		return priority, fmt.Errorf("Invalid priority %s, should be one of must-fix, neutral, or nice-to-have", name)
+	}
+	return priority, nil
+}
+
+// IsAtLeast returns true if the priority is the same as or above the minimum.
+// An empty priority is treated as neutral.
+func (priority Priority) IsAtLeast(minimum Priority) bool {
+	if priority == "" {
+		priority = PriorityNeutral
+	}
+	return priorityRanks[priority] >= priorityRanks[minimum]
+}
+
+// GetPriority returns the priority of a check, from the priorities in the config,
+// then the priority in the check definition, defaulting to neutral
+func (conf Configuration) GetPriority(checkID string) Priority {
+	if priority, ok := conf.Priorities[checkID]; ok {
+		return priority
+	}
+	if check, ok := conf.CustomChecks[checkID]; ok && check.Priority != "" {
+		return check.Priority
+	}
+	if check, ok := BuiltInChecks[checkID]; ok && check.Priority != "" {
+		return check.Priority
+	}
+	return PriorityNeutral
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// GetRemediations maps each enabled check ID to the remediation action that fixes it.
+// Checks without remediation metadata are left out.
+func (conf Configuration) GetRemediations() map[string]string {
+	remediations := map[string]string{}
+	for checkID, severity := range conf.Checks {
+		if severity == SeverityIgnore {
+			continue
+		}
+		check, ok := conf.CustomChecks[checkID]
+		if !ok {
+			check, ok = BuiltInChecks[checkID]
+		}
+		if !ok || check.Remediation == "" {
+			continue
+		}
+		remediations[checkID] = check.Remediation
+	}
+	return remediations
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRemediations(t *testing.T) {
+	conf := Configuration{
+		Checks: map[string]Severity{
+			"cpuLimitsMissing":    SeverityWarning,
+			"memoryLimitsMissing": SeverityDanger,
+			"hostIPCSet":          SeverityIgnore,
+			"customCheck":         SeverityWarning,
+			"noRemediation":       SeverityWarning,
+		},
+		CustomChecks: map[string]SchemaCheck{
+			"customCheck":   {Remediation: "Do the custom thing"},
+			"noRemediation": {},
+		},
+	}
+	assert.Equal(t, map[string]string{
+		"cpuLimitsMissing":    "Add resource limits",
+		"memoryLimitsMissing": "Add resource limits",
+		"customCheck":         "Do the custom thing",
+	}, conf.GetRemediations())
+}
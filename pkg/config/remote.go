@@ -0,0 +1,50 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// AuthorizationEnvVar is the environment variable holding the Authorization header sent when fetching config from a URL
+const AuthorizationEnvVar = "POLARIS_CONFIG_AUTHORIZATION"
+
+// ParseURL fetches config from a URL with the given client and parses it.
+// The Authorization header is set from POLARIS_CONFIG_AUTHORIZATION, for protected endpoints.
+func ParseURL(client *http.Client, url string) (Configuration, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Configuration{}, err
+	}
+	if authorization := os.Getenv(AuthorizationEnvVar); authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Configuration{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Configuration{}, fmt.Errorf("fetching config returned status %s", resp.Status)
+	}
+	rawBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Configuration{}, err
+	}
+	return Parse(rawBytes)
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseURL(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if r.URL.Path != "/config.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(confValidYAML))
+	}))
+	defer server.Close()
+
+	t.Setenv(AuthorizationEnvVar, "Bearer secret")
+	parsedConf, err := ParseURL(server.Client(), server.URL+"/config.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityWarning, parsedConf.Checks["cpuRequestsMissing"])
+	assert.Equal(t, "Bearer secret", authorization)
+
+	_, err = ParseURL(server.Client(), server.URL+"/missing.yaml")
+	assert.EqualError(t, err, "fetching config returned status 404 Not Found")
+}
+
+func TestParseURLWithoutAuthorization(t *testing.T) {
+	authorization := "unset"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(confValidYAML))
+	}))
+	defer server.Close()
+
+	t.Setenv(AuthorizationEnvVar, "")
+	_, err := ParseURL(server.Client(), server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "", authorization)
+}
@@ -20,9 +20,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/google/cel-go/cel"
 	"github.com/qri-io/jsonschema"
 	"github.com/thoas/go-funk"
 	corev1 "k8s.io/api/core/v1"
@@ -66,6 +68,8 @@ type SchemaCheck struct {
 	Category                string                            `yaml:"category" json:"category"`
 	SuccessMessage          string                            `yaml:"successMessage" json:"successMessage"`
 	FailureMessage          string                            `yaml:"failureMessage" json:"failureMessage"`
+	TemplateMessages        bool                              `yaml:"templateMessages" json:"templateMessages"`
+	Remediation             string                            `yaml:"remediation" json:"remediation"`
 	Controllers             includeExcludeList                `yaml:"controllers" json:"controllers"`
 	Containers              includeExcludeList                `yaml:"containers" json:"containers"`
 	Target                  TargetKind                        `yaml:"target" json:"target"`
@@ -77,6 +81,14 @@ type SchemaCheck struct {
 	AdditionalSchemaStrings map[string]string                 `yaml:"additionalSchemaStrings" json:"additionalSchemaStrings"`
 	AdditionalValidators    map[string]jsonschema.RootSchema  `yaml:"-" json:"-"`
 	Mutations               []Mutation                        `yaml:"mutations" json:"mutations"`
+	Controls                map[string][]string               `yaml:"controls" json:"controls"`
+	Priority                Priority                          `yaml:"priority" json:"priority"`
+	CEL                     string                            `yaml:"cel" json:"cel"`
+
+	// celProgram is the compiled CEL expression
+	celProgram cel.Program
+	// celResource is the resource the check was templated for, available to CEL expressions as resource
+	celResource interface{}
 }
 
 type resourceMinimum string
@@ -187,7 +199,16 @@ func validateRange(path string, limit interface{}, data interface{}, isMinimum b
 // Initialize sets up the schema
 func (check *SchemaCheck) Initialize(id string) error {
 	check.ID = id
-	if check.SchemaString == "" {
+	if check.CEL != "" {
+		if len(check.Schema) > 0 || check.SchemaString != "" {
+			return fmt.Errorf("Check %s can only specify one of schema, schemaString, and cel", id)
+		}
+		program, err := compileCEL(check.CEL)
+		if err != nil {
+			return fmt.Errorf("Invalid CEL expression for check %s: %v", id, err)
+		}
+		check.celProgram = program
+	} else if check.SchemaString == "" {
 		jsonBytes, err := json.Marshal(check.Schema)
 		if err != nil {
 			return err
@@ -209,9 +230,34 @@ func (check *SchemaCheck) Initialize(id string) error {
 	return nil
 }
 
+// GetCheckedFields returns the top-level fields that the check's schema inspects, i.e. those
+// listed under properties or required. The schema should already be templated.
+func (check SchemaCheck) GetCheckedFields() []string {
+	schema := map[string]interface{}{}
+	if err := k8sYaml.Unmarshal([]byte(check.SchemaString), &schema); err != nil {
+		return nil
+	}
+	fields := []string{}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for field := range properties {
+			fields = append(fields, field)
+		}
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, field := range required {
+			if name, ok := field.(string); ok && !funk.ContainsString(fields, name) {
+				fields = append(fields, name)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 // TemplateForResource fills out a check's templated fields given a particular resource
 func (check SchemaCheck) TemplateForResource(res interface{}) (*SchemaCheck, error) {
 	newCheck := check // Make a copy of the check, since we're going to modify the schema
+	newCheck.celResource = res
 
 	templateStrings := map[string]string{
 		"": newCheck.SchemaString,
@@ -223,20 +269,10 @@ func (check SchemaCheck) TemplateForResource(res interface{}) (*SchemaCheck, err
 	newCheck.AdditionalSchemaStrings = map[string]string{}
 
 	for kind, tmplString := range templateStrings {
-		tmpl := template.New(newCheck.ID).Funcs(template.FuncMap{
-			"hasPrefix": strings.HasPrefix,
-			"hasSuffix": strings.HasSuffix,
-		})
-		tmpl, err := tmpl.Parse(tmplString)
+		templated, err := executeCheckTemplate(newCheck.ID, tmplString, res)
 		if err != nil {
 			return nil, err
 		}
-		w := bytes.Buffer{}
-		err = tmpl.Execute(&w, res)
-		if err != nil {
-			return nil, err
-		}
-		templated := w.String()
 		if strings.TrimSpace(templated) == "" {
 			continue
 		}
@@ -248,6 +284,20 @@ func (check SchemaCheck) TemplateForResource(res interface{}) (*SchemaCheck, err
 		}
 	}
 
+	// Messages are only templated for checks which opt in, since other messages may contain {{ as text
+	if newCheck.TemplateMessages {
+		msg, err := executeCheckTemplate(newCheck.ID, newCheck.SuccessMessage, res)
+		if err != nil {
+			return nil, err
+		}
+		newCheck.SuccessMessage = strings.TrimSpace(msg)
+		msg, err = executeCheckTemplate(newCheck.ID, newCheck.FailureMessage, res)
+		if err != nil {
+			return nil, err
+		}
+		newCheck.FailureMessage = strings.TrimSpace(msg)
+	}
+
 	newCheck.AdditionalValidators = map[string]jsonschema.RootSchema{}
 	for kind, schemaStr := range newCheck.AdditionalSchemaStrings {
 		val := jsonschema.RootSchema{}
@@ -264,6 +314,60 @@ func (check SchemaCheck) TemplateForResource(res interface{}) (*SchemaCheck, err
 	return &newCheck, err
 }
 
+// ValidateSchemas makes sure the check's schemas parse. Templated schemas are only checked for template
+// syntax errors, since they can't be parsed until they're filled out for a resource.
+func (check SchemaCheck) ValidateSchemas() error {
+	schemaStrings := map[string]string{"": check.SchemaString}
+	kinds := []string{""}
+	for kind, schemaString := range check.AdditionalSchemaStrings {
+		schemaStrings[kind] = schemaString
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		schemaName := "schema"
+		if kind != "" {
+			schemaName = kind + " schema"
+		}
+		schemaString := schemaStrings[kind]
+		if strings.Contains(schemaString, "{{") {
+			if _, err := parseCheckTemplate(check.ID, schemaString); err != nil {
+				return fmt.Errorf("Invalid %s template for check %s: %v", schemaName, check.ID, err)
+			}
+			continue
+		}
+		if strings.TrimSpace(schemaString) == "" {
+			continue
+		}
+		if err := UnmarshalYAMLOrJSON([]byte(schemaString), &jsonschema.RootSchema{}); err != nil {
+			return fmt.Errorf("Invalid %s for check %s: %v", schemaName, check.ID, err)
+		}
+	}
+	return nil
+}
+
+func parseCheckTemplate(id, tmplString string) (*template.Template, error) {
+	tmpl := template.New(id).Funcs(template.FuncMap{
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"contains":  strings.Contains,
+	})
+	return tmpl.Parse(tmplString)
+}
+
+func executeCheckTemplate(id, tmplString string, res interface{}) (string, error) {
+	tmpl, err := parseCheckTemplate(id, tmplString)
+	if err != nil {
+		return "", err
+	}
+	w := bytes.Buffer{}
+	err = tmpl.Execute(&w, res)
+	if err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
+
 // CheckPodSpec checks a pod spec against the schema
 func (check SchemaCheck) CheckPodSpec(pod *corev1.PodSpec) (bool, []jsonschema.ValError, error) {
 	return check.CheckObject(pod)
@@ -287,6 +391,10 @@ func (check SchemaCheck) CheckContainer(container *corev1.Container) (bool, []js
 
 // CheckObject checks arbitrary data against the schema
 func (check SchemaCheck) CheckObject(obj interface{}) (bool, []jsonschema.ValError, error) {
+	if check.CEL != "" {
+		passes, err := check.checkCEL(obj)
+		return passes, nil, err
+	}
 	bytes, err := json.Marshal(obj)
 	if err != nil {
 		return false, nil, err
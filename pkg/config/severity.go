@@ -14,6 +14,14 @@
 
 package config
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // Severity represents the severity of action to take (Ignore, Warning, Error).
 type Severity string
 
@@ -28,7 +36,90 @@ const (
 	SeverityDanger Severity = "danger"
 )
 
+var severityRanks = map[Severity]int{
+	SeverityIgnore:  0,
+	SeverityWarning: 1,
+	SeverityDanger:  2,
+}
+
+// UnmarshalJSON accepts either a severity, e.g. `warning`, or an object with a severity,
+// e.g. `{severity: warning, kinds: [Deployment]}`. The kinds are read separately into CheckKinds.
+func (severity *Severity) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return json.Unmarshal(data, (*string)(severity))
+	}
+	setting := struct {
+		Severity string `json:"severity"`
+	}{}
+	if err := json.Unmarshal(data, &setting); err != nil {
+		return err
+	}
+	*severity = Severity(setting.Severity)
+	return nil
+}
+
+// IsAtLeast returns true if the severity is the same as or above the minimum
+func (severity Severity) IsAtLeast(minimum Severity) bool {
+	return severityRanks[severity] >= severityRanks[minimum]
+}
+
+// DefaultEnvironmentLabel is the label used to look up environment-specific severities
+const DefaultEnvironmentLabel = "environment"
+
 // IsActionable returns true if the severity level is warning or error
 func (severity *Severity) IsActionable() bool {
 	return *severity == SeverityWarning || *severity == SeverityDanger
 }
+
+// GetEnvironmentLabel returns the label key used to determine a resource's environment
+func (conf Configuration) GetEnvironmentLabel() string {
+	if conf.EnvironmentLabel == "" {
+		return DefaultEnvironmentLabel
+	}
+	return conf.EnvironmentLabel
+}
+
+// GetSeverity returns the severity of a check for a particular resource, taking
+// environment-specific severities into account
+func (conf Configuration) GetSeverity(checkID string, objMeta metav1.Object) (Severity, bool) {
+	if objMeta != nil && len(conf.EnvironmentSeverities) > 0 {
+		env := objMeta.GetLabels()[conf.GetEnvironmentLabel()]
+		if severity, ok := conf.EnvironmentSeverities[env][checkID]; ok && env != "" {
+			return severity, true
+		}
+	}
+	severity, ok := conf.Checks[checkID]
+	return severity, ok
+}
+
+// IsCheckEnabled returns true if the check has a warning or danger severity in checks, or for any environment
+func (conf Configuration) IsCheckEnabled(checkID string) bool {
+	if severity, ok := conf.Checks[checkID]; ok && severity.IsActionable() {
+		return true
+	}
+	for _, envSeverities := range conf.EnvironmentSeverities {
+		if severity, ok := envSeverities[checkID]; ok && severity.IsActionable() {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCheckSeverity sets the severity of a check for every resource, replacing the severity from the
+// config and any environment-specific severities for the check. Policy checks are enabled the same way.
+func (conf *Configuration) SetCheckSeverity(checkID string, severity Severity) error {
+	if !conf.IsKnownCheck(checkID) {
+		return fmt.Errorf("Unknown check %s", checkID)
+	}
+	if severity != SeverityIgnore && severity != SeverityWarning && severity != SeverityDanger {
+		return fmt.Errorf("Invalid severity %s for check %s, should be one of ignore, warning, or danger", severity, checkID)
+	}
+	if conf.Checks == nil {
+		conf.Checks = map[string]Severity{}
+	}
+	conf.Checks[checkID] = severity
+	for _, envSeverities := range conf.EnvironmentSeverities {
+		delete(envSeverities, checkID)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+var configurationType = reflect.TypeOf(Configuration{})
+
+// ValidateFiles checks config files more strictly than ParseFiles, e.g. before deploying them to the admission
+// controller. It returns every problem it finds rather than stopping at the first one: keys which don't match any
+// setting, checks which don't exist, invalid severities, and custom check schemas which don't parse. Like
+// ParseFiles, later files are merged onto earlier ones, so override files don't need to be valid on their own.
+func ValidateFiles(paths []string) []error {
+	return ValidateFilesWithClient(http.DefaultClient, paths)
+}
+
+// ValidateFilesWithClient is ValidateFiles, fetching any config given as a URL with client, e.g. one with a timeout
+func ValidateFilesWithClient(client *http.Client, paths []string) []error {
+	problems := []error{}
+	merged := Configuration{}
+	decoded := true
+	for _, path := range paths {
+		rawBytes, err := readFile(client, path)
+		if err != nil {
+			problems = append(problems, err)
+			decoded = false
+			continue
+		}
+		unknownKeys, err := findUnknownKeys(rawBytes)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			decoded = false
+			continue
+		}
+		for _, key := range unknownKeys {
+			problems = append(problems, fmt.Errorf("%s: Unknown key %s", path, key))
+		}
+		conf, err := decode(rawBytes)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			decoded = false
+			continue
+		}
+		merged.Merge(conf)
+	}
+	// the merged config is incomplete if a file couldn't be read or decoded
+	if !decoded {
+		return problems
+	}
+	problems = append(problems, merged.initializeAll()...)
+	problems = append(problems, merged.validateAllChecks()...)
+	checkIDs := []string{}
+	for checkID := range merged.CustomChecks {
+		checkIDs = append(checkIDs, checkID)
+	}
+	sort.Strings(checkIDs)
+	for _, checkID := range checkIDs {
+		if err := merged.CustomChecks[checkID].ValidateSchemas(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// findUnknownKeys returns the paths of keys in a config file which don't match any setting, e.g. exemptions[0].controllerName
+func findUnknownKeys(rawBytes []byte) ([]string, error) {
+	unknownKeys := []string{}
+	d := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(rawBytes), 4096)
+	for {
+		var document interface{}
+		if err := d.Decode(&document); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("Decoding config failed: %v", err)
+		}
+		unknownKeys = append(unknownKeys, findUnknownKeysOfType("", document, configurationType)...)
+	}
+	sort.Strings(unknownKeys)
+	return unknownKeys, nil
+}
+
+func findUnknownKeysOfType(path string, value interface{}, t reflect.Type) []string {
+	unknownKeys := []string{}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == rawMessageType {
+		return unknownKeys
+	}
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, entry := range typedValue {
+			entryPath := key
+			if path != "" {
+				entryPath = path + "." + key
+			}
+			switch {
+			case t == severityType:
+				// checks entries in object form, which also limit the check to some kinds
+				if key != "severity" && key != "kinds" {
+					unknownKeys = append(unknownKeys, entryPath)
+				}
+			case t.Kind() == reflect.Map:
+				unknownKeys = append(unknownKeys, findUnknownKeysOfType(entryPath, entry, t.Elem())...)
+			case t.Kind() == reflect.Struct:
+				field, ok := getFieldForKey(t, key)
+				if !ok {
+					unknownKeys = append(unknownKeys, entryPath)
+					continue
+				}
+				if t == configurationType && field.Name == "Profiles" {
+					// profiles are overlays of the config itself
+					profiles, _ := entry.(map[string]interface{})
+					for name, profile := range profiles {
+						unknownKeys = append(unknownKeys, findUnknownKeysOfType(entryPath+"."+name, profile, configurationType)...)
+					}
+					continue
+				}
+				unknownKeys = append(unknownKeys, findUnknownKeysOfType(entryPath, entry, field.Type)...)
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return unknownKeys
+		}
+		for idx, entry := range typedValue {
+			unknownKeys = append(unknownKeys, findUnknownKeysOfType(fmt.Sprintf("%s[%d]", path, idx), entry, t.Elem())...)
+		}
+	}
+	return unknownKeys
+}
+
+// getFieldForKey returns the struct field a key is decoded into, which like encoding/json is matched case-insensitively
+func getFieldForKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := getJSONSchemaFieldName(field); name != "" && strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
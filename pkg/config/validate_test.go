@@ -0,0 +1,132 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var confValidateTypos = `
+checks:
+  hostIPCSett: danger
+  cpuLimitsMissing:
+    severity: warning
+    kind: [Deployment]
+exemptiions:
+- rules: [hostIPCSet]
+exemptions:
+- rules: [hostIPCSet]
+  controllerName: [node-exporter]
+requiredLabels:
+  labels:
+  - key: team
+    pattern: ^[a-z]+$
+profiles:
+  strict:
+    checkz:
+      hostIPCSet: danger
+`
+
+var confValidateInvalidChecks = `
+checks:
+  hostIPCSett: danger
+  badSchema: warning
+  badTemplate: warning
+customChecks:
+  badSchema:
+    successMessage: ok
+    failureMessage: not ok
+    category: Security
+    target: Container
+    schema:
+      type: 5
+  badTemplate:
+    successMessage: ok
+    failureMessage: not ok
+    category: Security
+    target: Container
+    schemaString: |
+      type: object
+      {{ if .Polaris.Foo }
+`
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestValidateFilesUnknownKeys(t *testing.T) {
+	path := writeConfigFile(t, "typos.yaml", confValidateTypos)
+	problems := ValidateFiles([]string{path})
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		path + ": Unknown key checks.cpuLimitsMissing.kind",
+		path + ": Unknown key exemptiions",
+		path + ": Unknown key exemptions[0].controllerName",
+		path + ": Unknown key profiles.strict.checkz",
+		"Unknown check hostIPCSett",
+	}, messages)
+}
+
+func TestValidateFilesInvalidChecks(t *testing.T) {
+	path := writeConfigFile(t, "checks.yaml", confValidateInvalidChecks)
+	problems := ValidateFiles([]string{path})
+	assert.Equal(t, 3, len(problems))
+	assert.EqualError(t, problems[0], "Unknown check hostIPCSett")
+	assert.ErrorContains(t, problems[1], "Invalid schema for check badSchema")
+	assert.ErrorContains(t, problems[2], "Invalid schema template for check badTemplate")
+}
+
+func TestValidateFilesMerged(t *testing.T) {
+	override := writeConfigFile(t, "override.yaml", "checks:\n  hostIPCSet: warning\n")
+	assert.Empty(t, ValidateFiles([]string{"../../examples/config.yaml", override}))
+	assert.Empty(t, ValidateFiles([]string{"../../examples/config-full.yaml"}))
+
+	problems := ValidateFiles([]string{override, filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.Equal(t, 1, len(problems))
+	assert.ErrorContains(t, problems[0], "missing.yaml")
+}
+
+func TestValidateFilesEveryProblem(t *testing.T) {
+	path := writeConfigFile(t, "problems.yaml", `
+checks:
+  hostIPCSet: critical
+  hostIPCSett: danger
+  hostPIDSett: danger
+parallelism: -1
+owners:
+- namespaces: [payments]
+`)
+	problems := ValidateFiles([]string{path})
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		"Invalid parallelism -1, should be at least 1",
+		"Owners must specify a team",
+		"Invalid severity critical for check hostIPCSet, should be one of ignore, warning, or danger",
+		"Unknown check hostIPCSett",
+		"Unknown check hostPIDSett",
+	}, messages)
+}
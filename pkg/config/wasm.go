@@ -0,0 +1,69 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// WasmModule is a custom check implemented as a WebAssembly module, loaded with --wasm-checks
+type WasmModule interface {
+	// ID is the check ID reported in results
+	ID() string
+	// Run evaluates the check against a resource, given as JSON
+	Run(ctx context.Context, resource []byte) (WasmResult, error)
+}
+
+// WasmResult is the output of a WebAssembly check for a single resource
+type WasmResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// AddWasmModule adds a WebAssembly check, and enables it in checks with its severity from wasmChecks, or warning,
+// so it's selected and skipped like any other check
+func (conf *Configuration) AddWasmModule(module WasmModule) error {
+	checkID := module.ID()
+	if conf.IsKnownCheck(checkID) {
+		return fmt.Errorf("%s has the same ID as an existing check", checkID)
+	}
+	conf.WasmModules = append(conf.WasmModules, module)
+	if conf.Checks == nil {
+		conf.Checks = map[string]Severity{}
+	}
+	if _, ok := conf.Checks[checkID]; !ok {
+		conf.Checks[checkID] = conf.GetWasmCheckSeverity(checkID)
+	}
+	return nil
+}
+
+// IsWasmCheck returns true if the check is one of the loaded WebAssembly checks
+func (conf Configuration) IsWasmCheck(checkID string) bool {
+	for _, module := range conf.WasmModules {
+		if module.ID() == checkID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWasmCheckSeverity returns the configured severity of a WebAssembly check, or warning
+func (conf Configuration) GetWasmCheckSeverity(checkID string) Severity {
+	if severity, ok := conf.WasmChecks[checkID]; ok {
+		return severity
+	}
+	return SeverityWarning
+}
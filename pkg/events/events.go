@@ -0,0 +1,181 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events records audit findings as Kubernetes Events on the resources they were found on,
+// so they show up in `kubectl describe`.
+package events
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/fairwindsops/polaris/pkg/kube"
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+const (
+	// Component is the source component of the Events
+	Component = "polaris"
+	// DefaultMaxEvents is the default number of Events written per audit
+	DefaultMaxEvents = 100
+
+	eventsQPS   = 5
+	eventsBurst = 10
+)
+
+// Emitter writes a Warning Event for each failing check. Writes are rate limited, capped per audit,
+// and repeated findings update the count of the existing Event instead of creating a new one.
+type Emitter struct {
+	client    kubernetes.Interface
+	limiter   flowcontrol.RateLimiter
+	maxEvents int
+}
+
+// NewEmitter creates an Emitter which writes at most maxEvents Events per audit
+func NewEmitter(client kubernetes.Interface, maxEvents int) *Emitter {
+	return &Emitter{
+		client:    client,
+		limiter:   flowcontrol.NewTokenBucketRateLimiter(eventsQPS, eventsBurst),
+		maxEvents: maxEvents,
+	}
+}
+
+type finding struct {
+	resource  kube.GenericResource
+	container string
+	message   validator.ResultMessage
+}
+
+// Emit records the audit's failures on the resources they were found on, and returns how many Events were written.
+// Results for resources which aren't in the resource provider, e.g. because they were filtered, are skipped.
+func (e *Emitter) Emit(ctx context.Context, auditData validator.AuditData, resources *kube.ResourceProvider) (int, error) {
+	findings := getFindings(auditData, resources)
+	written := 0
+	for _, f := range findings {
+		if written >= e.maxEvents {
+			logrus.Warnf("Skipped %d Events after reaching the limit of %d per audit", len(findings)-written, e.maxEvents)
+			break
+		}
+		if err := e.limiter.Wait(ctx); err != nil {
+			return written, err
+		}
+		if err := e.record(ctx, f); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+func getFindings(auditData validator.AuditData, resources *kube.ResourceProvider) []finding {
+	byKey := map[string]kube.GenericResource{}
+	for _, kindResources := range resources.Resources {
+		for _, resource := range kindResources {
+			byKey[resourceKey(resource.ObjectMeta.GetNamespace(), resource.Kind, resource.ObjectMeta.GetName())] = resource
+		}
+	}
+	findings := []finding{}
+	for _, result := range auditData.Results {
+		resource, ok := byKey[resourceKey(result.Namespace, result.Kind, result.Name)]
+		if !ok {
+			logrus.Debugf("Not emitting Events for %s %s/%s, the resource wasn't found", result.Kind, result.Namespace, result.Name)
+			continue
+		}
+		add := func(container string, rs validator.ResultSet) {
+			for _, msg := range rs.GetSortedResults() {
+				if !msg.Success {
+					findings = append(findings, finding{resource: resource, container: container, message: msg})
+				}
+			}
+		}
+		add("", result.Results)
+		if result.PodResult != nil {
+			add("", result.PodResult.Results)
+			for _, containerResult := range result.PodResult.ContainerResults {
+				add(containerResult.Name, containerResult.Results)
+			}
+		}
+	}
+	return findings
+}
+
+func resourceKey(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
+
+// record creates the Event for a finding, or updates it if an earlier audit already created it
+func (e *Emitter) record(ctx context.Context, f finding) error {
+	namespace := f.resource.ObjectMeta.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	name := eventName(f)
+	message := f.message.Message
+	if f.container != "" {
+		message = fmt.Sprintf("Container %s: %s", f.container, message)
+	}
+	now := metav1.Now()
+	eventsClient := e.client.CoreV1().Events(namespace)
+	existing, err := eventsClient.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		existing.Count++
+		existing.LastTimestamp = now
+		existing.Message = message
+		_, err = eventsClient.Update(ctx, existing, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("updating Event %s/%s: %w", namespace, name, err)
+		}
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting Event %s/%s: %w", namespace, name, err)
+	}
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: f.resource.Resource.GetAPIVersion(),
+			Kind:       f.resource.Kind,
+			Namespace:  f.resource.ObjectMeta.GetNamespace(),
+			Name:       f.resource.ObjectMeta.GetName(),
+			UID:        f.resource.ObjectMeta.GetUID(),
+		},
+		Reason:         f.message.ID,
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Source:         corev1.EventSource{Component: Component},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	_, err = eventsClient.Create(ctx, event, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("creating Event %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// eventName is stable for a finding, so that later audits update the same Event
+func eventName(f finding) string {
+	hash := fnv.New32a()
+	fmt.Fprintf(hash, "%s/%s/%s", f.resource.ObjectMeta.GetUID(), f.container, f.message.ID)
+	return fmt.Sprintf("%s.polaris-%08x", f.resource.ObjectMeta.GetName(), hash.Sum32())
+}
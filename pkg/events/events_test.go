@@ -0,0 +1,130 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+var eventsTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+  uid: "1234"
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+`
+
+func getTestAudit() validator.AuditData {
+	return validator.AuditData{
+		Results: []validator.Result{{
+			Name:      "api",
+			Namespace: "web",
+			Kind:      "Deployment",
+			Results: validator.ResultSet{
+				"deploymentMissingReplicas": {ID: "deploymentMissingReplicas", Message: "Only one replica is scheduled", Severity: config.SeverityWarning},
+			},
+			PodResult: &validator.PodResult{
+				Results: validator.ResultSet{
+					"hostIPCSet": {ID: "hostIPCSet", Message: "Host IPC is not configured", Success: true},
+				},
+				ContainerResults: []validator.ContainerResult{{
+					Name: "api",
+					Results: validator.ResultSet{
+						"runAsRootAllowed": {ID: "runAsRootAllowed", Message: "Should not be allowed to run as root", Severity: config.SeverityDanger},
+					},
+				}},
+			},
+		}, {
+			Name:      "missing",
+			Namespace: "web",
+			Kind:      "Deployment",
+			Results: validator.ResultSet{
+				"deploymentMissingReplicas": {ID: "deploymentMissingReplicas", Message: "Only one replica is scheduled", Severity: config.SeverityWarning},
+			},
+		}},
+	}
+}
+
+func newTestEmitter(maxEvents int) (*Emitter, *fake.Clientset) {
+	client := fake.NewSimpleClientset()
+	emitter := NewEmitter(client, maxEvents)
+	emitter.limiter = flowcontrol.NewFakeAlwaysRateLimiter()
+	return emitter, client
+}
+
+func TestEmit(t *testing.T) {
+	ctx := context.Background()
+	provider := kube.CreateResourceProviderFromYaml(eventsTestYaml)
+	emitter, client := newTestEmitter(DefaultMaxEvents)
+
+	emitted, err := emitter.Emit(ctx, getTestAudit(), provider)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, emitted, "passing checks and unknown resources should be skipped")
+
+	list, err := client.CoreV1().Events("web").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+	messages := map[string]corev1.Event{}
+	for _, event := range list.Items {
+		messages[event.Reason] = event
+	}
+	replicas := messages["deploymentMissingReplicas"]
+	assert.Equal(t, corev1.EventTypeWarning, replicas.Type)
+	assert.Equal(t, "Only one replica is scheduled", replicas.Message)
+	assert.Equal(t, "Deployment", replicas.InvolvedObject.Kind)
+	assert.Equal(t, "apps/v1", replicas.InvolvedObject.APIVersion)
+	assert.Equal(t, "api", replicas.InvolvedObject.Name)
+	assert.EqualValues(t, "1234", replicas.InvolvedObject.UID)
+	assert.Equal(t, Component, replicas.Source.Component)
+	assert.Equal(t, "Container api: Should not be allowed to run as root", messages["runAsRootAllowed"].Message)
+
+	_, err = emitter.Emit(ctx, getTestAudit(), provider)
+	assert.NoError(t, err)
+	list, err = client.CoreV1().Events("web").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 2, "repeated findings should update the existing Events")
+	assert.EqualValues(t, 2, list.Items[0].Count)
+}
+
+func TestEmitMaxEvents(t *testing.T) {
+	ctx := context.Background()
+	provider := kube.CreateResourceProviderFromYaml(eventsTestYaml)
+	emitter, client := newTestEmitter(1)
+
+	emitted, err := emitter.Emit(ctx, getTestAudit(), provider)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, emitted)
+	list, err := client.CoreV1().Events("web").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+}
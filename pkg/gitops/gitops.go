@@ -0,0 +1,203 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitops discovers the apps defined in an Argo CD or Flux repository,
+// and how each app's manifests should be rendered.
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Renderer is the tool used to render an app's manifests
+type Renderer string
+
+const (
+	// RendererHelm renders a chart with helm template
+	RendererHelm Renderer = "helm"
+	// RendererKustomize renders a kustomization with kustomize build
+	RendererKustomize Renderer = "kustomize"
+	// RendererDirectory audits the manifests in a directory as they are
+	RendererDirectory Renderer = "directory"
+)
+
+// App is an Argo CD Application or Flux Kustomization whose source is in the repository
+type App struct {
+	Name string
+	// Kind is Application or Kustomization
+	Kind string
+	// Path is the directory of the app's source within the repository
+	Path     string
+	Renderer Renderer
+	// ValueFiles are the helm values files of the app, relative to Path
+	ValueFiles []string
+	// Namespace is the namespace the app is deployed to, if it sets one
+	Namespace string
+}
+
+type gitopsResource struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		// Argo CD Application
+		Source struct {
+			Path string `yaml:"path"`
+			Helm *struct {
+				ValueFiles []string `yaml:"valueFiles"`
+			} `yaml:"helm"`
+		} `yaml:"source"`
+		Destination struct {
+			Namespace string `yaml:"namespace"`
+		} `yaml:"destination"`
+		// Flux Kustomization
+		Path            string `yaml:"path"`
+		TargetNamespace string `yaml:"targetNamespace"`
+	} `yaml:"spec"`
+}
+
+// Discover finds the Argo CD Applications and Flux Kustomizations in a repository.
+// Apps whose source isn't a path in the repository, e.g. a chart from a helm repository, are skipped.
+func Discover(repoDir string) ([]App, error) {
+	info, err := os.Stat(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", repoDir)
+	}
+	apps := []App{}
+	visitFile := func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() && f.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !strings.HasSuffix(path, ".yml") && !strings.HasSuffix(path, ".yaml") {
+			return nil
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		found, err := parseApps(repoDir, contents)
+		if err != nil {
+			logrus.Warnf("Skipping %s: cannot parse YAML: %v", path, err)
+			return nil
+		}
+		apps = append(apps, found...)
+		return nil
+	}
+	if err := filepath.Walk(repoDir, visitFile); err != nil {
+		return nil, err
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
+func parseApps(repoDir string, contents []byte) ([]App, error) {
+	apps := []App{}
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	for {
+		resource := gitopsResource{}
+		err := decoder.Decode(&resource)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		app := App{Name: resource.Metadata.Name, Kind: resource.Kind}
+		var sourcePath string
+		if resource.Kind == "Application" && strings.HasPrefix(resource.APIVersion, "argoproj.io/") {
+			sourcePath = resource.Spec.Source.Path
+			app.Namespace = resource.Spec.Destination.Namespace
+			if resource.Spec.Source.Helm != nil {
+				app.ValueFiles = resource.Spec.Source.Helm.ValueFiles
+			}
+		} else if resource.Kind == "Kustomization" && strings.HasPrefix(resource.APIVersion, "kustomize.toolkit.fluxcd.io/") {
+			sourcePath = resource.Spec.Path
+			app.Namespace = resource.Spec.TargetNamespace
+		} else {
+			continue
+		}
+		if sourcePath == "" {
+			logrus.Warnf("Skipping %s %s: its source is not a path in the repository", app.Kind, app.Name)
+			continue
+		}
+		app.Path = filepath.Join(repoDir, sourcePath)
+		if !isInRepo(repoDir, app.Path) {
+			logrus.Warnf("Skipping %s %s: its path %s is outside the repository", app.Kind, app.Name, sourcePath)
+			continue
+		}
+		if valueFile := findValueFileOutsideRepo(repoDir, app); valueFile != "" {
+			logrus.Warnf("Skipping %s %s: its values file %s is outside the repository", app.Kind, app.Name, valueFile)
+			continue
+		}
+		app.Renderer = DetectRenderer(app.Path)
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// isInRepo checks that path doesn't escape the repository, e.g. with ../ in an app's source path
+func isInRepo(repoDir, path string) bool {
+	rel, err := filepath.Rel(repoDir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// findValueFileOutsideRepo returns the first of the app's values files outside the repository, if any
+func findValueFileOutsideRepo(repoDir string, app App) string {
+	for _, valueFile := range app.ValueFiles {
+		if !isInRepo(repoDir, filepath.Join(app.Path, valueFile)) {
+			return valueFile
+		}
+	}
+	return ""
+}
+
+// DetectRenderer picks the renderer from the files in a directory, as Argo CD does
+func DetectRenderer(dir string) Renderer {
+	if fileExists(filepath.Join(dir, "Chart.yaml")) {
+		return RendererHelm
+	}
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+		if fileExists(filepath.Join(dir, name)) {
+			return RendererKustomize
+		}
+	}
+	return RendererDirectory
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+}
+
+func TestDiscover(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "apps", "api", "Chart.yaml"), "name: api\n")
+	writeFile(t, filepath.Join(repo, "apps", "web", "kustomization.yaml"), "resources: []\n")
+	writeFile(t, filepath.Join(repo, "apps", "worker", "deployment.yaml"), "kind: Deployment\n")
+	writeFile(t, filepath.Join(repo, "argo", "apps.yaml"), `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: api
+spec:
+  source:
+    path: apps/api
+    helm:
+      valueFiles:
+      - values-prod.yaml
+  destination:
+    namespace: api
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: external
+spec:
+  source:
+    repoURL: https://charts.example.com
+    chart: external
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+`)
+	writeFile(t, filepath.Join(repo, "flux", "web.yaml"), `
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: web
+spec:
+  path: ./apps/web
+  targetNamespace: web
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: worker
+spec:
+  path: ./apps/worker
+`)
+
+	apps, err := Discover(repo)
+	assert.NoError(t, err)
+	assert.Len(t, apps, 3)
+
+	assert.Equal(t, App{
+		Name:       "api",
+		Kind:       "Application",
+		Path:       filepath.Join(repo, "apps", "api"),
+		Renderer:   RendererHelm,
+		ValueFiles: []string{"values-prod.yaml"},
+		Namespace:  "api",
+	}, apps[0])
+	assert.Equal(t, "web", apps[1].Name)
+	assert.Equal(t, "Kustomization", apps[1].Kind)
+	assert.Equal(t, RendererKustomize, apps[1].Renderer)
+	assert.Equal(t, "web", apps[1].Namespace)
+	assert.Equal(t, "worker", apps[2].Name)
+	assert.Equal(t, RendererDirectory, apps[2].Renderer)
+
+	_, err = Discover(filepath.Join(repo, "missing"))
+	assert.Error(t, err)
+}
+
+func TestDiscoverSkipsPathsOutsideRepo(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "apps", "api", "Chart.yaml"), "name: api\n")
+	writeFile(t, filepath.Join(repo, "argo", "apps.yaml"), `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: escape
+spec:
+  source:
+    path: ../../etc
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: values
+spec:
+  source:
+    path: apps/api
+    helm:
+      valueFiles:
+      - ../../../secrets.yaml
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: absolute
+spec:
+  path: /../../etc
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: api
+spec:
+  source:
+    path: apps/../apps/api
+    helm:
+      valueFiles:
+      - ../shared-values.yaml
+`)
+
+	apps, err := Discover(repo)
+	assert.NoError(t, err)
+	assert.Len(t, apps, 1)
+	assert.Equal(t, "api", apps[0].Name)
+	assert.Equal(t, filepath.Join(repo, "apps", "api"), apps[0].Path)
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RemotePrefix marks a path as a remote Git repository, e.g. git::https://github.com/org/repo//manifests?ref=main
+	RemotePrefix = "git::"
+	// TokenEnvVar is the environment variable with a token for cloning private repositories over HTTPS
+	TokenEnvVar = "POLARIS_GIT_TOKEN"
+)
+
+// Remote is a directory in a remote Git repository
+type Remote struct {
+	// URL is the repository to clone, over HTTPS or SSH
+	URL string
+	// Subpath is the directory within the repository, or empty for its root
+	Subpath string
+	// Ref is the branch, tag or commit to check out, or empty for the default branch
+	Ref string
+}
+
+// IsRemote returns true if the path refers to a remote Git repository
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, RemotePrefix)
+}
+
+// ParseRemote parses a remote in the form git::<url>[//<subpath>][?ref=<ref>]
+func ParseRemote(source string) (Remote, error) {
+	if !IsRemote(source) {
+		return Remote{}, fmt.Errorf("%s should start with %s", source, RemotePrefix)
+	}
+	raw := strings.TrimPrefix(source, RemotePrefix)
+	remote := Remote{}
+	if idx := strings.Index(raw, "?"); idx >= 0 {
+		query, err := url.ParseQuery(raw[idx+1:])
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid query in %s: %w", source, err)
+		}
+		for key := range query {
+			if key != "ref" {
+				return Remote{}, fmt.Errorf("unsupported parameter %s in %s, only ref is supported", key, source)
+			}
+		}
+		remote.Ref = query.Get("ref")
+		raw = raw[:idx]
+	}
+	// the subpath follows a double slash, which has to be looked for after the scheme's
+	start := 0
+	if idx := strings.Index(raw, "://"); idx >= 0 {
+		start = idx + len("://")
+	}
+	if idx := strings.Index(raw[start:], "//"); idx >= 0 {
+		remote.Subpath = raw[start+idx+len("//"):]
+		raw = raw[:start+idx]
+	}
+	if raw == "" {
+		return Remote{}, fmt.Errorf("no repository URL in %s", source)
+	}
+	return NewRemote(raw, remote.Subpath, remote.Ref)
+}
+
+// NewRemote returns the directory subpath of the repository at repoURL, checked out at ref
+func NewRemote(repoURL, subpath, ref string) (Remote, error) {
+	if repoURL == "" {
+		return Remote{}, errors.New("no repository URL")
+	}
+	remote := Remote{URL: repoURL, Ref: ref}
+	if subpath = strings.Trim(subpath, "/"); subpath != "" {
+		cleaned := path.Clean(subpath)
+		if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return Remote{}, fmt.Errorf("subpath %s is outside of the repository", subpath)
+		}
+		remote.Subpath = cleaned
+	}
+	return remote, nil
+}
+
+// String returns the remote in the form git::<url>[//<subpath>][?ref=<ref>]
+func (r Remote) String() string {
+	str := RemotePrefix + r.URL
+	if r.Subpath != "" {
+		str += "//" + r.Subpath
+	}
+	if r.Ref != "" {
+		str += "?ref=" + url.QueryEscape(r.Ref)
+	}
+	return str
+}
+
+// Clone checks out the remote's ref into a new temporary directory, which the caller should remove.
+// Private repositories are cloned with the SSH agent, or over HTTPS with the token in POLARIS_GIT_TOKEN.
+func (r Remote) Clone(timeout time.Duration) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	dir, err := os.MkdirTemp("", "polaris-git-*")
+	if err != nil {
+		return "", err
+	}
+	git := func(args ...string) error {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		cmd.Env = gitEnv(r.URL)
+		cmd.WaitDelay = time.Second
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("git %s timed out after %s", args[0], timeout)
+			}
+			return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	ref := r.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	err = git("init", "--quiet")
+	if err == nil {
+		err = git("remote", "add", "origin", r.URL)
+	}
+	if err == nil {
+		// a shallow fetch of the ref works for branches, tags, and full commit hashes on most servers
+		err = git("fetch", "--quiet", "--depth", "1", "origin", ref)
+		if err == nil {
+			err = git("checkout", "--quiet", "FETCH_HEAD")
+		} else if r.Ref != "" {
+			// otherwise, e.g. for abbreviated commit hashes, fetch everything and let git resolve the ref
+			err = git("fetch", "--quiet", "--tags", "origin")
+			if err == nil {
+				err = git("checkout", "--quiet", r.Ref)
+			}
+		}
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// Path returns the remote's subpath within a clone
+func (r Remote) Path(cloneDir string) string {
+	return filepath.Join(cloneDir, filepath.FromSlash(r.Subpath))
+}
+
+// proxyEnvVars are read by Go's HTTP client in upper or lower case, but git ignores an upper case HTTP_PROXY
+var proxyEnvVars = []string{"http_proxy", "https_proxy", "no_proxy"}
+
+// gitEnv never prompts for credentials, and authenticates HTTPS requests to the remote's host with the token from
+// POLARIS_GIT_TOKEN. The token is passed through the environment so it doesn't show up in the process list, and
+// is only sent to that host, not e.g. to submodules or redirects elsewhere. Proxy settings are passed in lower
+// case, so clones use the same proxy as the rest of Polaris.
+func gitEnv(remoteURL string) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	for _, name := range proxyEnvVars {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if value, ok := os.LookupEnv(strings.ToUpper(name)); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	token := os.Getenv(TokenEnvVar)
+	parsed, err := url.Parse(remoteURL)
+	if token == "" || err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return env
+	}
+	// add to any config already passed to git through the environment, rather than replacing it
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	// the last value of a variable in env is the one that's used
+	return append(env,
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1),
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=http.%s://%s/.extraHeader", count, parsed.Scheme, parsed.Host),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: Basic %s", count, credentials),
+	)
+}
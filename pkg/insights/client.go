@@ -3,6 +3,7 @@ package insights
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ErrUnauthorized is returned when Insights rejects the credentials, e.g. because the token expired
+var ErrUnauthorized = errors.New("unauthorized by Fairwinds Insights")
+
 type cluster struct {
 	Name         string `json:"Name"`
 	AuthToken    string `json:"AuthToken"`
@@ -28,16 +32,36 @@ type Client interface {
 	SendReport(cluster cluster, reportType, reportVersion string, payload []byte) (*reportJob, error)
 	GetReportJob(clusterName string, reportJobID int) (*reportJob, error)
 	IsTokenValid() (bool, error)
+	GetPolarisConfig() ([]byte, error)
 }
 
 type HTTPClient struct {
+	// httpClient sends the requests, or the default HTTP client if it's nil
+	httpClient   *http.Client
 	insightsHost string
 	organization string
 	token        string
 }
 
 func NewHTTPClient(host, organization, token string) Client {
-	return HTTPClient{host, organization, token}
+	return NewHTTPClientWithClient(nil, host, organization, token)
+}
+
+// NewHTTPClientWithClient returns a client which sends requests with httpClient, or the default HTTP client
+// if it's nil. Retries are left to the client's transport, e.g. a retry.Transport, which only retries requests
+// that are safe to send again, so reports aren't uploaded twice.
+func NewHTTPClientWithClient(httpClient *http.Client, host, organization, token string) Client {
+	return HTTPClient{httpClient: httpClient, insightsHost: host, organization: organization, token: token}
+}
+
+// do sends the request with the HTTP client
+func (ic HTTPClient) do(req *http.Request) (*http.Response, error) {
+	client := ic.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	logrus.Debugf("%s %s", req.Method, req.URL.Path)
+	return client.Do(req)
 }
 
 func (ic HTTPClient) UpsertCluster(clusterName string) (*cluster, error) {
@@ -48,12 +72,15 @@ func (ic HTTPClient) UpsertCluster(clusterName string) (*cluster, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+ic.token)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := ic.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making request fetching cluster: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("fetching cluster: %w", ErrUnauthorized)
+	}
 	if isSuccessful2XX(resp.StatusCode) {
 		// cluster already created
 		logrus.Infof("cluster %q found...", clusterName)
@@ -79,7 +106,7 @@ func (ic HTTPClient) UpsertCluster(clusterName string) (*cluster, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+ic.token)
-	resp, err = http.DefaultClient.Do(req)
+	resp, err = ic.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making request for creating cluster: %w", err)
 	}
@@ -90,6 +117,9 @@ func (ic HTTPClient) UpsertCluster(clusterName string) (*cluster, error) {
 		return nil, fmt.Errorf("reading response body: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("creating cluster: %w", ErrUnauthorized)
+	}
 	if !isSuccessful2XX(resp.StatusCode) {
 		return nil, fmt.Errorf("creating cluster, expected 200 OK received %s: %v", resp.Status, string(body))
 	}
@@ -116,7 +146,7 @@ func (ic HTTPClient) SendReport(cluster cluster, reportType, reportVersion strin
 	req.Header.Set("X-Fairwinds-Report-Priority", "4") // should have higher priority than the default 5
 	req.Header.Set("X-Fairwinds-Agent-Version", "")
 	req.Header.Set("X-Fairwinds-Agent-Chart-Version", "")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := ic.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making request for output: %w", err)
 	}
@@ -125,6 +155,9 @@ func (ic HTTPClient) SendReport(cluster cluster, reportType, reportVersion strin
 	if err != nil {
 		return nil, fmt.Errorf("reading response body: %w", err)
 	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("sending %s report: %w", reportType, ErrUnauthorized)
+	}
 	if !isSuccessful2XX(resp.StatusCode) {
 		return nil, fmt.Errorf("sending %s report, expected 200 OK received %s: %v", reportType, resp.Status, string(body))
 	}
@@ -151,12 +184,15 @@ func (ic HTTPClient) GetReportJob(clusterName string, reportJobID int) (*reportJ
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+ic.token)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := ic.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making request fetching report-job: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("fetching report-job: %w", ErrUnauthorized)
+	}
 	if !isSuccessful2XX(resp.StatusCode) {
 		return nil, fmt.Errorf("fetching report-job, expected 200 OK received %s", resp.Status)
 	}
@@ -181,7 +217,7 @@ func (ic HTTPClient) IsTokenValid() (bool, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+ic.token)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := ic.do(req)
 	if err != nil {
 		return false, fmt.Errorf("making request fetching organization: %w", err)
 	}
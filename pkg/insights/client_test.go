@@ -0,0 +1,97 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/retry"
+)
+
+// newRetryingClient returns an HTTP client which retries idempotent requests, like the one used by polaris audit
+func newRetryingClient(retries int, attemptTimeout time.Duration) *http.Client {
+	transport := retry.NewTransport(nil, time.Minute)
+	transport.Retries = retries
+	transport.Backoff = time.Millisecond
+	transport.AttemptTimeout = attemptTimeout
+	return &http.Client{Transport: transport}
+}
+
+func TestHTTPClientRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"id": 7, "status": "pending"}`))
+	}))
+	defer server.Close()
+
+	job, err := NewHTTPClientWithClient(newRetryingClient(2, 0), server.URL, "acme", "token").GetReportJob("test", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, job.ID)
+	assert.Equal(t, 3, requests)
+
+	requests = 0
+	_, err = NewHTTPClientWithClient(newRetryingClient(2, 0), server.URL, "acme", "token").SendReport(cluster{Name: "test"}, "polaris", "1.0", []byte(`{}`))
+	assert.EqualError(t, err, "sending polaris report, expected 200 OK received 502 Bad Gateway: ")
+	assert.Equal(t, 1, requests, "uploads shouldn't be retried, since they may have been handled")
+
+	requests = 0
+	_, err = NewHTTPClient(server.URL, "acme", "token").GetReportJob("test", 7)
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests, "requests aren't retried by default")
+}
+
+func TestHTTPClientDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPClientWithClient(newRetryingClient(3, 0), server.URL, "acme", "token").GetReportJob("test", 7)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, 1, requests)
+}
+
+func TestHTTPClientRetriesNetworkErrors(t *testing.T) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte(`{"id": 7, "status": "completed"}`))
+	}))
+	defer server.Close()
+
+	job, err := NewHTTPClientWithClient(newRetryingClient(1, 20*time.Millisecond), server.URL, "acme", "token").GetReportJob("test", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	atomic.StoreInt32(&requests, 0)
+	_, err = NewHTTPClientWithClient(newRetryingClient(0, 20*time.Millisecond), server.URL, "acme", "token").GetReportJob("test", 7)
+	assert.Error(t, err)
+}
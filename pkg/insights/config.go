@@ -0,0 +1,84 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConfigCacheTTL is how long a cached Polaris configuration is used before it's fetched again
+const ConfigCacheTTL = time.Hour
+
+// GetPolarisConfig fetches the organization's Polaris configuration, as YAML
+func (ic HTTPClient) GetPolarisConfig() ([]byte, error) {
+	configURL := fmt.Sprintf("%s/v0/organizations/%s/polaris/config", ic.insightsHost, ic.organization)
+	req, err := http.NewRequest("GET", configURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for fetching Polaris config: %w", err)
+	}
+	req.Header.Set("Accept", "application/yaml")
+	req.Header.Set("Authorization", "Bearer "+ic.token)
+	resp, err := ic.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request fetching Polaris config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("fetching Polaris config: %w", ErrUnauthorized)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	if !isSuccessful2XX(resp.StatusCode) {
+		return nil, fmt.Errorf("fetching Polaris config, expected 200 OK received %s: %v", resp.Status, string(body))
+	}
+	return body, nil
+}
+
+// LoadPolarisConfig returns the organization's Polaris configuration, from the cache file if it
+// was fetched within ConfigCacheTTL. Otherwise it's fetched and cached. If Insights can't be
+// reached, an outdated cached configuration is used rather than failing.
+func LoadPolarisConfig(client Client, cachePath string) ([]byte, error) {
+	info, statErr := os.Stat(cachePath)
+	if statErr == nil && time.Since(info.ModTime()) < ConfigCacheTTL {
+		logrus.Debugf("Using Polaris config from Fairwinds Insights cached in %s", cachePath)
+		return os.ReadFile(cachePath)
+	}
+	contents, err := client.GetPolarisConfig()
+	if err != nil {
+		if statErr != nil || errors.Is(err, ErrUnauthorized) {
+			return nil, err
+		}
+		logrus.Warnf("Using Polaris config cached at %s, since it couldn't be fetched from Fairwinds Insights: %v", info.ModTime().Format(time.RFC3339), err)
+		return os.ReadFile(cachePath)
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(cachePath, contents, 0600); err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
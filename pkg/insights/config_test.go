@@ -0,0 +1,89 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPolarisConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v0/organizations/acme/polaris/config", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("checks:\n  hostIPCSet: danger\n"))
+	}))
+	defer server.Close()
+
+	contents, err := NewHTTPClient(server.URL, "acme", "token").GetPolarisConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "checks:\n  hostIPCSet: danger\n", string(contents))
+
+	_, err = NewHTTPClient(server.URL, "acme", "expired").GetPolarisConfig()
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+type configClient struct {
+	HTTPClient
+	contents []byte
+	err      error
+	fetches  int
+}
+
+func (c *configClient) GetPolarisConfig() ([]byte, error) {
+	c.fetches++
+	return c.contents, c.err
+}
+
+func TestLoadPolarisConfig(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "polaris", "insights-config-acme.yaml")
+	client := &configClient{contents: []byte("checks:\n  hostIPCSet: danger\n")}
+
+	contents, err := LoadPolarisConfig(client, cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, client.contents, contents)
+	cached, err := os.ReadFile(cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, client.contents, cached)
+
+	_, err = LoadPolarisConfig(client, cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.fetches, "a recent cache should be used")
+
+	outdated := time.Now().Add(-2 * ConfigCacheTTL)
+	assert.NoError(t, os.Chtimes(cachePath, outdated, outdated))
+	client.err = errors.New("connection refused")
+	contents, err = LoadPolarisConfig(client, cachePath)
+	assert.NoError(t, err, "an outdated cache should be used when Insights can't be reached")
+	assert.Equal(t, cached, contents)
+	assert.Equal(t, 2, client.fetches)
+
+	client.err = ErrUnauthorized
+	_, err = LoadPolarisConfig(client, cachePath)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = LoadPolarisConfig(client, filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
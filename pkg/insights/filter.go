@@ -0,0 +1,107 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	workloads "github.com/fairwindsops/insights-plugins/plugins/workloads/pkg"
+	"github.com/thoas/go-funk"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+// NamespaceFilter limits the namespaces uploaded to Fairwinds Insights
+type NamespaceFilter struct {
+	// Include are the namespaces to upload. Every namespace is uploaded when empty.
+	Include []string
+	// Exclude are namespaces which aren't uploaded, even if they're included
+	Exclude []string
+}
+
+// IsEmpty returns true if the filter keeps every namespace
+func (f NamespaceFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Matches returns true if the namespace should be uploaded. Cluster-scoped resources, which have
+// no namespace, always are.
+func (f NamespaceFilter) Matches(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	if funk.ContainsString(f.Exclude, namespace) {
+		return false
+	}
+	return len(f.Include) == 0 || funk.ContainsString(f.Include, namespace)
+}
+
+// FilterReports removes the filtered namespaces from both reports, so that they describe the same
+// namespaces. The audit's cluster info and score are updated to match.
+func (f NamespaceFilter) FilterReports(wr WorkloadsReport, pr PolarisReport) (WorkloadsReport, PolarisReport) {
+	if f.IsEmpty() {
+		return wr, pr
+	}
+	wr.Payload = f.filterWorkloads(wr.Payload)
+
+	audit := pr.Payload
+	results := []validator.Result{}
+	for _, result := range audit.Results {
+		if f.Matches(result.Namespace) {
+			results = append(results, result)
+		}
+	}
+	audit.Results = results
+	audit.ClusterInfo.Namespaces = len(wr.Payload.Namespaces)
+	audit.ClusterInfo.Controllers = len(wr.Payload.Controllers)
+	audit.Score = audit.GetSummary().GetScore()
+	pr.Payload = audit
+	return wr, pr
+}
+
+// filterWorkloads removes the filtered namespaces, with their controllers and ingresses. Nodes are kept,
+// since they're shared by every namespace.
+func (f NamespaceFilter) filterWorkloads(report workloads.ClusterWorkloadReport) workloads.ClusterWorkloadReport {
+	namespaces := []corev1.Namespace{}
+	for _, namespace := range report.Namespaces {
+		if f.Matches(namespace.Name) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	removedUIDs := map[string]bool{}
+	controllers := []workloads.ControllerResult{}
+	for _, controller := range report.Controllers {
+		if f.Matches(controller.Namespace) {
+			controllers = append(controllers, controller)
+		} else {
+			removedUIDs[controller.UID] = true
+		}
+	}
+	// don't leave references to the controllers which were removed
+	for idx := range controllers {
+		if removedUIDs[controllers[idx].ParentUID] {
+			controllers[idx].ParentUID = ""
+		}
+	}
+	ingresses := []workloads.Ingress{}
+	for _, ingress := range report.Ingresses {
+		if f.Matches(ingress.Namespace) {
+			ingresses = append(ingresses, ingress)
+		}
+	}
+	report.Namespaces = namespaces
+	report.Controllers = controllers
+	report.Ingresses = ingresses
+	return report
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"testing"
+
+	workloads "github.com/fairwindsops/insights-plugins/plugins/workloads/pkg"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+func getFilterTestReports() (WorkloadsReport, PolarisReport) {
+	wr := WorkloadsReport{Payload: workloads.ClusterWorkloadReport{
+		Nodes: []workloads.NodeSummary{{Name: "node-1"}},
+		Namespaces: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "api"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		},
+		Controllers: []workloads.ControllerResult{
+			{Kind: "Deployment", Name: "api", Namespace: "api", UID: "uid-api"},
+			{Kind: "Deployment", Name: "billing", Namespace: "payments", UID: "uid-billing"},
+			{Kind: "Job", Name: "migrate", Namespace: "payments", UID: "uid-migrate", ParentUID: "uid-billing"},
+			{Kind: "DaemonSet", Name: "proxy", Namespace: "kube-system", UID: "uid-proxy"},
+			{Kind: "Job", Name: "cleanup", Namespace: "api", UID: "uid-cleanup", ParentUID: "uid-proxy"},
+		},
+		Ingresses: []workloads.Ingress{
+			{Kind: "Ingress", Name: "web", Namespace: "api"},
+			{Kind: "Ingress", Name: "checkout", Namespace: "payments"},
+		},
+	}}
+	pr := PolarisReport{Payload: validator.AuditData{
+		ClusterInfo: validator.ClusterInfo{Nodes: 1, Namespaces: 3, Controllers: 5},
+		Results: []validator.Result{
+			{Kind: "Deployment", Name: "api", Namespace: "api", Results: validator.ResultSet{
+				"hostIPCSet": {ID: "hostIPCSet", Success: true, Severity: config.SeverityDanger},
+			}},
+			{Kind: "Deployment", Name: "billing", Namespace: "payments", Results: validator.ResultSet{
+				"hostIPCSet": {ID: "hostIPCSet", Success: false, Severity: config.SeverityDanger},
+			}},
+			{Kind: "ClusterRole", Name: "viewer", Results: validator.ResultSet{
+				"clusterrolePodExecAttach": {ID: "clusterrolePodExecAttach", Success: true, Severity: config.SeverityDanger},
+			}},
+		},
+	}}
+	return wr, pr
+}
+
+func TestNamespaceFilterMatches(t *testing.T) {
+	filter := NamespaceFilter{Include: []string{"api", "payments"}, Exclude: []string{"payments"}}
+	assert.True(t, filter.Matches("api"))
+	assert.False(t, filter.Matches("payments"), "exclude should win")
+	assert.False(t, filter.Matches("kube-system"))
+	assert.True(t, filter.Matches(""), "cluster-scoped resources should be kept")
+	assert.True(t, NamespaceFilter{}.Matches("kube-system"))
+	assert.True(t, NamespaceFilter{}.IsEmpty())
+}
+
+func TestNamespaceFilterFilterReports(t *testing.T) {
+	wr, pr := getFilterTestReports()
+	filteredWR, filteredPR := NamespaceFilter{}.FilterReports(wr, pr)
+	assert.Equal(t, wr, filteredWR)
+	assert.Equal(t, pr, filteredPR)
+
+	filteredWR, filteredPR = NamespaceFilter{Exclude: []string{"payments"}}.FilterReports(wr, pr)
+	assert.Equal(t, []workloads.NodeSummary{{Name: "node-1"}}, filteredWR.Payload.Nodes)
+	assert.Len(t, filteredWR.Payload.Namespaces, 2)
+	assert.Equal(t, []workloads.ControllerResult{
+		{Kind: "Deployment", Name: "api", Namespace: "api", UID: "uid-api"},
+		{Kind: "DaemonSet", Name: "proxy", Namespace: "kube-system", UID: "uid-proxy"},
+		{Kind: "Job", Name: "cleanup", Namespace: "api", UID: "uid-cleanup", ParentUID: "uid-proxy"},
+	}, filteredWR.Payload.Controllers)
+	assert.Equal(t, []workloads.Ingress{{Kind: "Ingress", Name: "web", Namespace: "api"}}, filteredWR.Payload.Ingresses)
+	assert.Len(t, filteredPR.Payload.Results, 2)
+	assert.Equal(t, validator.ClusterInfo{Nodes: 1, Namespaces: 2, Controllers: 3}, filteredPR.Payload.ClusterInfo)
+	assert.Equal(t, uint(100), filteredPR.Payload.Score)
+
+	filteredWR, filteredPR = NamespaceFilter{Include: []string{"api"}}.FilterReports(wr, pr)
+	assert.Len(t, filteredWR.Payload.Namespaces, 1)
+	assert.Equal(t, []workloads.ControllerResult{
+		{Kind: "Deployment", Name: "api", Namespace: "api", UID: "uid-api"},
+		{Kind: "Job", Name: "cleanup", Namespace: "api", UID: "uid-cleanup"},
+	}, filteredWR.Payload.Controllers, "references to removed controllers should be cleared")
+	assert.Equal(t, []string{"api", ""}, []string{filteredPR.Payload.Results[0].Namespace, filteredPR.Payload.Results[1].Namespace})
+
+	assert.Len(t, wr.Payload.Controllers, 5, "the original reports shouldn't change")
+	assert.Equal(t, "uid-proxy", wr.Payload.Controllers[4].ParentUID)
+}
@@ -2,6 +2,7 @@ package insights
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,10 @@ import (
 
 type insightsReporter struct {
 	client Client
+	// reauthenticate returns a client with new credentials once Insights rejects the current ones, or is nil
+	reauthenticate func() (Client, error)
+	// cluster is fetched with the client's credentials, so it's fetched again after reauthenticating
+	cluster *cluster
 }
 
 func NewInsightsReporter(client Client) *insightsReporter {
@@ -20,6 +25,53 @@ func NewInsightsReporter(client Client) *insightsReporter {
 	}
 }
 
+// NewInsightsReporterWithReauthentication returns a reporter which calls reauthenticate the first time Insights
+// rejects the credentials, e.g. because the token expired, and then retries only the rejected request
+func NewInsightsReporterWithReauthentication(client Client, reauthenticate func() (Client, error)) *insightsReporter {
+	return &insightsReporter{
+		client:         client,
+		reauthenticate: reauthenticate,
+	}
+}
+
+// withReauthentication makes a request, and makes it again with new credentials if Insights rejected them
+func (ir *insightsReporter) withReauthentication(request func() error) error {
+	err := request()
+	if !errors.Is(err, ErrUnauthorized) || ir.reauthenticate == nil {
+		return err
+	}
+	client, err := ir.reauthenticate()
+	if err != nil {
+		return err
+	}
+	ir.client = client
+	ir.cluster = nil
+	// only reauthenticate once, in case the new credentials are rejected as well
+	ir.reauthenticate = nil
+	return request()
+}
+
+// getCluster fetches the cluster, or creates it if it doesn't exist yet
+func (ir *insightsReporter) getCluster(clusterName string) (*cluster, error) {
+	if ir.cluster == nil {
+		cluster, err := ir.client.UpsertCluster(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		ir.cluster = cluster
+	}
+	return ir.cluster, nil
+}
+
+// sendReport sends a report for the cluster
+func (ir *insightsReporter) sendReport(clusterName, reportType, reportVersion string, payload []byte) (*reportJob, error) {
+	cluster, err := ir.getCluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return ir.client.SendReport(*cluster, reportType, reportVersion, payload)
+}
+
 type WorkloadsReport struct {
 	Version string
 	Payload workloads.ClusterWorkloadReport
@@ -30,14 +82,40 @@ type PolarisReport struct {
 	Payload validator.AuditData
 }
 
+// DryRunReport contains the reports that would be uploaded to Fairwinds Insights
+type DryRunReport struct {
+	Workloads WorkloadsReport
+	Polaris   PolarisReport
+}
+
+// BuildDryRunReport verifies the client's credentials and returns the reports without uploading them
+func (ir insightsReporter) BuildDryRunReport(wr WorkloadsReport, pr PolarisReport) ([]byte, error) {
+	valid, err := ir.client.IsTokenValid()
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("insights token is not valid, please log in again")
+	}
+	payload, err := json.MarshalIndent(DryRunReport{Workloads: wr, Polaris: pr}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling data: %w", err)
+	}
+	return payload, nil
+}
+
 // ReportAuditToFairwindsInsights report audit to insights
 // 1 - check if cluster exists, otherwise create it
 // 2 - send workload report
 // 3 - send polaris report
 // 4 - checks if report job is completed for 3 minutes
 // 5 - display link to Fairwinds Insights
-func (ir insightsReporter) ReportAuditToFairwindsInsights(clusterName string, wr WorkloadsReport, pr PolarisReport) error {
-	cluster, err := ir.client.UpsertCluster(clusterName)
+// A request rejected as unauthorized is retried on its own after reauthenticating, if the reporter can.
+func (ir *insightsReporter) ReportAuditToFairwindsInsights(clusterName string, wr WorkloadsReport, pr PolarisReport) error {
+	err := ir.withReauthentication(func() error {
+		_, err := ir.getCluster(clusterName)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -45,7 +123,10 @@ func (ir insightsReporter) ReportAuditToFairwindsInsights(clusterName string, wr
 	if err != nil {
 		return fmt.Errorf("marshaling data: %w", err)
 	}
-	_, err = ir.client.SendReport(*cluster, "workloads", wr.Version, workloadsPayload)
+	err = ir.withReauthentication(func() error {
+		_, err := ir.sendReport(clusterName, "workloads", wr.Version, workloadsPayload)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -54,12 +135,17 @@ func (ir insightsReporter) ReportAuditToFairwindsInsights(clusterName string, wr
 	if err != nil {
 		return fmt.Errorf("marshaling data: %w", err)
 	}
-	reportJob, err := ir.client.SendReport(*cluster, "polaris", pr.Version, polarisPayload)
+	var reportJob *reportJob
+	err = ir.withReauthentication(func() error {
+		var err error
+		reportJob, err = ir.sendReport(clusterName, "polaris", pr.Version, polarisPayload)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	success, err := verifyReportJobCompletion(&ir, cluster.Name, reportJob.ID)
+	success, err := verifyReportJobCompletion(ir, ir.cluster.Name, reportJob.ID)
 	if err != nil {
 		return err
 	}
@@ -75,7 +161,12 @@ func verifyReportJobCompletion(ir *insightsReporter, clusterName string, reportJ
 	defer func() { fmt.Println() }()
 	logrus.Println("Processing (this usually takes 1-3 minutes)...")
 	for i := 0; i < 60; i++ {
-		reportJob, err := ir.client.GetReportJob(clusterName, reportJobID)
+		var reportJob *reportJob
+		err := ir.withReauthentication(func() error {
+			var err error
+			reportJob, err = ir.client.GetReportJob(clusterName, reportJobID)
+			return err
+		})
 		if err != nil {
 			return false, err
 		}
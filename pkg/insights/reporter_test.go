@@ -0,0 +1,120 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClient struct {
+	token          string
+	upserts        int
+	reports        []string
+	reportJobCalls int
+	// unauthorizedReports is the number of SendReport calls rejected as unauthorized
+	unauthorizedReports int
+	// unauthorizedReportJobs is the number of GetReportJob calls rejected as unauthorized
+	unauthorizedReportJobs int
+}
+
+func (c *fakeClient) UpsertCluster(clusterName string) (*cluster, error) {
+	c.upserts++
+	return &cluster{Name: clusterName, AuthToken: c.token}, nil
+}
+
+func (c *fakeClient) SendReport(cluster cluster, reportType, reportVersion string, payload []byte) (*reportJob, error) {
+	if c.unauthorizedReports > 0 {
+		c.unauthorizedReports--
+		return nil, ErrUnauthorized
+	}
+	c.reports = append(c.reports, reportType+":"+cluster.AuthToken)
+	return &reportJob{ID: len(c.reports)}, nil
+}
+
+func (c *fakeClient) GetReportJob(clusterName string, reportJobID int) (*reportJob, error) {
+	c.reportJobCalls++
+	if c.reportJobCalls <= c.unauthorizedReportJobs {
+		return nil, ErrUnauthorized
+	}
+	return &reportJob{ID: reportJobID, Status: "completed"}, nil
+}
+
+func (c *fakeClient) IsTokenValid() (bool, error) {
+	return true, nil
+}
+
+func (c *fakeClient) GetPolarisConfig() ([]byte, error) {
+	return nil, nil
+}
+
+func TestReportRetriesOnlyUnauthorizedRequest(t *testing.T) {
+	client := &fakeClient{token: "old", unauthorizedReportJobs: 1}
+	reauthentications := 0
+	reporter := NewInsightsReporterWithReauthentication(client, func() (Client, error) {
+		reauthentications++
+		client.token = "new"
+		return client, nil
+	})
+	err := reporter.ReportAuditToFairwindsInsights("test", WorkloadsReport{}, PolarisReport{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reauthentications)
+	assert.Equal(t, []string{"workloads:old", "polaris:old"}, client.reports, "reports shouldn't be uploaded again")
+	assert.Equal(t, 2, client.reportJobCalls)
+}
+
+func TestReportFetchesClusterAfterReauthentication(t *testing.T) {
+	client := &fakeClient{token: "old", unauthorizedReports: 1}
+	reporter := NewInsightsReporterWithReauthentication(client, func() (Client, error) {
+		client.token = "new"
+		return client, nil
+	})
+	err := reporter.ReportAuditToFairwindsInsights("test", WorkloadsReport{}, PolarisReport{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, client.upserts)
+	assert.Equal(t, []string{"workloads:new", "polaris:new"}, client.reports)
+}
+
+func TestReportReauthenticatesOnlyOnce(t *testing.T) {
+	client := &fakeClient{token: "old", unauthorizedReportJobs: 2}
+	reauthentications := 0
+	reporter := NewInsightsReporterWithReauthentication(client, func() (Client, error) {
+		reauthentications++
+		return client, nil
+	})
+	err := reporter.ReportAuditToFairwindsInsights("test", WorkloadsReport{}, PolarisReport{})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, 1, reauthentications)
+	assert.Equal(t, 2, client.reportJobCalls)
+}
+
+func TestReportReauthenticationError(t *testing.T) {
+	client := &fakeClient{unauthorizedReportJobs: 1}
+	reporter := NewInsightsReporterWithReauthentication(client, func() (Client, error) {
+		return nil, errors.New("token expired")
+	})
+	err := reporter.ReportAuditToFairwindsInsights("test", WorkloadsReport{}, PolarisReport{})
+	assert.EqualError(t, err, "token expired")
+	assert.Equal(t, 1, client.reportJobCalls)
+}
+
+func TestReportWithoutReauthentication(t *testing.T) {
+	client := &fakeClient{unauthorizedReportJobs: 1}
+	err := NewInsightsReporter(client).ReportAuditToFairwindsInsights("test", WorkloadsReport{}, PolarisReport{})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, 1, client.reportJobCalls)
+}
@@ -0,0 +1,287 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fairwindsops/controller-utils/pkg/controller"
+	workloads "github.com/fairwindsops/insights-plugins/plugins/workloads/pkg"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/pager"
+)
+
+// DefaultConcurrency is the default number of concurrent requests made to the Kubernetes API
+const DefaultConcurrency = 4
+
+// workloadsPageSize is the number of items requested per page when listing nodes, namespaces and ingresses
+const workloadsPageSize = 500
+
+// GetWorkloads fetches the cluster's workloads for the Insights workloads report. Lists are paged, and
+// the requests made for each node and namespace are spread across up to concurrency workers.
+// The report only depends on the state of the cluster, not on the concurrency.
+func GetWorkloads(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, kube kubernetes.Interface, clusterName string, concurrency int) (*workloads.ClusterWorkloadReport, error) {
+	client := controller.Client{
+		Context:    ctx,
+		Dynamic:    dynamicClient,
+		RESTMapper: restMapper,
+	}
+	topControllers, err := client.GetAllTopControllersSummary("")
+	if err != nil {
+		return nil, fmt.Errorf("fetching top controllers: %w", err)
+	}
+	controllers := make([]workloads.ControllerResult, 0, len(topControllers))
+	for _, workload := range topControllers {
+		controllers = append(controllers, formatWorkload(workload))
+	}
+	return getClusterWorkloads(ctx, kube, clusterName, controllers, concurrency)
+}
+
+// getClusterWorkloads assembles the report from the controllers, and the nodes, namespaces and ingresses in the cluster
+func getClusterWorkloads(ctx context.Context, kube kubernetes.Interface, clusterName string, controllers []workloads.ControllerResult, concurrency int) (*workloads.ClusterWorkloadReport, error) {
+	serverVersion, err := kube.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("fetching cluster API version: %w", err)
+	}
+	// top controllers are collected from a map, so sort them to keep the report stable
+	sort.Slice(controllers, func(i, j int) bool {
+		a, b := controllers[i], controllers[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	nodes := []corev1.Node{}
+	err = listPages(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return kube.CoreV1().Nodes().List(ctx, opts)
+	}, func(obj runtime.Object) {
+		nodes = append(nodes, *obj.(*corev1.Node))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching nodes: %w", err)
+	}
+	nodeSummaries := make([]workloads.NodeSummary, len(nodes))
+	err = forEachConcurrently(ctx, len(nodes), concurrency, func(ctx context.Context, idx int) error {
+		summary, err := getNodeSummary(ctx, kube, nodes[idx])
+		if err != nil {
+			return fmt.Errorf("fetching allocation of node %s: %w", nodes[idx].Name, err)
+		}
+		nodeSummaries[idx] = summary
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []corev1.Namespace{}
+	err = listPages(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return kube.CoreV1().Namespaces().List(ctx, opts)
+	}, func(obj runtime.Object) {
+		namespaces = append(namespaces, *obj.(*corev1.Namespace))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching namespaces: %w", err)
+	}
+	ingressesByNamespace := make([][]workloads.Ingress, len(namespaces))
+	err = forEachConcurrently(ctx, len(namespaces), concurrency, func(ctx context.Context, idx int) error {
+		namespace := namespaces[idx].Name
+		err := listPages(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return kube.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+		}, func(obj runtime.Object) {
+			ingressesByNamespace[idx] = append(ingressesByNamespace[idx], formatIngress(obj.(*networkingv1.Ingress)))
+		})
+		if err != nil {
+			return fmt.Errorf("fetching ingresses in namespace %s: %w", namespace, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ingresses := []workloads.Ingress{}
+	for _, namespaceIngresses := range ingressesByNamespace {
+		ingresses = append(ingresses, namespaceIngresses...)
+	}
+
+	return &workloads.ClusterWorkloadReport{
+		ServerVersion: serverVersion.Major + "." + serverVersion.Minor,
+		SourceType:    "Cluster",
+		SourceName:    clusterName,
+		CreationTime:  time.Now(),
+		Nodes:         nodeSummaries,
+		Namespaces:    namespaces,
+		Controllers:   controllers,
+		Ingresses:     ingresses,
+	}, nil
+}
+
+// listPages lists a resource one page at a time, calling fn for every item
+func listPages(ctx context.Context, list pager.ListPageFunc, fn func(obj runtime.Object)) error {
+	p := pager.New(list)
+	p.PageSize = workloadsPageSize
+	return p.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+		fn(obj)
+		return nil
+	})
+}
+
+// forEachConcurrently calls fn for every index below n from up to concurrency goroutines.
+// Once fn fails no more indexes are started, and the first error is returned.
+func forEachConcurrently(ctx context.Context, n int, concurrency int, fn func(ctx context.Context, idx int) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	indexes := make(chan int)
+	errs := make(chan error, 1)
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < concurrency && worker < n; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				if err := fn(workerCtx, idx); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+					cancel()
+				}
+			}
+		}()
+	}
+send:
+	for idx := 0; idx < n; idx++ {
+		select {
+		case indexes <- idx:
+		case <-workerCtx.Done():
+			break send
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// formatWorkload converts a top controller to its workloads report entry, as the workloads plugin does
+func formatWorkload(workload controller.Workload) workloads.ControllerResult {
+	topController := workload.TopController
+	var containers []workloads.ContainerResult
+	if workload.PodSpec != nil {
+		for _, container := range workload.PodSpec.Containers {
+			containers = append(containers, formatContainer(container, topController.GetCreationTimestamp()))
+		}
+	}
+	parentUID := ""
+	if owners := topController.GetOwnerReferences(); len(owners) > 0 {
+		parentUID = string(owners[0].UID)
+	}
+	return workloads.ControllerResult{
+		Kind:        topController.GetKind(),
+		Name:        topController.GetName(),
+		Namespace:   topController.GetNamespace(),
+		Annotations: topController.GetAnnotations(),
+		Labels:      topController.GetLabels(),
+		UID:         string(topController.GetUID()),
+		ParentUID:   parentUID,
+		PodCount:    float64(workload.RunningPodCount),
+		Containers:  containers,
+	}
+}
+
+func formatContainer(container corev1.Container, created metav1.Time) workloads.ContainerResult {
+	resources := workloads.ResourceResult{
+		Requests: workloads.ResourcesInfo{
+			CPU:    container.Resources.Requests.Cpu().String(),
+			Memory: container.Resources.Requests.Memory().String(),
+		},
+		Limits: workloads.ResourcesInfo{
+			CPU:    container.Resources.Limits.Cpu().String(),
+			Memory: container.Resources.Limits.Memory().String(),
+		},
+	}
+	// requests default to the limits when they're not set
+	if container.Resources.Requests.Cpu().IsZero() && !container.Resources.Limits.Cpu().IsZero() {
+		resources.Requests.CPU = resources.Limits.CPU
+	}
+	if container.Resources.Requests.Memory().IsZero() && !container.Resources.Limits.Memory().IsZero() {
+		resources.Requests.Memory = resources.Limits.Memory
+	}
+	return workloads.ContainerResult{
+		Name:         container.Name,
+		Image:        container.Image,
+		CreationTime: created.UTC(),
+		Resource:     resources,
+	}
+}
+
+func getNodeSummary(ctx context.Context, kube kubernetes.Interface, node corev1.Node) (workloads.NodeSummary, error) {
+	allocated, utilization, err := workloads.GetNodeAllocatedResource(ctx, kube, node)
+	if err != nil {
+		return workloads.NodeSummary{}, err
+	}
+	labels := node.GetLabels()
+	_, isControlPlane := labels["node-role.kubernetes.io/control-plane"]
+	_, isMaster := labels["node-role.kubernetes.io/master"]
+	return workloads.NodeSummary{
+		Name:               node.GetName(),
+		Labels:             labels,
+		Annotations:        node.GetAnnotations(),
+		CreationTimestamp:  node.GetCreationTimestamp().UTC(),
+		Capacity:           node.Status.Capacity,
+		Allocatable:        node.Status.Allocatable,
+		AllocatedLimits:    allocated.Limits,
+		AllocatedRequests:  allocated.Requests,
+		Utilization:        utilization,
+		KubeletVersion:     node.Status.NodeInfo.KubeletVersion,
+		KubeProxyVersion:   node.Status.NodeInfo.KubeProxyVersion,
+		IsControlPlaneNode: isControlPlane || isMaster,
+	}, nil
+}
+
+func formatIngress(item *networkingv1.Ingress) workloads.Ingress {
+	ingress := workloads.Ingress{
+		Kind:        workloads.KindIngress,
+		Name:        item.Name,
+		Namespace:   item.Namespace,
+		Annotations: item.Annotations,
+		Labels:      item.Labels,
+		UID:         string(item.UID),
+	}
+	if len(item.ManagedFields) > 0 {
+		ingress.APIVersion = item.ManagedFields[0].APIVersion
+	}
+	return ingress
+}
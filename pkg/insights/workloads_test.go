@@ -0,0 +1,194 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	workloads "github.com/fairwindsops/insights-plugins/plugins/workloads/pkg"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newWorkloadsTestClient(nodes, namespaces int) *fake.Clientset {
+	objects := []runtime.Object{}
+	for i := 0; i < nodes; i++ {
+		node := fmt.Sprintf("node-%03d", i)
+		objects = append(objects, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: node},
+			Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			}},
+		})
+	}
+	for i := 0; i < namespaces; i++ {
+		namespace := fmt.Sprintf("ns-%03d", i)
+		objects = append(objects,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+			&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace, UID: types.UID("uid-" + namespace)}},
+		)
+	}
+	return fake.NewSimpleClientset(objects...)
+}
+
+func getWorkloadsTestReport(t testing.TB, concurrency int) []byte {
+	controllers := []workloads.ControllerResult{
+		{Kind: "Deployment", Name: "web", Namespace: "ns-001"},
+		{Kind: "DaemonSet", Name: "agent", Namespace: "ns-000"},
+		{Kind: "Deployment", Name: "api", Namespace: "ns-001"},
+	}
+	report, err := getClusterWorkloads(context.Background(), newWorkloadsTestClient(20, 30), "test", controllers, concurrency)
+	assert.NoError(t, err)
+	report.CreationTime = time.Time{}
+	payload, err := json.Marshal(WorkloadsReport{Version: "test", Payload: *report})
+	assert.NoError(t, err)
+	return payload
+}
+
+func TestGetClusterWorkloads(t *testing.T) {
+	controllers := []workloads.ControllerResult{
+		{Kind: "Deployment", Name: "web", Namespace: "ns-001"},
+		{Kind: "DaemonSet", Name: "agent", Namespace: "ns-000"},
+	}
+	report, err := getClusterWorkloads(context.Background(), newWorkloadsTestClient(3, 2), "test", controllers, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", report.SourceName)
+	assert.Equal(t, "Cluster", report.SourceType)
+	assert.Equal(t, "agent", report.Controllers[0].Name)
+	assert.Len(t, report.Nodes, 3)
+	assert.Equal(t, "node-000", report.Nodes[0].Name)
+	assert.Len(t, report.Namespaces, 2)
+	assert.Len(t, report.Ingresses, 2)
+	assert.Equal(t, "ns-000", report.Ingresses[0].Namespace)
+	assert.Equal(t, workloads.KindIngress, report.Ingresses[0].Kind)
+}
+
+func TestGetClusterWorkloadsIsIndependentOfConcurrency(t *testing.T) {
+	expected := getWorkloadsTestReport(t, 1)
+	for _, concurrency := range []int{2, 8, 64} {
+		assert.Equal(t, string(expected), string(getWorkloadsTestReport(t, concurrency)), "concurrency %d", concurrency)
+	}
+}
+
+func TestGetClusterWorkloadsError(t *testing.T) {
+	client := newWorkloadsTestClient(2, 5)
+	client.PrependReactor("list", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	_, err := getClusterWorkloads(context.Background(), client, "test", nil, 4)
+	assert.ErrorContains(t, err, "fetching ingresses in namespace")
+	assert.ErrorContains(t, err, "forbidden")
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	var running, maxRunning int32
+	err := forEachConcurrently(context.Background(), 20, 3, func(ctx context.Context, idx int) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, maxRunning, int32(3))
+
+	var calls int32
+	err = forEachConcurrently(context.Background(), 100, 1, func(ctx context.Context, idx int) error {
+		atomic.AddInt32(&calls, 1)
+		if idx == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "failed")
+	assert.Less(t, calls, int32(100))
+}
+
+// newWorkloadsTestServer serves nodes, namespaces, pods and ingresses like the Kubernetes API, answering each request after latency
+func newWorkloadsTestServer(nodes, namespaces int, latency time.Duration) *httptest.Server {
+	writeList := func(w http.ResponseWriter, list runtime.Object) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(latency)
+		switch {
+		case r.URL.Path == "/version":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"major": "1", "minor": "27"}`))
+		case r.URL.Path == "/api/v1/nodes":
+			list := &corev1.NodeList{}
+			for i := 0; i < nodes; i++ {
+				list.Items = append(list.Items, corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%03d", i)}})
+			}
+			writeList(w, list)
+		case r.URL.Path == "/api/v1/namespaces":
+			list := &corev1.NamespaceList{}
+			for i := 0; i < namespaces; i++ {
+				list.Items = append(list.Items, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ns-%03d", i)}})
+			}
+			writeList(w, list)
+		case r.URL.Path == "/api/v1/pods":
+			writeList(w, &corev1.PodList{})
+		case strings.HasSuffix(r.URL.Path, "/ingresses"):
+			writeList(w, &networkingv1.IngressList{Items: []networkingv1.Ingress{{ObjectMeta: metav1.ObjectMeta{Name: "web"}}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func BenchmarkGetClusterWorkloads(b *testing.B) {
+	server := newWorkloadsTestServer(50, 50, time.Millisecond)
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL, QPS: -1})
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency-%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := getClusterWorkloads(context.Background(), client, "test", nil, concurrency)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
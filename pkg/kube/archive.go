@@ -0,0 +1,110 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveSuffixes are the extensions of tarballs which are audited like a directory
+var archiveSuffixes = []string{".tar", ".tar.gz", ".tgz"}
+
+// isArchive returns true if the path is a tarball of manifests, by its extension
+func isArchive(path string) bool {
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchive extracts a tar or gzipped tar archive to a new temporary directory, and returns
+// the directory. The caller should remove it.
+func extractArchive(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if !strings.HasSuffix(path, ".tar") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", path, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	dir, err := os.MkdirTemp("", "polaris-archive-*")
+	if err != nil {
+		return "", err
+	}
+	if err := extractTar(tar.NewReader(reader), dir); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("extracting %s: %w", path, err)
+	}
+	return dir, nil
+}
+
+// extractTar writes the directories and regular files of a tar archive to dir. Other entries, like
+// symlinks, are skipped, and entries which would be written outside of dir are an error.
+func extractTar(reader *tar.Reader, dir string) error {
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, header.Name)
+		if target != dir && !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+			return fmt.Errorf("entry %s is outside of the archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return err
+			}
+			if err := writeArchiveFile(target, reader); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeArchiveFile(target string, reader io.Reader) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
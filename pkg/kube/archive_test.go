@@ -0,0 +1,96 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type archiveTestEntry struct {
+	name     string
+	contents string
+}
+
+func writeTestArchive(t *testing.T, path string, entries []archiveTestEntry) {
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.contents)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(entry.contents))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	contents := buf.Bytes()
+	if filepath.Ext(path) != ".tar" {
+		var gzipped bytes.Buffer
+		gzipWriter := gzip.NewWriter(&gzipped)
+		_, err := gzipWriter.Write(contents)
+		assert.NoError(t, err)
+		assert.NoError(t, gzipWriter.Close())
+		contents = gzipped.Bytes()
+	}
+	assert.NoError(t, os.WriteFile(path, contents, 0644))
+}
+
+func TestGetResourcesFromArchive(t *testing.T) {
+	deployment, err := os.ReadFile("./test_files/test_1/deployment.yaml")
+	assert.NoError(t, err)
+	namespace, err := os.ReadFile("./test_files/test_1/namespace.yaml")
+	assert.NoError(t, err)
+	entries := []archiveTestEntry{
+		{name: "release/deployment.yaml", contents: string(deployment)},
+		{name: "release/nested/namespace.yml", contents: string(namespace)},
+		{name: "release/README.md", contents: "not a manifest"},
+	}
+	for _, name := range []string{"release.tar", "release.tar.gz", "release.tgz"} {
+		path := filepath.Join(t.TempDir(), name)
+		writeTestArchive(t, path, entries)
+
+		before, err := filepath.Glob(filepath.Join(os.TempDir(), "polaris-archive-*"))
+		assert.NoError(t, err)
+		provider, err := CreateResourceProviderFromPath(path)
+		assert.NoError(t, err, name)
+		assert.Equal(t, "Path", provider.SourceType)
+		assert.Equal(t, path, provider.SourceName)
+		assert.Equal(t, 2, provider.Resources.GetLength(), name)
+		assert.Equal(t, filepath.Join(path, "release/deployment.yaml"), provider.Resources["apps/Deployment"][0].SourceFile)
+		assert.Equal(t, 1, provider.Resources["apps/Deployment"][0].SourceLine)
+		after, err := filepath.Glob(filepath.Join(os.TempDir(), "polaris-archive-*"))
+		assert.NoError(t, err)
+		assert.Equal(t, before, after, "the extracted archive should be removed")
+	}
+}
+
+func TestGetResourcesFromArchiveWithPathTraversal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.tar.gz")
+	writeTestArchive(t, path, []archiveTestEntry{
+		{name: "deployment.yaml", contents: "kind: ConfigMap"},
+		{name: "../../escaped.yaml", contents: "kind: ConfigMap"},
+	})
+	_, err := CreateResourceProviderFromPath(path)
+	assert.ErrorContains(t, err, "entry ../../escaped.yaml is outside of the archive")
+
+	path = filepath.Join(t.TempDir(), "broken.tar.gz")
+	assert.NoError(t, os.WriteFile(path, []byte("not gzipped"), 0644))
+	_, err = CreateResourceProviderFromPath(path)
+	assert.ErrorContains(t, err, "reading "+path)
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PolarisIgnoreFile lists the files and directories to skip when auditing a directory, in gitignore syntax
+const PolarisIgnoreFile = ".polarisignore"
+
+// ignoreRule is a single line of a .polarisignore file, or an --exclude pattern
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreRules are matched in order, and the last rule matching a path decides whether it's ignored
+type ignoreRules []ignoreRule
+
+// readIgnoreRules reads the .polarisignore file at the root of a directory, if it has one, followed by the
+// exclude patterns
+func readIgnoreRules(root string, exclude []string) (ignoreRules, error) {
+	lines := []string{}
+	if info, err := os.Stat(root); err == nil && info.IsDir() {
+		contents, err := os.ReadFile(filepath.Join(root, PolarisIgnoreFile))
+		if err == nil {
+			lines = strings.Split(string(contents), "\n")
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return parseIgnoreRules(append(lines, exclude...))
+}
+
+// parseIgnoreRules parses patterns in gitignore syntax. Blank lines and comments are skipped.
+func parseIgnoreRules(lines []string) (ignoreRules, error) {
+	rules := ignoreRules{}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{}
+		pattern := line
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		}
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimRight(pattern, "/")
+		}
+		// patterns with a slash are relative to the root, others match a name in any directory
+		anchored := strings.Contains(pattern, "/")
+		pattern = strings.TrimPrefix(pattern, "/")
+		expr := globToRegexp(pattern)
+		if !anchored {
+			expr = "(.*/)?" + expr
+		}
+		var err error
+		rule.pattern, err = regexp.Compile("^" + expr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %s: %w", line, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// globToRegexp converts a gitignore glob to a regular expression, where * and ? don't match a slash,
+// and ** matches any number of directories
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+	for idx := 0; idx < len(glob); idx++ {
+		char := glob[idx]
+		switch {
+		case strings.HasPrefix(glob[idx:], "**/"):
+			expr.WriteString("(.*/)?")
+			idx += 2
+		case strings.HasPrefix(glob[idx:], "**"):
+			expr.WriteString(".*")
+			idx++
+		case char == '*':
+			expr.WriteString("[^/]*")
+		case char == '?':
+			expr.WriteString("[^/]")
+		case char == '[':
+			end := strings.IndexByte(glob[idx:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta(string(char)))
+				continue
+			}
+			class := glob[idx+1 : idx+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			idx += end
+		case char == '\\' && idx+1 < len(glob):
+			idx++
+			expr.WriteString(regexp.QuoteMeta(string(glob[idx])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	return expr.String()
+}
+
+// isIgnored returns true if the path, relative to the audit root and separated by slashes, is ignored
+func (rules ignoreRules) isIgnored(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.pattern.MatchString(relPath) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
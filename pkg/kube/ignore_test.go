@@ -0,0 +1,104 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules, err := parseIgnoreRules([]string{
+		"# vendored charts",
+		"vendor/",
+		"",
+		"*.example.yaml",
+		"!keep.example.yaml",
+		"/tmp",
+		"docs/**/samples",
+		"test-[0-9].yaml",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, rules, 6)
+
+	ignored := []struct {
+		path  string
+		isDir bool
+	}{
+		{"vendor", true},
+		{"apps/vendor", true},
+		{"app.example.yaml", false},
+		{"apps/web/app.example.yaml", false},
+		{"tmp", true},
+		{"tmp", false},
+		{"docs/samples", true},
+		{"docs/v1/api/samples", true},
+		{"test-1.yaml", false},
+	}
+	for _, path := range ignored {
+		assert.True(t, rules.isIgnored(path.path, path.isDir), path.path)
+	}
+	kept := []struct {
+		path  string
+		isDir bool
+	}{
+		{"vendor", false},
+		{"keep.example.yaml", false},
+		{"apps/keep.example.yaml", false},
+		{"apps/tmp", true},
+		{"apps/deployment.yaml", false},
+		{"docs/samples.yaml", false},
+		{"test-a.yaml", false},
+	}
+	for _, path := range kept {
+		assert.False(t, rules.isIgnored(path.path, path.isDir), path.path)
+	}
+
+	_, err = parseIgnoreRules([]string{"[z-a].yaml"})
+	assert.ErrorContains(t, err, "invalid ignore pattern [z-a].yaml")
+}
+
+func TestGetResourcesFromPathWithIgnores(t *testing.T) {
+	deployment, err := os.ReadFile("./test_files/test_1/deployment.yaml")
+	assert.NoError(t, err)
+	service, err := os.ReadFile("./test_files/test_1/service.yaml")
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	for _, path := range []string{"deploy/deployment.yaml", "deploy/service.yaml", "vendor/chart/deployment.yaml", "examples/deployment.yaml"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0755))
+		contents := deployment
+		if filepath.Base(path) == "service.yaml" {
+			contents = service
+		}
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, path), contents, 0644))
+	}
+
+	provider, err := CreateResourceProviderFromPath(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, provider.Resources.GetLength())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, PolarisIgnoreFile), []byte("# third party\nvendor/\n"), 0644))
+	provider, err = CreateResourceProviderFromPath(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, provider.Resources.GetLength())
+
+	provider, err = CreateResourceProviderFromPathExcluding(dir, []string{"examples/", "deploy/service.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, provider.Resources.GetLength())
+	assert.Equal(t, filepath.Join(dir, "deploy/deployment.yaml"), provider.Resources["apps/Deployment"][0].SourceFile)
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+)
+
+// CreateResourceProviderFromResourceName returns a new ResourceProvider with the single resource of the name.
+// Unlike CreateResourceProviderFromResource, the version is discovered through the REST mapper, and the kind
+// and namespace are optional. Without a kind, the workload kinds and the kinds the checks need are searched.
+// It's an error if several resources have the name, which lists them in the format of --resource.
+func CreateResourceProviderFromResourceName(ctx context.Context, name, kind string, c conf.Configuration) (*ResourceProvider, error) {
+	dynamicClient, restMapper, clientSet, _, err := GetKubeClient(ctx, c.Kubeconfig, c.KubeContext)
+	if err != nil {
+		return nil, err
+	}
+	serverVersion, err := clientSet.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching Cluster API version: %w", err)
+	}
+	kinds := []string{kind}
+	if kind == "" {
+		kinds = getResourceNameKinds(c)
+	}
+	found, err := findResourcesByName(ctx, dynamicClient, restMapper, kinds, c.Namespace, name, kind != "")
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("Could not find a resource named %s", name)
+	}
+	if len(found) > 1 {
+		candidates := []string{}
+		for _, obj := range found {
+			candidates = append(candidates, getResourceIdentifier(obj))
+		}
+		return nil, fmt.Errorf("Found %d resources named %s, set --resource-kind or --namespace, or audit one of them with --resource:\n  %s", len(found), name, strings.Join(candidates, "\n  "))
+	}
+	resources := newResourceProvider(serverVersion.Major+"."+serverVersion.Minor, "Resource", getResourceIdentifier(found[0]))
+	workloadObj, err := NewGenericResourceFromUnstructured(found[0], nil)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse resource %s: %w", name, err)
+	}
+	resources.Resources.addResource(workloadObj)
+	return &resources, nil
+}
+
+// getResourceNameKinds returns the kinds searched for a resource name when no kind is given
+func getResourceNameKinds(c conf.Configuration) []string {
+	kinds := []string{}
+	for _, kind := range workloadKinds {
+		if isKindEnabled(c, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	for _, kind := range getAdditionalKinds(c) {
+		kinds = append(kinds, string(kind))
+	}
+	return kinds
+}
+
+// findResourcesByName lists the resources of each kind with a field selector on the name, in the preferred
+// version of the kind. Unknown kinds are an error if required, and skipped otherwise, e.g. for kinds of
+// custom checks whose CRDs aren't installed.
+func findResourcesByName(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, kinds []string, namespace, name string, required bool) ([]unstructured.Unstructured, error) {
+	found := []unstructured.Unstructured{}
+	listOpts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+	for _, kind := range kinds {
+		mapping, err := getKindMapping(restMapper, kind)
+		if err != nil {
+			if required {
+				return nil, fmt.Errorf("Unknown kind %s: %w", kind, err)
+			}
+			logrus.Debugf("Skipping kind %s while searching for %s: %v", kind, name, err)
+			continue
+		}
+		resourceClient := dynamicClient.Resource(mapping.Resource)
+		var objects *unstructured.UnstructuredList
+		if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			objects, err = resourceClient.List(ctx, listOpts)
+		} else {
+			objects, err = resourceClient.Namespace(namespace).List(ctx, listOpts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error searching %s for %s: %w", kind, name, err)
+		}
+		for _, obj := range objects.Items {
+			// the field selector should have filtered them, but not every client honors it
+			if obj.GetName() == name {
+				found = append(found, obj)
+			}
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return getResourceIdentifier(found[i]) < getResourceIdentifier(found[j])
+	})
+	return found, nil
+}
+
+// parseResourceKind parses a kind given as Kind, group/Kind, or Kind.group as in --resource, e.g. Deployment.apps
+func parseResourceKind(kind string) schema.GroupKind {
+	if i := strings.Index(kind, "."); i > 0 && !strings.Contains(kind, "/") {
+		return schema.GroupKind{Kind: kind[:i], Group: kind[i+1:]}
+	}
+	return parseGroupKind(maybeTransformKindIntoGroupKind(kind))
+}
+
+// getKindMapping returns the mapping of the kind's preferred version. Kinds given without a group, like
+// Deployment, are looked up by their resource name, since the REST mapper only matches them in the core group.
+func getKindMapping(restMapper meta.RESTMapper, kind string) (*meta.RESTMapping, error) {
+	groupKind := parseResourceKind(kind)
+	mapping, err := restMapper.RESTMapping(groupKind)
+	if err == nil || groupKind.Group != "" {
+		return mapping, err
+	}
+	gvk, kindErr := restMapper.KindFor(schema.GroupVersionResource{Resource: strings.ToLower(groupKind.Kind)})
+	if kindErr != nil {
+		return nil, err
+	}
+	return restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// getResourceIdentifier returns the identifier of a resource for --resource, e.g. nginx-ingress/Deployment.apps/v1/default-backend
+func getResourceIdentifier(obj unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	kind := gvk.Kind
+	if gvk.Group != "" {
+		kind += "." + gvk.Group
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", obj.GetNamespace(), kind, gvk.Version, obj.GetName())
+}
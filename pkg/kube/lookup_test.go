@@ -0,0 +1,92 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+func newLookupTestObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestFindResourcesByName(t *testing.T) {
+	ctx := context.Background()
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	cronJobs := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+	clusterRoles := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{deployments.GroupVersion(), cronJobs.GroupVersion(), clusterRoles.GroupVersion()})
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	dynamicClient := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deployments:  "DeploymentList",
+		cronJobs:     "CronJobList",
+		clusterRoles: "ClusterRoleList",
+	},
+		newLookupTestObject("apps/v1", "Deployment", "web", "api"),
+		newLookupTestObject("apps/v1", "Deployment", "web", "frontend"),
+		newLookupTestObject("apps/v1", "Deployment", "staging", "frontend"),
+		newLookupTestObject("batch/v1", "CronJob", "web", "api"),
+		newLookupTestObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "api"),
+	)
+	kinds := []string{"Deployment", "CronJob", "rbac.authorization.k8s.io/ClusterRole", "VerticalPodAutoscaler"}
+
+	found, err := findResourcesByName(ctx, dynamicClient, restMapper, kinds, "", "api", false)
+	assert.NoError(t, err)
+	identifiers := []string{}
+	for _, obj := range found {
+		identifiers = append(identifiers, getResourceIdentifier(obj))
+	}
+	assert.Equal(t, []string{"/ClusterRole.rbac.authorization.k8s.io/v1/api", "web/CronJob.batch/v1/api", "web/Deployment.apps/v1/api"}, identifiers)
+
+	found, err = findResourcesByName(ctx, dynamicClient, restMapper, []string{"CronJob.batch"}, "", "api", true)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "CronJob", found[0].GetKind())
+
+	found, err = findResourcesByName(ctx, dynamicClient, restMapper, kinds, "staging", "frontend", false)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "staging", found[0].GetNamespace())
+
+	found, err = findResourcesByName(ctx, dynamicClient, restMapper, kinds, "", "missing", false)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	_, err = findResourcesByName(ctx, dynamicClient, restMapper, []string{"VerticalPodAutoscaler"}, "", "api", true)
+	assert.ErrorContains(t, err, "Unknown kind VerticalPodAutoscaler")
+}
+
+func TestParseResourceKind(t *testing.T) {
+	assert.Equal(t, schema.GroupKind{Kind: "Deployment"}, parseResourceKind("Deployment"))
+	assert.Equal(t, schema.GroupKind{Group: "apps", Kind: "Deployment"}, parseResourceKind("Deployment.apps"))
+	assert.Equal(t, schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}, parseResourceKind("ClusterRole.rbac.authorization.k8s.io"))
+	assert.Equal(t, schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}, parseResourceKind("rbac.authorization.k8s.io/ClusterRole"))
+	assert.Equal(t, schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}, parseResourceKind("Ingress"))
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// OCIPrefix marks an --audit-path which is an OCI artifact of manifests, e.g. oci://registry.example.com/team/app-manifests:1.0
+const OCIPrefix = "oci://"
+
+// ociTitleAnnotation names the file a layer was pushed from, e.g. by oras push
+const ociTitleAnnotation = "org.opencontainers.image.title"
+
+// IsOCIArtifact returns true if the path is a reference to an OCI artifact
+func IsOCIArtifact(path string) bool {
+	return strings.HasPrefix(path, OCIPrefix)
+}
+
+// ParseOCIReference parses an oci:// path into a registry reference
+func ParseOCIReference(path string) (name.Reference, error) {
+	return name.ParseReference(strings.TrimPrefix(path, OCIPrefix))
+}
+
+// pullOCIArtifact writes the layers of an OCI artifact to a new temporary directory, and returns the directory.
+// The caller should remove it. Layers are written to the file named by their title annotation, and tarballs
+// are extracted. Registries are logged in to with the credentials from `helm registry login`, then the
+// Docker config.
+func pullOCIArtifact(ctx context.Context, path string) (string, error) {
+	ref, err := ParseOCIReference(path)
+	if err != nil {
+		return "", err
+	}
+	keychain := authn.NewMultiKeychain(helmRegistryKeychain{path: getHelmRegistryConfigPath()}, authn.DefaultKeychain)
+	image, err := remote.Image(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return "", fmt.Errorf("pulling %s: %w", ref, err)
+	}
+	manifest, err := image.Manifest()
+	if err != nil {
+		return "", fmt.Errorf("pulling %s: %w", ref, err)
+	}
+	dir, err := os.MkdirTemp("", "polaris-oci-*")
+	if err != nil {
+		return "", err
+	}
+	for _, descriptor := range manifest.Layers {
+		if err := writeOCILayer(image, descriptor, dir); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("pulling layer %s of %s: %w", descriptor.Digest, ref, err)
+		}
+	}
+	return dir, nil
+}
+
+// writeOCILayer writes a layer to dir, extracting it if it's a tarball
+func writeOCILayer(image v1.Image, descriptor v1.Descriptor, dir string) error {
+	layer, err := image.LayerByDigest(descriptor.Digest)
+	if err != nil {
+		return err
+	}
+	blob, err := layer.Compressed()
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	// directories are pushed as tarballs, e.g. with a media type of application/vnd.oci.image.layer.v1.tar+gzip
+	mediaType := string(descriptor.MediaType)
+	title := descriptor.Annotations[ociTitleAnnotation]
+	if strings.HasSuffix(mediaType, ".tar+gzip") || strings.HasSuffix(title, ".tar.gz") || strings.HasSuffix(title, ".tgz") {
+		gzipReader, err := gzip.NewReader(blob)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		return extractTar(tar.NewReader(gzipReader), dir)
+	}
+	if strings.HasSuffix(mediaType, ".tar") || strings.HasSuffix(title, ".tar") {
+		return extractTar(tar.NewReader(blob), dir)
+	}
+	if title == "" {
+		title = descriptor.Digest.Hex + ".yaml"
+	}
+	target := filepath.Join(dir, title)
+	if !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+		return fmt.Errorf("layer title %s is outside of the artifact", title)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	return writeArchiveFile(target, blob)
+}
+
+// getHelmRegistryConfigPath returns the file `helm registry login` saves credentials to
+func getHelmRegistryConfigPath() string {
+	if path := os.Getenv("HELM_REGISTRY_CONFIG"); path != "" {
+		return path
+	}
+	configHome := os.Getenv("HELM_CONFIG_HOME")
+	if configHome == "" {
+		if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
+			configHome = filepath.Join(xdgConfigHome, "helm")
+		} else if home, err := os.UserHomeDir(); err == nil && runtime.GOOS == "darwin" {
+			configHome = filepath.Join(home, "Library", "Preferences", "helm")
+		} else if userConfigDir, err := os.UserConfigDir(); err == nil {
+			configHome = filepath.Join(userConfigDir, "helm")
+		}
+	}
+	return filepath.Join(configHome, "registry", "config.json")
+}
+
+// helmRegistryKeychain resolves credentials from helm's registry config, which has the same format as the Docker config
+type helmRegistryKeychain struct {
+	path string
+}
+
+// Resolve implements authn.Keychain
+func (k helmRegistryKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	file, err := os.Open(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return authn.Anonymous, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	configFile, err := config.LoadFromReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", k.path, err)
+	}
+	authConfig, err := configFile.GetAuthConfig(target.RegistryStr())
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", k.path, err)
+	}
+	if authConfig == (types.AuthConfig{}) {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      authConfig.Username,
+		Password:      authConfig.Password,
+		Auth:          authConfig.Auth,
+		IdentityToken: authConfig.IdentityToken,
+		RegistryToken: authConfig.RegistryToken,
+	}), nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// pushTestArtifact pushes an artifact with a YAML layer for the deployment, and a gzipped tarball layer for the
+// namespace, to the registry and returns its oci:// reference
+func pushTestArtifact(t *testing.T, registryHost string, options ...remote.Option) string {
+	deployment, err := os.ReadFile("./test_files/test_1/deployment.yaml")
+	assert.NoError(t, err)
+	namespace, err := os.ReadFile("./test_files/test_1/namespace.yaml")
+	assert.NoError(t, err)
+	tarball := filepath.Join(t.TempDir(), "nested.tar.gz")
+	writeTestArchive(t, tarball, []archiveTestEntry{{name: "nested/namespace.yaml", contents: string(namespace)}})
+	tarballContents, err := os.ReadFile(tarball)
+	assert.NoError(t, err)
+
+	artifact, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1),
+		mutate.Addendum{
+			Layer:       static.NewLayer(deployment, "application/vnd.polaris.manifest.v1+yaml"),
+			Annotations: map[string]string{ociTitleAnnotation: "deployment.yaml"},
+		},
+		mutate.Addendum{
+			Layer:       static.NewLayer(tarballContents, types.OCILayer),
+			Annotations: map[string]string{ociTitleAnnotation: "nested"},
+		},
+	)
+	assert.NoError(t, err)
+	ref, err := name.ParseReference(registryHost + "/team/manifests:1.0")
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Write(ref, artifact, options...))
+	return OCIPrefix + ref.String()
+}
+
+func newTestRegistry() http.Handler {
+	return registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+}
+
+func TestGetResourcesFromOCIArtifact(t *testing.T) {
+	server := httptest.NewServer(newTestRegistry())
+	defer server.Close()
+	path := pushTestArtifact(t, strings.TrimPrefix(server.URL, "http://"))
+
+	before, err := filepath.Glob(filepath.Join(os.TempDir(), "polaris-oci-*"))
+	assert.NoError(t, err)
+	provider, err := CreateResourceProviderFromOCIArtifact(context.Background(), path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Path", provider.SourceType)
+	assert.Equal(t, path, provider.SourceName)
+	assert.Equal(t, 2, provider.Resources.GetLength())
+	assert.Equal(t, path+"/deployment.yaml", provider.Resources["apps/Deployment"][0].SourceFile)
+	assert.Equal(t, path+"/nested/namespace.yaml", provider.Resources["Namespace"][0].SourceFile)
+	after, err := filepath.Glob(filepath.Join(os.TempDir(), "polaris-oci-*"))
+	assert.NoError(t, err)
+	assert.Equal(t, before, after, "the pulled artifact should be removed")
+
+	_, err = CreateResourceProviderFromOCIArtifact(context.Background(), path+"-missing", nil)
+	assert.ErrorContains(t, err, "pulling "+strings.TrimPrefix(path, OCIPrefix)+"-missing")
+}
+
+func TestGetResourcesFromOCIArtifactWithHelmRegistryLogin(t *testing.T) {
+	registryHandler := newTestRegistry()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "polaris" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		registryHandler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+
+	configDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", configDir)
+	t.Setenv("HELM_REGISTRY_CONFIG", filepath.Join(configDir, "missing.json"))
+	path := pushTestArtifact(t, registryHost, remote.WithAuth(&authn.Basic{Username: "polaris", Password: "secret"}))
+	_, err := CreateResourceProviderFromOCIArtifact(context.Background(), path, nil)
+	assert.ErrorContains(t, err, "401 Unauthorized")
+
+	helmConfig := filepath.Join(configDir, "helm-registry.json")
+	auth := base64.StdEncoding.EncodeToString([]byte("polaris:secret"))
+	config := `{"auths": {"` + registryHost + `": {"auth": "` + auth + `"}}}`
+	assert.NoError(t, os.WriteFile(helmConfig, []byte(config), 0600))
+	t.Setenv("HELM_REGISTRY_CONFIG", helmConfig)
+	provider, err := CreateResourceProviderFromOCIArtifact(context.Background(), path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.Resources.GetLength())
+}
+
+func TestGetHelmRegistryConfigPath(t *testing.T) {
+	t.Setenv("HELM_REGISTRY_CONFIG", "/etc/helm/registry.json")
+	assert.Equal(t, "/etc/helm/registry.json", getHelmRegistryConfigPath())
+	t.Setenv("HELM_REGISTRY_CONFIG", "")
+	t.Setenv("HELM_CONFIG_HOME", "/etc/helm")
+	assert.Equal(t, filepath.Join("/etc/helm", "registry", "config.json"), getHelmRegistryConfigPath())
+}
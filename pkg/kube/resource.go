@@ -42,6 +42,9 @@ type GenericResource struct {
 	PodTemplate        interface{}
 	OriginalObjectJSON []byte
 	OriginalObjectYAML []byte
+	// SourceFile and SourceLine locate the resource's YAML when it was loaded from a file
+	SourceFile string
+	SourceLine int
 }
 
 // NewGenericResourceFromUnstructured creates a workload from an unstructured.Unstructured
@@ -254,7 +257,11 @@ func cacheAllObjectsOfKind(ctx context.Context, apiVersion, kind string, dynamic
 
 func getObject(ctx context.Context, namespace, kind, version, name string, dynamicClient dynamic.Interface, restMapper meta.RESTMapper) (*unstructured.Unstructured, error) {
 	fqKind := schema.FromAPIVersionAndKind(version, kind)
-	mapping, err := restMapper.RESTMapping(fqKind.GroupKind(), fqKind.Version)
+	groupKind := fqKind.GroupKind()
+	if groupKind.Group == "" {
+		groupKind = parseResourceKind(kind)
+	}
+	mapping, err := restMapper.RESTMapping(groupKind, fqKind.Version)
 	if err != nil {
 		return nil, err
 	}
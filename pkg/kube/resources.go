@@ -18,12 +18,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	conf "github.com/fairwindsops/polaris/pkg/config"
@@ -41,6 +44,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Required for other auth providers like GKE.
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
@@ -53,18 +57,31 @@ type ResourceProvider struct {
 	Nodes         []corev1.Node
 	Namespaces    []corev1.Namespace
 	Resources     resourceKindMap
+	// definitions indexes resources by namespace, kind and name, see GetDefinitions
+	definitions *definitionIndex
 }
 
+// definitionIndex is built the first time it's needed, after every resource has been added to the provider
+type definitionIndex struct {
+	once      sync.Once
+	resources map[string][]GenericResource
+}
+
+// VPAGroupKind is the key under which VerticalPodAutoscalers are stored in a ResourceProvider
+const VPAGroupKind = "autoscaling.k8s.io/VerticalPodAutoscaler"
+
 type resourceKindMap map[string][]GenericResource
 
-func (rkm resourceKindMap) addResource(r GenericResource) {
+func getGroupKind(r GenericResource) string {
 	gvk := r.Resource.GroupVersionKind()
-	var key string
 	if gvk.Group != "" {
-		key = gvk.Group + "/" + gvk.Kind
-	} else {
-		key = gvk.Kind
+		return gvk.Group + "/" + gvk.Kind
 	}
+	return gvk.Kind
+}
+
+func (rkm resourceKindMap) addResource(r GenericResource) {
+	key := getGroupKind(r)
 	rkm[key] = append(rkm[key], r)
 }
 
@@ -124,9 +141,34 @@ func newResourceProvider(version, sourceType, sourceName string) ResourceProvide
 		Nodes:         make([]corev1.Node, 0),
 		Namespaces:    make([]corev1.Namespace, 0),
 		Resources:     make(map[string][]GenericResource),
+		definitions:   &definitionIndex{},
 	}
 }
 
+// GetDefinitions returns every resource in the provider with the same namespace, kind and name as the given one,
+// including itself. The index is built once, so resources must not be added after the first call.
+func (resources *ResourceProvider) GetDefinitions(resource GenericResource) []GenericResource {
+	index := resources.definitions
+	if index == nil {
+		// not created by newResourceProvider, so the index isn't kept
+		index = &definitionIndex{}
+	}
+	index.once.Do(func() {
+		index.resources = map[string][]GenericResource{}
+		for _, kindResources := range resources.Resources {
+			for _, r := range kindResources {
+				key := getDefinitionKey(r)
+				index.resources[key] = append(index.resources[key], r)
+			}
+		}
+	})
+	return index.resources[getDefinitionKey(resource)]
+}
+
+func getDefinitionKey(resource GenericResource) string {
+	return resource.ObjectMeta.GetNamespace() + "/" + getGroupKind(resource) + "/" + resource.ObjectMeta.GetName()
+}
+
 type k8sResource struct {
 	Kind string `yaml:"kind"`
 }
@@ -136,17 +178,124 @@ var podSpecFields = []string{"jobTemplate", "spec", "template"}
 // CreateResourceProvider returns a new ResourceProvider object to interact with k8s resources
 func CreateResourceProvider(ctx context.Context, directory, workload string, c conf.Configuration) (*ResourceProvider, error) {
 	if workload != "" {
-		return CreateResourceProviderFromResource(ctx, workload)
+		return CreateResourceProviderFromResource(ctx, workload, c)
+	}
+	if IsOCIArtifact(directory) {
+		resources, err := CreateResourceProviderFromOCIArtifact(ctx, directory, c.ExcludePaths)
+		if err == nil && c.DefaultNamespace != "" {
+			resources.setDefaultNamespace(c.DefaultNamespace)
+		}
+		return resources, err
 	}
 	if directory != "" {
-		return CreateResourceProviderFromPath(directory)
+		resources, err := CreateResourceProviderFromPathExcluding(directory, c.ExcludePaths)
+		if err == nil && c.DefaultNamespace != "" {
+			resources.setDefaultNamespace(c.DefaultNamespace)
+		}
+		return resources, err
 	}
 	return CreateResourceProviderFromCluster(ctx, c)
 }
 
+// CreateResourceProviderFromPaths returns a ResourceProvider with the resources from several paths, which can be
+// anything CreateResourceProvider audits, e.g. directories or OCI artifacts. A resource found under more than one
+// path, by its namespace, kind, and name, is only added once. Stdin, given as -, can only be read once.
+func CreateResourceProviderFromPaths(ctx context.Context, paths []string, c conf.Configuration) (*ResourceProvider, error) {
+	stdinPaths := 0
+	for _, path := range paths {
+		if path == "-" {
+			stdinPaths++
+		}
+	}
+	if stdinPaths > 1 {
+		return nil, errors.New("- can only be given once, since stdin can only be read once")
+	}
+	providers := []*ResourceProvider{}
+	for _, path := range paths {
+		resources, err := CreateResourceProvider(ctx, path, "", c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		providers = append(providers, resources)
+	}
+	return mergeResourceProviders(providers, strings.Join(paths, ", ")), nil
+}
+
+// mergeResourceProviders combines the resources of several providers. Resources which were already added from an
+// earlier provider are skipped, while duplicates within one provider are kept for the duplicate resource check.
+func mergeResourceProviders(providers []*ResourceProvider, sourceName string) *ResourceProvider {
+	merged := newResourceProvider("unknown", "Path", sourceName)
+	namespaces := map[string]bool{}
+	resourceKeys := map[string]bool{}
+	for _, provider := range providers {
+		providerKeys := map[string]bool{}
+		if merged.ServerVersion == "unknown" {
+			merged.ServerVersion = provider.ServerVersion
+		}
+		merged.Nodes = append(merged.Nodes, provider.Nodes...)
+		for _, namespace := range provider.Namespaces {
+			if !namespaces[namespace.Name] {
+				namespaces[namespace.Name] = true
+				merged.Namespaces = append(merged.Namespaces, namespace)
+			}
+		}
+		kinds := []string{}
+		for kind := range provider.Resources {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			for _, resource := range provider.Resources[kind] {
+				key := resource.ObjectMeta.GetNamespace() + "/" + kind + "/" + resource.ObjectMeta.GetName()
+				if resourceKeys[key] {
+					logrus.Debugf("Skipping %s from %s, it was already found under another path", key, provider.SourceName)
+					continue
+				}
+				providerKeys[key] = true
+				merged.Resources.addResource(resource)
+			}
+		}
+		for key := range providerKeys {
+			resourceKeys[key] = true
+		}
+	}
+	return &merged
+}
+
+// clusterScopedKinds are never given a default namespace
+var clusterScopedKinds = []string{
+	"APIService",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CSIDriver",
+	"CustomResourceDefinition",
+	"IngressClass",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PodSecurityPolicy",
+	"PriorityClass",
+	"RuntimeClass",
+	"StorageClass",
+	"ValidatingWebhookConfiguration",
+	"VolumeAttachment",
+}
+
+// setDefaultNamespace sets the namespace of namespaced resources which don't specify one, like `kubectl apply -n`
+func (resources *ResourceProvider) setDefaultNamespace(namespace string) {
+	for _, kindResources := range resources.Resources {
+		for _, resource := range kindResources {
+			if resource.ObjectMeta.GetNamespace() == "" && !funk.ContainsString(clusterScopedKinds, resource.Kind) {
+				resource.ObjectMeta.SetNamespace(namespace)
+			}
+		}
+	}
+}
+
 // CreateResourceProviderFromResource creates a new ResourceProvider that just contains one workload
-func CreateResourceProviderFromResource(ctx context.Context, workload string) (*ResourceProvider, error) {
-	dynamicClient, restMapper, clientSet, _, err := GetKubeClient(ctx, "")
+func CreateResourceProviderFromResource(ctx context.Context, workload string, c conf.Configuration) (*ResourceProvider, error) {
+	dynamicClient, restMapper, clientSet, _, err := GetKubeClient(ctx, c.Kubeconfig, c.KubeContext)
 	if err != nil {
 		return nil, err
 	}
@@ -177,21 +326,79 @@ func CreateResourceProviderFromResource(ctx context.Context, workload string) (*
 	return &resources, nil
 }
 
-// CreateResourceProviderFromPath returns a new ResourceProvider using the YAML files in a directory
+// CreateResourceProviderFromPath returns a new ResourceProvider using the YAML files in a directory,
+// or the multi-document YAML piped to stdin when directory is -
 func CreateResourceProviderFromPath(directory string) (*ResourceProvider, error) {
+	return CreateResourceProviderFromPathExcluding(directory, nil)
+}
+
+// CreateResourceProviderFromPathExcluding returns a new ResourceProvider using the YAML files in a directory,
+// skipping the files and directories matching its .polarisignore file or the exclude patterns, which use the
+// same gitignore syntax
+func CreateResourceProviderFromPathExcluding(directory string, exclude []string) (*ResourceProvider, error) {
 	resources := newResourceProvider("unknown", "Path", directory)
 
 	if directory == "-" {
-		fi, err := os.Stdin.Stat()
-		if err == nil && fi.Mode()&os.ModeNamedPipe == os.ModeNamedPipe {
-			if err := resources.addResourcesFromReader(os.Stdin); err != nil {
-				return nil, err
-			}
-			return &resources, nil
+		if err := resources.addResourcesFromStdin(os.Stdin); err != nil {
+			return nil, err
+		}
+		return &resources, nil
+	}
+
+	root := directory
+	if isArchive(directory) {
+		extracted, err := extractArchive(directory)
+		if err != nil {
+			return nil, err
 		}
+		defer os.RemoveAll(extracted)
+		root = extracted
+	}
+	if err := resources.addResourcesFromDirectory(root, directory, exclude); err != nil {
+		return nil, err
+	}
+	return &resources, nil
+}
+
+// CreateResourceProviderFromOCIArtifact returns a new ResourceProvider using the YAML files in an OCI artifact,
+// e.g. oci://registry.example.com/team/app-manifests:1.0, skipping the files matching the exclude patterns
+func CreateResourceProviderFromOCIArtifact(ctx context.Context, path string, exclude []string) (*ResourceProvider, error) {
+	resources := newResourceProvider("unknown", "Path", path)
+	pulled, err := pullOCIArtifact(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(pulled)
+	if err := resources.addResourcesFromDirectory(pulled, path, exclude); err != nil {
+		return nil, err
+	}
+	return &resources, nil
+}
+
+// addResourcesFromDirectory adds the YAML files under root which don't match its .polarisignore file or the
+// exclude patterns. Files are located by their path within source, which root was extracted from if they differ.
+func (resources *ResourceProvider) addResourcesFromDirectory(root, source string, exclude []string) error {
+	rules, err := readIgnoreRules(root, exclude)
+	if err != nil {
+		return err
 	}
+	skippedFiles, skippedDirs := 0, 0
 
 	visitFile := func(path string, f os.FileInfo, err error) error {
+		if f != nil && path != root {
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if rules.isIgnored(filepath.ToSlash(relPath), f.IsDir()) {
+				if f.IsDir() {
+					skippedDirs++
+					return filepath.SkipDir
+				}
+				skippedFiles++
+				return nil
+			}
+		}
 		if !strings.HasSuffix(path, ".yml") && !strings.HasSuffix(path, ".yaml") {
 			return nil
 		}
@@ -200,18 +407,34 @@ func CreateResourceProviderFromPath(directory string) (*ResourceProvider, error)
 			logrus.Errorf("Error reading file: %v", path)
 			return err
 		}
-		err = resources.addResourcesFromYaml(string(contents))
+		// files extracted from an archive are located by their path within it, e.g. release.tar.gz/deploy.yaml
+		sourceFile := path
+		if root != source {
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if IsOCIArtifact(source) {
+				sourceFile = source + "/" + filepath.ToSlash(relPath)
+			} else {
+				sourceFile = filepath.Join(source, relPath)
+			}
+		}
+		err = resources.addResourcesFromFile(sourceFile, string(contents))
 		if err != nil {
-			logrus.Warnf("Skipping %s: cannot add resource from YAML: %v", path, err)
+			logrus.Warnf("Skipping %s: cannot add resource from YAML: %v", sourceFile, err)
 		}
 		return nil
 	}
 
-	err := filepath.Walk(directory, visitFile)
+	err = filepath.Walk(root, visitFile)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &resources, nil
+	if len(rules) > 0 {
+		logrus.Debugf("Skipped %d files and %d directories matching %s or --exclude", skippedFiles, skippedDirs, PolarisIgnoreFile)
+	}
+	return nil
 }
 
 // CreateResourceProviderFromYaml returns a new ResourceProvider using the yaml
@@ -221,23 +444,27 @@ func CreateResourceProviderFromYaml(yamlContent string) *ResourceProvider {
 	return &resources
 }
 
+// CreateResourceProviderFromFileContents returns a new ResourceProvider using the yaml read from a file,
+// recording the file and line each resource starts on
+func CreateResourceProviderFromFileContents(path string, yamlContent string) *ResourceProvider {
+	resources := newResourceProvider("unknown", "Content", "unknown")
+	resources.addResourcesFromFile(path, yamlContent)
+	return &resources
+}
+
 // CreateResourceProviderFromCluster creates a new ResourceProvider using live data from a cluster
 func CreateResourceProviderFromCluster(ctx context.Context, c conf.Configuration) (*ResourceProvider, error) {
-	dynamicClient, _, clientSet, clusterHost, err := GetKubeClient(ctx, c.KubeContext)
+	dynamicClient, _, clientSet, clusterHost, err := GetKubeClient(ctx, c.Kubeconfig, c.KubeContext)
 	if err != nil {
 		return nil, err
 	}
 	return CreateResourceProviderFromAPI(ctx, clientSet, clusterHost, dynamicClient, c)
 }
 
-func GetKubeClient(ctx context.Context, kubeContext string) (dynamic.Interface, meta.RESTMapper, kubernetes.Interface, string, error) {
-	var kubeConf *rest.Config
-	var err error
-	if len(kubeContext) > 0 {
-		kubeConf, err = config.GetConfigWithContext(kubeContext)
-	} else {
-		kubeConf, err = config.GetConfig()
-	}
+// GetKubeClient returns clients for the cluster of a kubeconfig context. The default kubeconfig, e.g. from
+// KUBECONFIG or the in-cluster config, is used when kubeconfig is empty, and its current context when kubeContext is.
+func GetKubeClient(ctx context.Context, kubeconfig, kubeContext string) (dynamic.Interface, meta.RESTMapper, kubernetes.Interface, string, error) {
+	kubeConf, err := getKubeConfig(kubeconfig, kubeContext)
 	if err != nil {
 		return nil, nil, nil, "", fmt.Errorf("Error fetching KubeConfig: %v", err)
 	}
@@ -256,6 +483,57 @@ func GetKubeClient(ctx context.Context, kubeContext string) (dynamic.Interface,
 	return dynamicClient, restmapper.NewDiscoveryRESTMapper(resources), clientSet, kubeConf.Host, nil
 }
 
+func getKubeConfig(kubeconfig, kubeContext string) (*rest.Config, error) {
+	if kubeconfig == "" {
+		if len(kubeContext) > 0 {
+			return config.GetConfigWithContext(kubeContext)
+		}
+		return config.GetConfig()
+	}
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	kubeConf, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	// the same defaults as config.GetConfig, which are higher than client-go's
+	if kubeConf.QPS == 0.0 {
+		kubeConf.QPS = 20.0
+	}
+	if kubeConf.Burst == 0 {
+		kubeConf.Burst = 30
+	}
+	return kubeConf, nil
+}
+
+// workloadKinds are discovered through the pods running in the cluster, rather than listed directly
+var workloadKinds = []string{"Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job", "CronJob"}
+
+// isKindEnabled returns true if the kind should be fetched, given the configured resource kinds.
+// All kinds are enabled when no resource kinds are configured.
+func isKindEnabled(c conf.Configuration, kind string) bool {
+	if len(c.ResourceKinds) == 0 {
+		return true
+	}
+	groupKind := parseGroupKind(maybeTransformKindIntoGroupKind(kind))
+	for _, enabled := range c.ResourceKinds {
+		enabledGroupKind := parseGroupKind(maybeTransformKindIntoGroupKind(enabled))
+		if enabledGroupKind.Kind == groupKind.Kind && (enabledGroupKind.Group == "" || enabledGroupKind.Group == groupKind.Group) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAnyKindEnabled(c conf.Configuration, kinds []string) bool {
+	for _, kind := range kinds {
+		if isKindEnabled(c, kind) {
+			return true
+		}
+	}
+	return false
+}
+
 // CreateResourceProviderFromAPI creates a new ResourceProvider from an existing k8s interface
 func CreateResourceProviderFromAPI(ctx context.Context, kube kubernetes.Interface, clusterName string, dynamic dynamic.Interface, c conf.Configuration) (*ResourceProvider, error) {
 	listOpts := metav1.ListOptions{}
@@ -297,11 +575,14 @@ func CreateResourceProviderFromAPI(ctx context.Context, kube kubernetes.Interfac
 		}
 		namespaces = nsList
 	}
-	logrus.Info("Loading pods")
-	pods, err := kube.CoreV1().Pods(c.Namespace).List(ctx, listOpts)
-	if err != nil {
-		logrus.Errorf("Error fetching Pods: %v", err)
-		return nil, err
+	pods := &corev1.PodList{}
+	if isAnyKindEnabled(c, workloadKinds) {
+		logrus.Info("Loading pods")
+		pods, err = kube.CoreV1().Pods(c.Namespace).List(ctx, listOpts)
+		if err != nil {
+			logrus.Errorf("Error fetching Pods: %v", err)
+			return nil, err
+		}
 	}
 
 	logrus.Info("Setting up restmapper")
@@ -311,29 +592,15 @@ func CreateResourceProviderFromAPI(ctx context.Context, kube kubernetes.Interfac
 		return nil, err
 	}
 	restMapper := restmapper.NewDiscoveryRESTMapper(resources)
-	allChecks := []conf.SchemaCheck{}
-	for _, check := range c.CustomChecks {
-		allChecks = append(allChecks, check)
-	}
-	for _, check := range conf.BuiltInChecks {
-		allChecks = append(allChecks, check)
-	}
-
-	var additionalKinds []conf.TargetKind
-	for _, check := range allChecks {
-		neededKinds := []conf.TargetKind{check.Target}
-		for key := range check.AdditionalSchemas {
-			neededKinds = append(neededKinds, conf.TargetKind(key))
+	for _, kind := range c.ResourceKinds {
+		if funk.ContainsString(workloadKinds, kind) {
+			continue
 		}
-		for key := range check.AdditionalSchemaStrings {
-			neededKinds = append(neededKinds, conf.TargetKind(key))
-		}
-		for _, kind := range neededKinds {
-			if !funk.Contains(conf.HandledTargets, kind) && !funk.Contains(additionalKinds, kind) {
-				additionalKinds = append(additionalKinds, kind)
-			}
+		if _, err := restMapper.RESTMapping(parseGroupKind(maybeTransformKindIntoGroupKind(kind))); err != nil {
+			logrus.Warnf("Unknown kind %s in resource kinds, it will not be audited: %v", kind, err)
 		}
 	}
+	additionalKinds := getAdditionalKinds(c)
 
 	var kubernetesResources []GenericResource
 	for _, kind := range additionalKinds {
@@ -359,6 +626,27 @@ func CreateResourceProviderFromAPI(ctx context.Context, kube kubernetes.Interfac
 		}
 	}
 
+	if c.IsCheckEnabled("requestsDeviateFromVPA") && !funk.Contains(additionalKinds, conf.TargetKind(VPAGroupKind)) {
+		mapping, err := restMapper.RESTMapping(parseGroupKind(VPAGroupKind))
+		if err != nil {
+			logrus.Warnf("VPA recommendations are enabled, but VerticalPodAutoscalers are not available: %v", err)
+		} else {
+			logrus.Info("Loading " + VPAGroupKind)
+			objects, err := dynamic.Resource(mapping.Resource).Namespace(c.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				logrus.Warnf("Error retrieving VerticalPodAutoscalers: %v", err)
+				return nil, err
+			}
+			for _, obj := range objects.Items {
+				res, err := NewGenericResourceFromUnstructured(obj, nil)
+				if err != nil {
+					return nil, err
+				}
+				kubernetesResources = append(kubernetesResources, res)
+			}
+		}
+	}
+
 	objectCache := map[string]unstructured.Unstructured{}
 
 	logrus.Info("Loading controllers")
@@ -367,16 +655,52 @@ func CreateResourceProviderFromAPI(ctx context.Context, kube kubernetes.Interfac
 		logrus.Errorf("Error loading controllers from pods: %v", err)
 		return nil, err
 	}
+	for _, controller := range controllers {
+		if isKindEnabled(c, controller.Kind) {
+			kubernetesResources = append(kubernetesResources, controller)
+		}
+	}
 	// resources loaded from custom checks can also contain controllers and thus would be added twice to the provider
-	kubernetesResources = deduplicateControllers(append(kubernetesResources, controllers...))
+	kubernetesResources = deduplicateControllers(kubernetesResources)
 
 	provider.Nodes = nodes.Items
 	provider.Namespaces = namespaces.Items
 	provider.Resources.addResources(kubernetesResources)
-	logrus.Info("Done loading Kubernetes resources")
+	logrus.WithField("resources", len(kubernetesResources)).Info("Done loading Kubernetes resources")
 	return &provider, nil
 }
 
+// getAdditionalKinds returns the kinds besides workloads which are needed by the checks, e.g. Ingress
+func getAdditionalKinds(c conf.Configuration) []conf.TargetKind {
+	allChecks := []conf.SchemaCheck{}
+	for _, check := range c.CustomChecks {
+		allChecks = append(allChecks, check)
+	}
+	for _, check := range conf.BuiltInChecks {
+		allChecks = append(allChecks, check)
+	}
+
+	var additionalKinds []conf.TargetKind
+	for _, check := range allChecks {
+		neededKinds := []conf.TargetKind{check.Target}
+		for key := range check.AdditionalSchemas {
+			neededKinds = append(neededKinds, conf.TargetKind(key))
+		}
+		for key := range check.AdditionalSchemaStrings {
+			neededKinds = append(neededKinds, conf.TargetKind(key))
+		}
+		for _, kind := range neededKinds {
+			if !isKindEnabled(c, string(kind)) {
+				continue
+			}
+			if !funk.Contains(conf.HandledTargets, kind) && !funk.Contains(additionalKinds, kind) {
+				additionalKinds = append(additionalKinds, kind)
+			}
+		}
+	}
+	return additionalKinds
+}
+
 // LoadControllers loads a list of controllers from the kubeResources Pods
 func LoadControllers(ctx context.Context, pods []corev1.Pod, dynamicClient dynamic.Interface, restMapperPointer meta.RESTMapper, objectCache map[string]unstructured.Unstructured) ([]GenericResource, error) {
 	interfaces := []GenericResource{}
@@ -432,13 +756,47 @@ func (resources *ResourceProvider) addResourcesFromReader(reader io.Reader) erro
 	return nil
 }
 
+// addResourcesFromStdin adds the YAML documents piped to stdin, e.g. by `kustomize build . | polaris audit --audit-path -`
+func (resources *ResourceProvider) addResourcesFromStdin(stdin *os.File) error {
+	fi, err := stdin.Stat()
+	if err != nil {
+		return fmt.Errorf("Error reading stdin: %w", err)
+	}
+	if fi.Mode()&os.ModeCharDevice != 0 {
+		return errors.New("No YAML was piped to stdin, which --audit-path - reads from")
+	}
+	contents, err := io.ReadAll(stdin)
+	if err != nil {
+		return fmt.Errorf("Error reading stdin: %w", err)
+	}
+	if strings.TrimSpace(string(contents)) == "" {
+		return errors.New("Stdin is empty, expected YAML manifests for --audit-path -")
+	}
+	return resources.addResourcesFromYaml(string(contents))
+}
+
 func (resources *ResourceProvider) addResourcesFromYaml(contents string) error {
-	specs := regexp.MustCompile("[\r\n]-+[\r\n]").Split(string(contents), -1)
-	for _, spec := range specs {
+	return resources.addResourcesFromFile("", contents)
+}
+
+var yamlDocumentSeparator = regexp.MustCompile("[\r\n]-+[\r\n]")
+
+// addResourcesFromFile adds each YAML document in contents, recording the file and line it starts on
+func (resources *ResourceProvider) addResourcesFromFile(path string, contents string) error {
+	separators := append(yamlDocumentSeparator.FindAllStringIndex(contents, -1), []int{len(contents), len(contents)})
+	start := 0
+	for _, separator := range separators {
+		spec := contents[start:separator[0]]
+		specStart := start + len(spec) - len(strings.TrimLeft(spec, " \t\r\n"))
+		start = separator[1]
 		if strings.TrimSpace(spec) == "" {
 			continue
 		}
-		err := resources.addResourceFromString(spec)
+		line := 0
+		if path != "" {
+			line = strings.Count(contents[:specStart], "\n") + 1
+		}
+		err := resources.addResourceFromString(spec, path, line)
 		if err != nil {
 			logrus.Errorf("Error parsing YAML: (%v)", err)
 			return err
@@ -447,7 +805,7 @@ func (resources *ResourceProvider) addResourcesFromYaml(contents string) error {
 	return nil
 }
 
-func (resources *ResourceProvider) addResourceFromString(contents string) error {
+func (resources *ResourceProvider) addResourceFromString(contents string, sourceFile string, sourceLine int) error {
 	contentBytes := []byte(contents)
 	decoder := k8sYaml.NewYAMLOrJSONDecoder(bytes.NewReader(contentBytes), 1000)
 	resource := k8sResource{}
@@ -474,12 +832,16 @@ func (resources *ResourceProvider) addResourceFromString(contents string) error
 			return err
 		}
 		workload.OriginalObjectYAML = contentBytes
+		workload.SourceFile = sourceFile
+		workload.SourceLine = sourceLine
 		resources.Resources.addResource(workload)
 	} else {
 		newResource, err := NewGenericResourceFromBytes(contentBytes)
 		if err != nil {
 			return err
 		}
+		newResource.SourceFile = sourceFile
+		newResource.SourceLine = sourceLine
 		resources.Resources.addResource(newResource)
 	}
 	return err
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -53,6 +54,41 @@ func TestGetResourcesFromPath(t *testing.T) {
 	assert.Equal(t, 1, namespaceCount["two"])
 }
 
+func TestCreateResourceProviderFromPaths(t *testing.T) {
+	c := conf.Configuration{}
+	multi, err := CreateResourceProvider(context.Background(), "./test_files/test_2", "", c)
+	assert.NoError(t, err)
+
+	provider, err := CreateResourceProviderFromPaths(context.Background(), []string{"./test_files/test_1", "./test_files/test_2", "./test_files/test_1/pods"}, c)
+	assert.NoError(t, err)
+	assert.Equal(t, "Path", provider.SourceType)
+	assert.Equal(t, "./test_files/test_1, ./test_files/test_2, ./test_files/test_1/pods", provider.SourceName)
+	assert.Equal(t, 10+multi.Resources.GetLength(), provider.Resources.GetLength(), "pods under both paths should only be added once")
+	assert.Equal(t, 3, len(provider.Namespaces))
+
+	provider, err = CreateResourceProviderFromPaths(context.Background(), []string{"./test_files/test_2", "./test_files/test_2/multi.yaml"}, c)
+	assert.NoError(t, err)
+	assert.Equal(t, multi.Resources.GetLength(), provider.Resources.GetLength())
+	assert.Equal(t, 2, len(provider.Namespaces), "namespaces under both paths should only be added once")
+
+	_, err = CreateResourceProviderFromPaths(context.Background(), []string{"-", "./test_files/test_2", "-"}, c)
+	assert.EqualError(t, err, "- can only be given once, since stdin can only be read once")
+}
+
+func TestDefaultNamespace(t *testing.T) {
+	c := conf.Configuration{DefaultNamespace: "staging"}
+	provider, err := CreateResourceProvider(context.Background(), "./test_files/test_1", "", c)
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", provider.Resources["apps/Deployment"][0].ObjectMeta.GetNamespace())
+	assert.Equal(t, "staging", provider.Resources["apps/Deployment"][0].Resource.GetNamespace())
+	assert.Equal(t, "staging", provider.Resources["Pod"][0].ObjectMeta.GetNamespace())
+	assert.Equal(t, "", provider.Resources["Namespace"][0].ObjectMeta.GetNamespace())
+
+	provider, err = CreateResourceProvider(context.Background(), "./test_files/test_2/multi.yaml", "", c)
+	assert.NoError(t, err)
+	assert.Equal(t, "polaris", provider.Resources["apps/Deployment"][0].ObjectMeta.GetNamespace())
+}
+
 func TestGetMultipleResourceFromSingleFile(t *testing.T) {
 	resources, err := CreateResourceProviderFromPath("./test_files/test_2/multi.yaml")
 
@@ -67,12 +103,49 @@ func TestGetMultipleResourceFromSingleFile(t *testing.T) {
 
 	assert.Equal(t, 1, len(resources.Resources["apps/Deployment"]), "Should have one controller")
 	assert.Equal(t, "dashboard", resources.Resources["apps/Deployment"][0].PodSpec.Containers[0].Name)
+	assert.Equal(t, "./test_files/test_2/multi.yaml", resources.Resources["apps/Deployment"][0].SourceFile)
+	assert.Equal(t, 8, resources.Resources["apps/Deployment"][0].SourceLine)
 
 	assert.Equal(t, 2, len(resources.Namespaces), "Should have a namespace")
 	assert.Equal(t, "polaris", resources.Namespaces[0].ObjectMeta.Name)
 	assert.Equal(t, "polaris-2", resources.Namespaces[1].ObjectMeta.Name)
 }
 
+func TestGetDefinitions(t *testing.T) {
+	provider := CreateResourceProviderFromYaml(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: other
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: settings
+  namespace: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: app
+`)
+	configMaps := provider.Resources["ConfigMap"]
+	assert.Len(t, configMaps, 3)
+	assert.Len(t, provider.GetDefinitions(configMaps[0]), 2)
+	assert.Len(t, provider.GetDefinitions(configMaps[1]), 1)
+	assert.Len(t, provider.GetDefinitions(provider.Resources["Service"][0]), 1)
+
+	unindexed := ResourceProvider{Resources: provider.Resources}
+	assert.Len(t, unindexed.GetDefinitions(configMaps[2]), 2)
+}
+
 func TestGetMultipleResourceFromBadFile(t *testing.T) {
 	_, err := CreateResourceProviderFromPath("./test_files/test_3")
 	assert.Equal(t, nil, err, "CreateResource From Path should not fail with bad yaml")
@@ -96,6 +169,29 @@ func TestAddResourcesFromReader(t *testing.T) {
 	assert.Equal(t, "polaris-2", resources.Namespaces[1].ObjectMeta.Name)
 }
 
+func TestAddResourcesFromStdin(t *testing.T) {
+	contents, err := os.ReadFile("./test_files/test_2/multi.yaml")
+	assert.NoError(t, err)
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+	go func() {
+		writer.Write(contents)
+		writer.Close()
+	}()
+	resources := newResourceProvider("unknown", "Path", "-")
+	err = resources.addResourcesFromStdin(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resources.Resources["apps/Deployment"]), "Should have one controller")
+	assert.Equal(t, 2, len(resources.Namespaces), "Should have two namespaces")
+
+	empty, err := os.CreateTemp(t.TempDir(), "stdin")
+	assert.NoError(t, err)
+	defer empty.Close()
+	resources = newResourceProvider("unknown", "Path", "-")
+	err = resources.addResourcesFromStdin(empty)
+	assert.EqualError(t, err, "Stdin is empty, expected YAML manifests for --audit-path -")
+}
+
 func TestGetResourceFromAPI(t *testing.T) {
 	k8s, dynamicInterface := test.SetupTestAPI(test.GetMockControllers("test")...)
 
@@ -113,6 +209,7 @@ func TestGetResourceFromAPI(t *testing.T) {
 		want        *ResourceProvider
 		wantErr     bool
 		clusterName string
+		wantKinds   []string
 	}{
 		{
 			name:        "standard",
@@ -136,6 +233,19 @@ func TestGetResourceFromAPI(t *testing.T) {
 				CreationTime: time.Now(),
 			},
 		},
+		{
+			name: "resource kinds",
+			config: conf.Configuration{
+				ResourceKinds: []string{"Deployment", "StatefulSet", "NotAKind"},
+			},
+			clusterName: "test4",
+			want: &ResourceProvider{
+				SourceType:   "Cluster",
+				SourceName:   "test4",
+				CreationTime: time.Now(),
+			},
+			wantKinds: []string{"apps/Deployment", "apps/StatefulSet"},
+		},
 		{
 			name: "namespace does not exist",
 			config: conf.Configuration{
@@ -160,6 +270,14 @@ func TestGetResourceFromAPI(t *testing.T) {
 					for k, v := range resources.Resources {
 						fmt.Println("cont", k, v)
 					}
+					if tt.wantKinds != nil {
+						kinds := []string{}
+						for kind := range resources.Resources {
+							kinds = append(kinds, kind)
+						}
+						assert.ElementsMatch(t, tt.wantKinds, kinds)
+						return
+					}
 					assert.Equal(t, 5, len(resources.Resources), "Should have 5 controllers")
 
 					for _, controllers := range resources.Resources {
@@ -175,3 +293,43 @@ func TestGetResourceFromAPI(t *testing.T) {
 		})
 	}
 }
+
+var testKubeconfig = `
+apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com
+- name: production
+  cluster:
+    server: https://production.example.com
+contexts:
+- name: staging
+  context:
+    cluster: staging
+- name: production
+  context:
+    cluster: production
+`
+
+func TestGetKubeConfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0600))
+
+	kubeConf, err := getKubeConfig(kubeconfig, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com", kubeConf.Host)
+	assert.Equal(t, 30, kubeConf.Burst)
+
+	kubeConf, err = getKubeConfig(kubeconfig, "production")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://production.example.com", kubeConf.Host)
+
+	_, err = getKubeConfig(kubeconfig, "development")
+	assert.ErrorContains(t, err, "development")
+
+	_, err = getKubeConfig(filepath.Join(t.TempDir(), "missing"), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// DiffAnnotation attributes lines of a mutated file to the checks whose mutations produced them
+type DiffAnnotation struct {
+	// FirstLine and LastLine are 1-based and inclusive, and refer to the mutated file
+	FirstLine int
+	LastLine  int
+	Checks    []string
+}
+
+// GetMutatedChecksFromResult returns the IDs of the checks with mutations for a single result
+func GetMutatedChecksFromResult(result *validator.Result) []string {
+	checks := map[string]bool{}
+	addChecks := func(rs validator.ResultSet) {
+		for _, resultMessage := range rs {
+			if len(resultMessage.Mutations) > 0 {
+				checks[resultMessage.ID] = true
+			}
+		}
+	}
+	addChecks(result.Results)
+	if result.PodResult != nil {
+		addChecks(result.PodResult.Results)
+		for _, containerResult := range result.PodResult.ContainerResults {
+			addChecks(containerResult.Results)
+		}
+	}
+	checkIDs := []string{}
+	for checkID := range checks {
+		checkIDs = append(checkIDs, checkID)
+	}
+	sort.Strings(checkIDs)
+	return checkIDs
+}
+
+// GetUnifiedDiff returns a unified diff of the changes from the original to the mutated content of a file,
+// or an empty string if they're the same. Each hunk header is followed by the checks of the annotations
+// overlapping the hunk, e.g. `@@ -4,6 +4,8 @@ hostIPCSet, runAsRootAllowed`.
+func GetUnifiedDiff(path string, original string, mutated string, annotations []DiffAnnotation) string {
+	originalLines := splitDiffLines(original)
+	mutatedLines := splitDiffLines(mutated)
+	matcher := difflib.NewMatcher(originalLines, mutatedLines)
+	hunks := matcher.GetGroupedOpCodes(diffContextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+	diff := strings.Builder{}
+	fmt.Fprintf(&diff, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, hunk := range hunks {
+		first, last := hunk[0], hunk[len(hunk)-1]
+		header := fmt.Sprintf("@@ -%s +%s @@", formatDiffRange(first.I1, last.I2), formatDiffRange(first.J1, last.J2))
+		if checks := getHunkChecks(first.J1, last.J2, annotations); len(checks) > 0 {
+			header += " " + strings.Join(checks, ", ")
+		}
+		diff.WriteString(header + "\n")
+		for _, code := range hunk {
+			if code.Tag == 'e' {
+				writeDiffLines(&diff, " ", originalLines[code.I1:code.I2])
+				continue
+			}
+			if code.Tag == 'r' || code.Tag == 'd' {
+				writeDiffLines(&diff, "-", originalLines[code.I1:code.I2])
+			}
+			if code.Tag == 'r' || code.Tag == 'i' {
+				writeDiffLines(&diff, "+", mutatedLines[code.J1:code.J2])
+			}
+		}
+	}
+	return diff.String()
+}
+
+// splitDiffLines splits content into lines, keeping their newlines so a missing newline at the end is kept
+func splitDiffLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// formatDiffRange formats the 0-based, half-open range of lines as in a unified diff hunk header
+func formatDiffRange(start int, stop int) string {
+	length := stop - start
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+func writeDiffLines(diff *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		diff.WriteString(prefix + line)
+		if !strings.HasSuffix(line, "\n") {
+			diff.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// getHunkChecks returns the checks of the annotations overlapping the 0-based, half-open range of mutated lines
+func getHunkChecks(start int, stop int, annotations []DiffAnnotation) []string {
+	if stop == start {
+		stop = start + 1
+	}
+	checks := map[string]bool{}
+	for _, annotation := range annotations {
+		if annotation.FirstLine <= stop && annotation.LastLine > start {
+			for _, check := range annotation.Checks {
+				checks[check] = true
+			}
+		}
+	}
+	checkIDs := []string{}
+	for check := range checks {
+		checkIDs = append(checkIDs, check)
+	}
+	sort.Strings(checkIDs)
+	return checkIDs
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+var diffOriginal = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  hostIPC: true
+  containers:
+    - name: web
+      image: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+var diffMutated = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx
+      securityContext:
+        runAsNonRoot: true
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func TestGetUnifiedDiff(t *testing.T) {
+	annotations := []DiffAnnotation{
+		{FirstLine: 1, LastLine: 10, Checks: []string{"hostIPCSet", "runAsRootAllowed"}},
+		{FirstLine: 12, LastLine: 15},
+	}
+	assert.Equal(t, `--- a/deploy/web.yaml
++++ b/deploy/web.yaml
+@@ -3,10 +3,11 @@ hostIPCSet, runAsRootAllowed
+ metadata:
+   name: web
+ spec:
+-  hostIPC: true
+   containers:
+     - name: web
+       image: nginx
++      securityContext:
++        runAsNonRoot: true
+ ---
+ apiVersion: v1
+ kind: Service
+`, GetUnifiedDiff("deploy/web.yaml", diffOriginal, diffMutated, annotations))
+
+	assert.Equal(t, "", GetUnifiedDiff("deploy/web.yaml", diffOriginal, diffOriginal, annotations))
+}
+
+func TestGetUnifiedDiffWithoutTrailingNewline(t *testing.T) {
+	assert.Equal(t, `--- a/web.yaml
++++ b/web.yaml
+@@ -1,2 +1,2 @@ hostPIDSet
+ spec:
+-  hostPID: true
+\ No newline at end of file
++  hostPID: false
+`, GetUnifiedDiff("web.yaml", "spec:\n  hostPID: true", "spec:\n  hostPID: false\n", []DiffAnnotation{{FirstLine: 1, LastLine: 2, Checks: []string{"hostPIDSet"}}}))
+}
+
+func TestGetMutatedChecksFromResult(t *testing.T) {
+	mutations := []config.Mutation{{Op: "remove", Path: "/spec/hostIPC"}}
+	result := validator.Result{
+		Results: validator.ResultSet{
+			"hostIPCSet":      {ID: "hostIPCSet", Mutations: mutations},
+			"tagNotSpecified": {ID: "tagNotSpecified"},
+		},
+		PodResult: &validator.PodResult{
+			ContainerResults: []validator.ContainerResult{{
+				Results: validator.ResultSet{"runAsRootAllowed": {ID: "runAsRootAllowed", Mutations: mutations}},
+			}},
+		},
+	}
+	assert.Equal(t, []string{"hostIPCSet", "runAsRootAllowed"}, GetMutatedChecksFromResult(&result))
+}
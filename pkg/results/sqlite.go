@@ -0,0 +1,159 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/thoas/go-funk"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+// SQLiteDriver is the database/sql driver used for SQLite output. It's only
+// registered when Polaris is built with the sqlite build tag.
+const SQLiteDriver = "sqlite"
+
+// ErrSQLiteUnavailable is returned when Polaris was built without SQLite support
+var ErrSQLiteUnavailable = errors.New("SQLite output is not available in this build of Polaris, rebuild it with `go build -tags sqlite`")
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS runs (
+		run_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		audit_time TEXT NOT NULL,
+		source_type TEXT,
+		source_name TEXT,
+		display_name TEXT,
+		score INTEGER,
+		successes INTEGER,
+		warnings INTEGER,
+		dangers INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS resources (
+		resource_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		run_id INTEGER NOT NULL REFERENCES runs(run_id),
+		namespace TEXT,
+		kind TEXT NOT NULL,
+		name TEXT NOT NULL,
+		owner TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS findings (
+		finding_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		resource_id INTEGER NOT NULL REFERENCES resources(resource_id),
+		container TEXT,
+		check_id TEXT NOT NULL,
+		category TEXT,
+		severity TEXT,
+		success INTEGER NOT NULL,
+		message TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS resources_run_id ON resources(run_id)`,
+	`CREATE INDEX IF NOT EXISTS findings_resource_id ON findings(resource_id)`,
+}
+
+// SQLiteAvailable returns true if Polaris was built with SQLite support
+func SQLiteAvailable() bool {
+	return funk.ContainsString(sql.Drivers(), SQLiteDriver)
+}
+
+// OpenSQLite opens or creates a SQLite database for audit results, creating the tables if needed
+func OpenSQLite(path string) (*sql.DB, error) {
+	if !SQLiteAvailable() {
+		return nil, ErrSQLiteUnavailable
+	}
+	db, err := sql.Open(SQLiteDriver, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// SaveToSQLite appends the audit as a new run, along with its resources and findings,
+// and returns the ID of the run
+func SaveToSQLite(db *sql.DB, auditData validator.AuditData) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	runID, err := saveRun(tx, auditData)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return runID, tx.Commit()
+}
+
+func saveRun(tx *sql.Tx, auditData validator.AuditData) (int64, error) {
+	summary := auditData.GetSummary()
+	res, err := tx.Exec(`INSERT INTO runs (audit_time, source_type, source_name, display_name, score, successes, warnings, dangers)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		auditData.AuditTime, auditData.SourceType, auditData.SourceName, auditData.DisplayName,
+		summary.GetScore(), summary.Successes, summary.Warnings, summary.Dangers)
+	if err != nil {
+		return 0, err
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	for _, result := range auditData.Results {
+		res, err := tx.Exec(`INSERT INTO resources (run_id, namespace, kind, name, owner) VALUES (?, ?, ?, ?, ?)`,
+			runID, result.Namespace, result.Kind, result.Name, result.Owner)
+		if err != nil {
+			return 0, err
+		}
+		resourceID, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		err = saveFindings(tx, resourceID, "", result.Results)
+		if err != nil {
+			return 0, err
+		}
+		if result.PodResult == nil {
+			continue
+		}
+		err = saveFindings(tx, resourceID, "", result.PodResult.Results)
+		if err != nil {
+			return 0, err
+		}
+		for _, containerResult := range result.PodResult.ContainerResults {
+			err = saveFindings(tx, resourceID, containerResult.Name, containerResult.Results)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return runID, nil
+}
+
+func saveFindings(tx *sql.Tx, resourceID int64, container string, rs validator.ResultSet) error {
+	for _, msg := range rs.GetSortedResults() {
+		_, err := tx.Exec(`INSERT INTO findings (resource_id, container, check_id, category, severity, success, message)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			resourceID, container, msg.ID, msg.Category, string(msg.Severity), msg.Success, msg.Message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
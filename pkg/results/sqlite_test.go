@@ -0,0 +1,74 @@
+//go:build sqlite
+
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+func TestSaveToSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	audit := validator.AuditData{
+		AuditTime:  "2022-01-01T00:00:00Z",
+		SourceType: "Path",
+		Results: []validator.Result{{
+			Kind:      "Deployment",
+			Name:      "api",
+			Namespace: "payments",
+			Results: validator.ResultSet{
+				"deploymentMissingReplicas": {ID: "deploymentMissingReplicas", Success: true, Severity: config.SeverityWarning},
+			},
+			PodResult: &validator.PodResult{
+				Results: validator.ResultSet{},
+				ContainerResults: []validator.ContainerResult{{
+					Name: "api",
+					Results: validator.ResultSet{
+						"cpuLimitsMissing": {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning},
+					},
+				}},
+			},
+		}},
+	}
+
+	for run := int64(1); run <= 2; run++ {
+		db, err := OpenSQLite(path)
+		assert.NoError(t, err)
+		runID, err := SaveToSQLite(db, audit)
+		assert.NoError(t, err)
+		assert.Equal(t, run, runID)
+		assert.NoError(t, db.Close())
+	}
+
+	db, err := OpenSQLite(path)
+	assert.NoError(t, err)
+	defer db.Close()
+	var runs, failures int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM runs`).Scan(&runs))
+	assert.Equal(t, 2, runs)
+	var container string
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*), MAX(f.container) FROM findings f
+		JOIN resources r ON r.resource_id = f.resource_id
+		WHERE r.run_id = 2 AND r.namespace = 'payments' AND f.success = 0`).Scan(&failures, &container))
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, "api", container)
+}
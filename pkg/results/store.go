@@ -0,0 +1,259 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+const (
+	// AuditResultKind is the kind of the custom resource used to store audit results
+	AuditResultKind = "AuditResult"
+	// DefaultNamespace is the namespace where audit results are stored by default
+	DefaultNamespace = "polaris"
+	// DefaultRetention is the number of audit results kept by default
+	DefaultRetention = 100
+	// MaxFailureStreaks limits the failure streaks stored with a result, to stay well under the
+	// size limit of etcd objects in clusters with many failures
+	MaxFailureStreaks = 5000
+	// resourceNamePrefix is the prefix the API server generates AuditResult names from
+	resourceNamePrefix = "audit-"
+)
+
+// AuditResultGVR identifies the AuditResult custom resource
+var AuditResultGVR = schema.GroupVersionResource{
+	Group:    "polaris.fairwinds.com",
+	Version:  "v1alpha1",
+	Resource: "auditresults",
+}
+
+// AuditResultSpec is the summary of a single audit stored in the cluster
+type AuditResultSpec struct {
+	AuditTime   string `json:"auditTime"`
+	SourceName  string `json:"sourceName,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Score       int64  `json:"score"`
+	Successes   int64  `json:"successes"`
+	Warnings    int64  `json:"warnings"`
+	Dangers     int64  `json:"dangers"`
+	// FailureStreaks counts the consecutive audits each finding has failed
+	FailureStreaks map[string]int64 `json:"failureStreaks,omitempty"`
+}
+
+// AuditResult is a stored audit
+type AuditResult struct {
+	Name string
+	Spec AuditResultSpec
+}
+
+// Store persists audit results as AuditResult custom resources
+type Store struct {
+	client    dynamic.Interface
+	namespace string
+}
+
+// NewStore creates a Store which keeps results in the given namespace
+func NewStore(client dynamic.Interface, namespace string) *Store {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return &Store{client: client, namespace: namespace}
+}
+
+// NewAuditResultSpec summarizes an audit along with the current failure streaks. Only the MaxFailureStreaks
+// longest streaks are kept, since shorter ones have the least effect on severity promotion.
+func NewAuditResultSpec(auditData validator.AuditData, streaks map[string]int) AuditResultSpec {
+	summary := auditData.GetSummary()
+	spec := AuditResultSpec{
+		AuditTime:      auditData.AuditTime,
+		SourceName:     auditData.SourceName,
+		DisplayName:    auditData.DisplayName,
+		Score:          int64(auditData.Score),
+		Successes:      int64(summary.Successes),
+		Warnings:       int64(summary.Warnings),
+		Dangers:        int64(summary.Dangers),
+		FailureStreaks: map[string]int64{},
+	}
+	keys := make([]string, 0, len(streaks))
+	for key := range streaks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if streaks[keys[i]] != streaks[keys[j]] {
+			return streaks[keys[i]] > streaks[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > MaxFailureStreaks {
+		logrus.Warnf("Only storing the %d longest of %d failure streaks", MaxFailureStreaks, len(keys))
+		keys = keys[:MaxFailureStreaks]
+	}
+	for _, key := range keys {
+		spec.FailureStreaks[key] = int64(streaks[key])
+	}
+	return spec
+}
+
+// GetFailureStreaks returns the failure streaks recorded with this result
+func (ar AuditResult) GetFailureStreaks() map[string]int {
+	streaks := map[string]int{}
+	for key, streak := range ar.Spec.FailureStreaks {
+		streaks[key] = int(streak)
+	}
+	return streaks
+}
+
+// Save stores a new AuditResult
+func (s *Store) Save(ctx context.Context, spec AuditResultSpec) (AuditResult, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec)
+	if err != nil {
+		return AuditResult{}, fmt.Errorf("converting audit result: %w", err)
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": content}}
+	obj.SetAPIVersion(AuditResultGVR.GroupVersion().String())
+	obj.SetKind(AuditResultKind)
+	// the name is generated, since several audits can finish in the same second
+	obj.SetGenerateName(resourceNamePrefix)
+	obj.SetNamespace(s.namespace)
+	created, err := s.client.Resource(AuditResultGVR).Namespace(s.namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return AuditResult{}, fmt.Errorf("storing audit result: %w", err)
+	}
+	return AuditResult{Name: created.GetName(), Spec: spec}, nil
+}
+
+// Prune deletes the oldest results, keeping the given number of the latest ones, and returns how many
+// were deleted. Nothing is deleted if keep is 0.
+func (s *Store) Prune(ctx context.Context, keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	results, err := s.List(ctx)
+	if err != nil || len(results) <= keep {
+		return 0, err
+	}
+	deleted := 0
+	for _, result := range results[:len(results)-keep] {
+		err := s.client.Resource(AuditResultGVR).Namespace(s.namespace).Delete(ctx, result.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("deleting audit result %s: %w", result.Name, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// List returns all stored results, oldest first
+func (s *Store) List(ctx context.Context) ([]AuditResult, error) {
+	list, err := s.client.Resource(AuditResultGVR).Namespace(s.namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		logrus.Debugf("No audit results found, the AuditResult CRD may not be installed: %v", err)
+		return []AuditResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing audit results: %w", err)
+	}
+	results := []AuditResult{}
+	for _, item := range list.Items {
+		specContent, _, err := unstructured.NestedMap(item.Object, "spec")
+		if err != nil {
+			return nil, fmt.Errorf("reading audit result %s: %w", item.GetName(), err)
+		}
+		result := AuditResult{Name: item.GetName()}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(specContent, &result.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("reading audit result %s: %w", item.GetName(), err)
+		}
+		results = append(results, result)
+	}
+	// audit times can have different offsets, so they're compared as times rather than strings
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].GetAuditTime().Before(results[j].GetAuditTime())
+	})
+	return results, nil
+}
+
+// GetAuditTime returns the time of the audit, or the zero time if it can't be parsed
+func (ar AuditResult) GetAuditTime() time.Time {
+	auditTime, err := time.Parse(time.RFC3339, ar.Spec.AuditTime)
+	if err != nil {
+		return time.Time{}
+	}
+	return auditTime
+}
+
+// FilterSince returns the results audited at or after the given time
+func FilterSince(results []AuditResult, since time.Time) []AuditResult {
+	filtered := []AuditResult{}
+	for _, result := range results {
+		if !result.GetAuditTime().Before(since) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// ParseSince parses a time window such as 7d, 12h, or 30m
+func ParseSince(window string) (time.Duration, error) {
+	if strings.HasSuffix(window, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(window, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid time window %q", window)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid time window %q", window)
+	}
+	return duration, nil
+}
+
+// GetPrettyOutput returns a table of audit results with their scores
+func GetPrettyOutput(results []AuditResult) string {
+	if len(results) == 0 {
+		return "No audit results found\n"
+	}
+	format := "%-25s  %-20s  %5v  %7v  %8v\n"
+	str := fmt.Sprintf(format, "AUDIT TIME", "NAME", "SCORE", "DANGERS", "WARNINGS")
+	for _, result := range results {
+		str += fmt.Sprintf(format, result.Spec.AuditTime, result.Name, result.Spec.Score, result.Spec.Dangers, result.Spec.Warnings)
+	}
+	return str
+}
+
+// Latest returns the most recently stored result, or nil if nothing has been stored yet
+func (s *Store) Latest(ctx context.Context) (*AuditResult, error) {
+	results, err := s.List(ctx)
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	return &results[len(results)-1], nil
+}
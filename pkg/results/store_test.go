@@ -0,0 +1,169 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8sTesting "k8s.io/client-go/testing"
+
+	"github.com/fairwindsops/polaris/pkg/validator"
+)
+
+func newTestStore() *Store {
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		AuditResultGVR: AuditResultKind + "List",
+	})
+	// the fake client doesn't generate names like the API server does
+	generated := 0
+	client.PrependReactor("create", "*", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8sTesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if obj.GetName() == "" && obj.GetGenerateName() != "" {
+			generated++
+			obj.SetName(fmt.Sprintf("%s%05d", obj.GetGenerateName(), generated))
+		}
+		return false, nil, nil
+	})
+	return NewStore(client, "")
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	latest, err := store.Latest(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+
+	first := validator.AuditData{AuditTime: "2022-01-01T00:00:00Z", Score: 80}
+	_, err = store.Save(ctx, NewAuditResultSpec(first, map[string]int{"ns/Deployment/api/api/cpuLimitsMissing": 1}))
+	assert.NoError(t, err)
+	second := validator.AuditData{AuditTime: "2022-01-02T00:00:00Z", Score: 90}
+	_, err = store.Save(ctx, NewAuditResultSpec(second, map[string]int{"ns/Deployment/api/api/cpuLimitsMissing": 2}))
+	assert.NoError(t, err)
+
+	results, err := store.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, int64(80), results[0].Spec.Score)
+
+	latest, err = store.Latest(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90), latest.Spec.Score)
+	assert.Equal(t, map[string]int{"ns/Deployment/api/api/cpuLimitsMissing": 2}, latest.GetFailureStreaks())
+}
+
+func TestStoreSameSecond(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	audit := validator.AuditData{AuditTime: "2022-01-01T00:00:00Z", Score: 80}
+	first, err := store.Save(ctx, NewAuditResultSpec(audit, map[string]int{}))
+	assert.NoError(t, err)
+	second, err := store.Save(ctx, NewAuditResultSpec(audit, map[string]int{}))
+	assert.NoError(t, err, "audits finishing in the same second should both be stored")
+	assert.NotEqual(t, first.Name, second.Name)
+}
+
+func TestStoreListOrdersByTime(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	for _, auditTime := range []string{"2022-01-01T08:00:00Z", "2022-01-01T12:00:00+05:00", "2022-01-01T09:00:00-01:00"} {
+		_, err := store.Save(ctx, NewAuditResultSpec(validator.AuditData{AuditTime: auditTime}, map[string]int{}))
+		assert.NoError(t, err)
+	}
+
+	results, err := store.List(ctx)
+	assert.NoError(t, err)
+	auditTimes := []string{}
+	for _, result := range results {
+		auditTimes = append(auditTimes, result.Spec.AuditTime)
+	}
+	assert.Equal(t, []string{"2022-01-01T12:00:00+05:00", "2022-01-01T08:00:00Z", "2022-01-01T09:00:00-01:00"}, auditTimes)
+}
+
+func TestStorePrune(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	for day := 1; day <= 5; day++ {
+		audit := validator.AuditData{AuditTime: fmt.Sprintf("2022-01-0%dT00:00:00Z", day), Score: uint(day)}
+		_, err := store.Save(ctx, NewAuditResultSpec(audit, map[string]int{}))
+		assert.NoError(t, err)
+	}
+
+	deleted, err := store.Prune(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted, "nothing should be deleted without a limit")
+
+	deleted, err = store.Prune(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	results, err := store.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, int64(4), results[0].Spec.Score, "the latest results should be kept")
+	assert.Equal(t, int64(5), results[1].Spec.Score)
+
+	deleted, err = store.Prune(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func TestNewAuditResultSpecLimitsStreaks(t *testing.T) {
+	streaks := map[string]int{}
+	for i := 0; i < MaxFailureStreaks+10; i++ {
+		streaks[fmt.Sprintf("ns/Deployment/app-%d//cpuLimitsMissing", i)] = 1
+	}
+	streaks["ns/Deployment/api//cpuLimitsMissing"] = 7
+
+	spec := NewAuditResultSpec(validator.AuditData{}, streaks)
+	assert.Len(t, spec.FailureStreaks, MaxFailureStreaks)
+	assert.Equal(t, int64(7), spec.FailureStreaks["ns/Deployment/api//cpuLimitsMissing"], "the longest streaks should be kept")
+}
+
+func TestFilterSince(t *testing.T) {
+	window, err := ParseSince("7d")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, window)
+	window, err = ParseSince("12h")
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, window)
+	_, err = ParseSince("a week")
+	assert.Error(t, err)
+	_, err = ParseSince("-1d")
+	assert.Error(t, err)
+
+	results := []AuditResult{
+		{Name: "old", Spec: AuditResultSpec{AuditTime: "2022-01-01T00:00:00Z"}},
+		{Name: "new", Spec: AuditResultSpec{AuditTime: "2022-01-08T00:00:00Z"}},
+	}
+	since, _ := time.Parse(time.RFC3339, "2022-01-05T00:00:00Z")
+	filtered := FilterSince(results, since)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "new", filtered[0].Name)
+
+	assert.Equal(t, "No audit results found\n", GetPrettyOutput(nil))
+	assert.Contains(t, GetPrettyOutput(filtered), "2022-01-08T00:00:00Z")
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry retries HTTP requests which a rate-limited server asked to be retried later, and idempotent
+// requests which failed.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxRetries limits retries of servers which keep asking to retry without a delay
+const maxRetries = 5
+
+// defaultBackoff is the wait before the first retry of a failed request, when Backoff isn't set
+const defaultBackoff = time.Second
+
+// Transport retries requests answered with 429 Too Many Requests or 503 Service Unavailable and a
+// Retry-After header, waiting as long as the server asked. With Retries, idempotent requests failing with
+// a network error or a 5xx response are also retried.
+type Transport struct {
+	Base http.RoundTripper
+	// MaxWait caps the total time spent waiting to retry a request. Responses asking for a longer
+	// wait are returned as they are. Zero disables retries.
+	MaxWait time.Duration
+	// Retries is the number of times idempotent requests, e.g. GETs, failing with a network error or a 5xx
+	// response are retried, with exponential backoff. Other requests, like uploads, aren't retried, since the
+	// server may have handled them already.
+	Retries int
+	// Backoff is the wait before the first of the Retries, which doubles for each further one. Zero waits a second.
+	Backoff time.Duration
+	// AttemptTimeout limits each attempt of a request, rather than all of them together. Zero disables it.
+	AttemptTimeout time.Duration
+}
+
+// NewTransport wraps base, or the default transport if base is nil
+func NewTransport(base http.RoundTripper, maxWait time.Duration) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, MaxWait: maxWait}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	waited := time.Duration(0)
+	rateLimited := 0
+	failures := 0
+	backoff := t.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for {
+		resp, err := t.attempt(req)
+		var wait time.Duration
+		var reason string
+		if retryAfter, ok := t.getRetryAfter(req, resp, err, waited); ok && rateLimited < maxRetries {
+			rateLimited++
+			waited += retryAfter
+			wait = retryAfter
+			reason = "responded " + resp.Status
+		} else if failures < t.Retries && isIdempotent(req) && (err != nil || (resp.StatusCode >= 500 && resp.Header.Get("Retry-After") == "")) {
+			failures++
+			wait = backoff
+			backoff *= 2
+			if err != nil {
+				reason = fmt.Sprintf("failed: %v", err)
+			} else {
+				reason = "responded " + resp.Status
+			}
+		} else {
+			return resp, err
+		}
+		// the body has already been sent, so it can only be retried if it can be read again
+		retryReq := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			retryReq.Body = body
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logrus.Infof("%s %s %s, retrying after %s", req.Method, req.URL.Host, reason, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		req = retryReq
+	}
+}
+
+// getRetryAfter returns how long a rate-limited server asked to wait before retrying, if it's within MaxWait
+func (t *Transport) getRetryAfter(req *http.Request, resp *http.Response, err error, waited time.Duration) (time.Duration, bool) {
+	if err != nil || t.MaxWait <= 0 {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	wait, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+	if waited+wait > t.MaxWait {
+		logrus.Warnf("%s asked to retry after %s, which exceeds the maximum wait of %s", req.URL.Host, wait, t.MaxWait)
+		return 0, false
+	}
+	return wait, true
+}
+
+// attempt sends the request once, limited by AttemptTimeout until the response body is closed
+func (t *Transport) attempt(req *http.Request) (*http.Response, error) {
+	if t.AttemptTimeout <= 0 {
+		return t.Base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.AttemptTimeout)
+	resp, err := t.Base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of an attempt once its response has been read
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// isIdempotent returns true if sending the request again has the same effect as sending it once
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// ParseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date,
+// into the time to wait from now
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
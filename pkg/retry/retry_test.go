@@ -0,0 +1,161 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	wait, ok := ParseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, wait)
+
+	wait, ok = ParseRetryAfter("Sat, 01 Jan 2022 12:00:30 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+
+	wait, ok = ParseRetryAfter("Sat, 01 Jan 2022 11:00:00 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), wait)
+
+	_, ok = ParseRetryAfter("", now)
+	assert.False(t, ok)
+	_, ok = ParseRetryAfter("-1", now)
+	assert.False(t, ok)
+	_, ok = ParseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
+
+func TestTransport(t *testing.T) {
+	var requests atomic.Int32
+	var lock sync.Mutex
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		bodies = append(bodies, string(body))
+		lock.Unlock()
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.URL.Path == "/slow" {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, time.Minute)}
+	resp, err := client.Post(server.URL, "text/plain", bytes.NewBufferString("audit"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	lock.Lock()
+	assert.Equal(t, []string{"audit", "audit"}, bodies)
+	lock.Unlock()
+
+	resp, err = client.Get(server.URL + "/slow")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "waits longer than the maximum should not be retried")
+	assert.Equal(t, int32(3), requests.Load())
+
+	// a streamed body can't be sent again
+	requests.Store(0)
+	resp, err = client.Post(server.URL, "text/plain", io.NopCloser(bytes.NewBufferString("stream")))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestTransportRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := requests.Add(1)
+		io.ReadAll(r.Body)
+		if attempt < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, time.Minute)
+	transport.Retries = 2
+	transport.Backoff = time.Millisecond
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), requests.Load())
+
+	requests.Store(0)
+	resp, err = client.Post(server.URL, "text/plain", bytes.NewBufferString("audit"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode, "requests which aren't idempotent shouldn't be retried")
+	assert.Equal(t, int32(1), requests.Load())
+
+	requests.Store(0)
+	transport.Retries = 1
+	resp, err = client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestTransportAttemptTimeout(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, 0)
+	transport.Retries = 1
+	transport.Backoff = time.Millisecond
+	transport.AttemptTimeout = 20 * time.Millisecond
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body), "the body should be readable after the attempt returns")
+	assert.Equal(t, int32(2), requests.Load())
+}
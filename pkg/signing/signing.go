@@ -0,0 +1,85 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// ParsePrivateKey parses a PEM-encoded PKCS #8 ed25519 private key
+func ParsePrivateKey(pemBytes []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM data found in signing key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key: %w", err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key must be an ed25519 key, got %T", key)
+	}
+	return privateKey, nil
+}
+
+// ParsePublicKey parses a PEM-encoded ed25519 public key. A private key is also accepted,
+// in which case its public key is returned.
+func ParsePublicKey(pemBytes []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM data found in key")
+	}
+	if block.Type == "PRIVATE KEY" {
+		privateKey, err := ParsePrivateKey(pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.Public().(ed25519.PublicKey), nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key must be an ed25519 key, got %T", key)
+	}
+	return publicKey, nil
+}
+
+// Sign returns a base64-encoded detached signature over data
+func Sign(data []byte, key ed25519.PrivateKey) []byte {
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	return []byte(signature + "\n")
+}
+
+// Verify checks a detached signature created with Sign
+func Verify(data, signature []byte, key ed25519.PublicKey) error {
+	rawSignature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	if !ed25519.Verify(key, data, rawSignature) {
+		return errors.New("signature does not match, the results may have been altered")
+	}
+	return nil
+}
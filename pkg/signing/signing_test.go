@@ -0,0 +1,55 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.NoError(t, err)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	parsedPrivate, err := ParsePrivateKey(privatePEM)
+	assert.NoError(t, err)
+	results := []byte(`{"Score": 90}`)
+	signature := Sign(results, parsedPrivate)
+
+	parsedPublic, err := ParsePublicKey(publicPEM)
+	assert.NoError(t, err)
+	assert.NoError(t, Verify(results, signature, parsedPublic))
+
+	derivedPublic, err := ParsePublicKey(privatePEM)
+	assert.NoError(t, err)
+	assert.NoError(t, Verify(results, signature, derivedPublic))
+
+	assert.Error(t, Verify([]byte(`{"Score": 100}`), signature, parsedPublic))
+	assert.Error(t, Verify(results, []byte("not base64!"), parsedPublic))
+
+	_, err = ParsePrivateKey([]byte("not a key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "sync"
+
+// ScoreAccumulator keeps a running summary of results as they arrive, e.g. from
+// StreamAllSchemaChecksToResourceProvider, so the current score can be shown during an audit without
+// summarizing every result again. Once it has every result of an audit, its score matches the audit's.
+type ScoreAccumulator struct {
+	granularity ScoreGranularity
+	onUpdate    func(CountSummary)
+	mutex       sync.Mutex
+	summary     CountSummary
+}
+
+// NewScoreAccumulator returns an accumulator which counts container results at the given granularity,
+// and calls onUpdate with the running summary after each result. onUpdate may be nil.
+func NewScoreAccumulator(granularity ScoreGranularity, onUpdate func(CountSummary)) *ScoreAccumulator {
+	return &ScoreAccumulator{granularity: granularity, onUpdate: onUpdate}
+}
+
+// AddResult adds a resource's results to the running summary
+func (a *ScoreAccumulator) AddResult(result Result) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.summary.AddSummary(result.getSummary(a.granularity))
+	if a.onUpdate != nil {
+		a.onUpdate(a.summary)
+	}
+}
+
+// GetSummary returns the summary of the results added so far
+func (a *ScoreAccumulator) GetSummary() CountSummary {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.summary
+}
+
+// GetScore returns the score of the results added so far
+func (a *ScoreAccumulator) GetScore() uint {
+	return a.GetSummary().GetScore()
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+func TestScoreAccumulator(t *testing.T) {
+	c, err := conf.ParseFile("")
+	assert.NoError(t, err)
+	c.Parallelism = 4
+	resources, err := kube.CreateResourceProviderFromPath("../kube/test_files/test_1")
+	assert.NoError(t, err)
+
+	for _, granularity := range []ScoreGranularity{ScoreGranularityContainer, ScoreGranularityPod} {
+		updates := []CountSummary{}
+		accumulator := NewScoreAccumulator(granularity, func(summary CountSummary) {
+			updates = append(updates, summary)
+		})
+		results, err := StreamAllSchemaChecksToResourceProvider(&c, resources, accumulator.AddResult)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, results)
+		assert.Len(t, updates, len(results), "every result should update the score")
+
+		auditData := AuditData{Results: results}.SetScoreGranularity(granularity)
+		assert.Equal(t, auditData.GetSummary(), accumulator.GetSummary(), string(granularity))
+		assert.Equal(t, auditData.GetSummary().GetScore(), accumulator.GetScore(), string(granularity))
+		assert.Equal(t, auditData.Score, updates[len(updates)-1].GetScore(), string(granularity))
+	}
+
+	assert.Equal(t, uint(100), NewScoreAccumulator(ScoreGranularityContainer, nil).GetScore(), "an empty audit scores 100")
+}
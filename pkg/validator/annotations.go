@@ -0,0 +1,65 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const requiredAnnotationsCheckID = "requiredAnnotationsMissing"
+
+// applyRequiredAnnotationsCheck checks that a resource has every annotation required for its kind
+func applyRequiredAnnotationsCheck(conf *config.Configuration, resource kube.GenericResource) *ResultMessage {
+	annotations := resource.ObjectMeta.GetAnnotations()
+	required := conf.RequiredAnnotations.GetAnnotationsForResource(resource.Kind, annotations)
+	if len(required) == 0 {
+		return nil
+	}
+	severity, ok := getPolicyCheckSeverity(conf, requiredAnnotationsCheckID, resource, "")
+	if !ok {
+		return nil
+	}
+	missing := []string{}
+	for _, annotation := range required {
+		found := false
+		for _, key := range annotation.AnyOf {
+			if _, ok := annotations[key]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, strings.Join(annotation.AnyOf, " or "))
+		}
+	}
+	result := ResultMessage{
+		ID:       requiredAnnotationsCheckID,
+		Severity: severity,
+		Category: "Reliability",
+		Priority: getResultPriority(conf, requiredAnnotationsCheckID),
+		Success:  len(missing) == 0,
+	}
+	if result.Success {
+		result.Message = "All required annotations are set"
+		return &result
+	}
+	result.Details = missing
+	result.Message = fmt.Sprintf("Required annotations are not set: missing %s", strings.Join(missing, ", "))
+	return &result
+}
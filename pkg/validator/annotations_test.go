@@ -0,0 +1,95 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var annotationsTestYaml = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  namespace: web
+  annotations:
+    cert-manager.io/common-name: web.example.com
+spec:
+  ingressClassName: nginx
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: internal
+  namespace: web
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: api
+  namespace: web
+  annotations:
+    cert-manager.io/issuer: letsencrypt
+`
+
+func TestRequiredAnnotations(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(annotationsTestYaml)
+	ingresses := provider.Resources["networking.k8s.io/Ingress"]
+	assert.Len(t, ingresses, 3)
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	result, err := applyNonControllerSchemaChecks(&c, provider, ingresses[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredAnnotationsCheckID, "should be off by default")
+
+	c.RequiredAnnotations = conf.RequiredAnnotations{
+		Kinds: map[string][]conf.RequiredAnnotation{
+			"Ingress": {
+				{AnyOf: []string{"cert-manager.io/cluster-issuer", "cert-manager.io/issuer"}, IfAnnotationPrefix: "cert-manager.io/"},
+			},
+		},
+	}
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingresses[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredAnnotationsCheckID, "should be off until it's given a severity")
+
+	c.Checks[requiredAnnotationsCheckID] = conf.SeverityWarning
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingresses[0])
+	assert.NoError(t, err)
+	annotations := result.Results[requiredAnnotationsCheckID]
+	assert.False(t, annotations.Success)
+	assert.Equal(t, conf.SeverityWarning, annotations.Severity)
+	assert.Equal(t, "Reliability", annotations.Category)
+	assert.Equal(t, "Required annotations are not set: missing cert-manager.io/cluster-issuer or cert-manager.io/issuer", annotations.Message)
+	assert.Equal(t, []string{"cert-manager.io/cluster-issuer or cert-manager.io/issuer"}, annotations.Details)
+
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingresses[1])
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredAnnotationsCheckID, "ingresses without cert-manager annotations shouldn't need an issuer")
+
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingresses[2])
+	assert.NoError(t, err)
+	assert.True(t, result.Results[requiredAnnotationsCheckID].Success)
+
+	c.Checks[requiredAnnotationsCheckID] = conf.SeverityIgnore
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingresses[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredAnnotationsCheckID)
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const pseudonymHashLength = 10
+
+// anonymizeKeyLength is the length of the random key used when none is configured
+const anonymizeKeyLength = 32
+
+// AnonymizeKeyEnvVar is the environment variable with the key pseudonyms are derived from, to keep them
+// the same across runs. A random key is used for each run when it isn't set.
+const AnonymizeKeyEnvVar = "POLARIS_ANONYMIZE_KEY"
+
+// Anonymizer replaces cluster and namespace identifiers with stable pseudonyms
+type Anonymizer struct {
+	// Mapping maps each pseudonym back to the original identifier
+	Mapping map[string]string
+	key     []byte
+}
+
+// NewAnonymizer creates an Anonymizer with an empty mapping. Pseudonyms are an HMAC of the identifier with
+// the key, so they can't be reversed by hashing guessed names. A random key is generated when it's empty.
+func NewAnonymizer(key []byte) (*Anonymizer, error) {
+	if len(key) == 0 {
+		key = make([]byte, anonymizeKeyLength)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	return &Anonymizer{
+		Mapping: map[string]string{},
+		key:     key,
+	}, nil
+}
+
+// Pseudonym returns a pseudonym for the given identifier, which is the same for every use of the key
+func (a *Anonymizer) Pseudonym(prefix, value string) string {
+	if value == "" {
+		return value
+	}
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(prefix + "/" + value))
+	pseudonym := prefix + "-" + hex.EncodeToString(mac.Sum(nil))[:pseudonymHashLength]
+	a.Mapping[pseudonym] = value
+	return pseudonym
+}
+
+// Anonymize returns a copy of the audit with cluster, namespace, app, and file identifiers replaced,
+// along with string metadata values
+func (a *Anonymizer) Anonymize(res AuditData) AuditData {
+	resCopy := res
+	resCopy.SourceName = a.Pseudonym("cluster", res.SourceName)
+	resCopy.DisplayName = a.Pseudonym("cluster", res.DisplayName)
+	if res.Metadata != nil {
+		resCopy.Metadata = map[string]interface{}{}
+		for key, value := range res.Metadata {
+			if str, ok := value.(string); ok {
+				value = a.Pseudonym("metadata", str)
+			}
+			resCopy.Metadata[key] = value
+		}
+	}
+	resCopy.Results = make([]Result, len(res.Results))
+	for idx, result := range res.Results {
+		result.Namespace = a.Pseudonym("namespace", result.Namespace)
+		if result.Kind == "Namespace" {
+			result.Name = a.Pseudonym("namespace", result.Name)
+		}
+		result.App = a.Pseudonym("app", result.App)
+		result.SourceFile = a.Pseudonym("file", result.SourceFile)
+		resCopy.Results[idx] = result
+	}
+	if res.FileIndex != nil {
+		resCopy.FileIndex = map[string][]FileIndexEntry{}
+		for file, entries := range res.FileIndex {
+			anonEntries := make([]FileIndexEntry, len(entries))
+			for idx, entry := range entries {
+				entry.Namespace = a.Pseudonym("namespace", entry.Namespace)
+				if entry.Kind == "Namespace" {
+					entry.Name = a.Pseudonym("namespace", entry.Name)
+				}
+				anonEntries[idx] = entry
+			}
+			resCopy.FileIndex[a.Pseudonym("file", file)] = anonEntries
+		}
+	}
+	return resCopy
+}
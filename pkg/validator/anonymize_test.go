@@ -0,0 +1,71 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymize(t *testing.T) {
+	audit := AuditData{
+		SourceType:  "Cluster",
+		SourceName:  "https://prod.example.com",
+		DisplayName: "prod",
+		Metadata:    map[string]interface{}{"team": "payments-team", "replicas": 3},
+		FileIndex: map[string][]FileIndexEntry{
+			"deploy/api.yaml": {{Kind: "Deployment", Name: "api", Namespace: "payments"}},
+		},
+		Results: []Result{
+			{Kind: "Deployment", Name: "api", Namespace: "payments", App: "checkout", SourceFile: "deploy/api.yaml"},
+			{Kind: "StatefulSet", Name: "db", Namespace: "payments"},
+			{Kind: "Namespace", Name: "payments"},
+		},
+	}
+
+	key := []byte("test-key")
+	anonymizer, err := NewAnonymizer(key)
+	assert.NoError(t, err)
+	anon := anonymizer.Anonymize(audit)
+
+	assert.True(t, strings.HasPrefix(anon.SourceName, "cluster-"))
+	assert.NotEqual(t, audit.SourceName, anon.SourceName)
+	assert.NotEqual(t, audit.DisplayName, anon.DisplayName)
+	assert.Equal(t, "api", anon.Results[0].Name)
+	assert.True(t, strings.HasPrefix(anon.Results[0].Namespace, "namespace-"))
+	assert.Equal(t, anon.Results[0].Namespace, anon.Results[1].Namespace)
+	assert.Equal(t, anon.Results[0].Namespace, anon.Results[2].Name)
+	assert.Equal(t, "payments", audit.Results[0].Namespace, "original audit should not be modified")
+	assert.True(t, strings.HasPrefix(anon.Results[0].App, "app-"))
+	assert.True(t, strings.HasPrefix(anon.Results[0].SourceFile, "file-"))
+	assert.Contains(t, anon.FileIndex, anon.Results[0].SourceFile)
+	assert.Equal(t, anon.Results[0].Namespace, anon.FileIndex[anon.Results[0].SourceFile][0].Namespace)
+	assert.True(t, strings.HasPrefix(anon.Metadata["team"].(string), "metadata-"))
+	assert.Equal(t, 3, anon.Metadata["replicas"])
+	assert.Equal(t, "payments-team", audit.Metadata["team"], "original metadata should not be modified")
+
+	assert.Equal(t, "payments", anonymizer.Mapping[anon.Results[0].Namespace])
+	assert.Equal(t, "https://prod.example.com", anonymizer.Mapping[anon.SourceName])
+
+	again, err := NewAnonymizer(key)
+	assert.NoError(t, err)
+	assert.Equal(t, anon.Results[0].Namespace, again.Anonymize(audit).Results[0].Namespace, "pseudonyms should be stable with the same key")
+
+	otherKey, err := NewAnonymizer(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, anon.Results[0].Namespace, otherKey.Anonymize(audit).Results[0].Namespace, "pseudonyms should depend on the key")
+}
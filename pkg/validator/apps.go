@@ -0,0 +1,80 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AppSummary provides the score and counts for the resources rendered from a GitOps app
+type AppSummary struct {
+	App       string
+	Score     uint
+	Resources int
+	CountSummary
+}
+
+// SetApp tags every result in the audit with the GitOps app it was rendered from
+func (res AuditData) SetApp(app string) AuditData {
+	resCopy := res
+	resCopy.Results = make([]Result, len(res.Results))
+	for idx, result := range res.Results {
+		result.App = app
+		resCopy.Results[idx] = result
+	}
+	return resCopy
+}
+
+// GetAppSummaries aggregates results by GitOps app. Every app given is included,
+// so apps without any resources still appear with a perfect score.
+func (res AuditData) GetAppSummaries(apps []string) []AppSummary {
+	byApp := map[string]*AppSummary{}
+	for _, app := range apps {
+		byApp[app] = &AppSummary{App: app}
+	}
+	for _, result := range res.Results {
+		if _, ok := byApp[result.App]; !ok {
+			byApp[result.App] = &AppSummary{App: result.App}
+		}
+		byApp[result.App].Resources++
+		byApp[result.App].AddSummary(result.getSummary(res.ScoreGranularity))
+	}
+	summaries := []AppSummary{}
+	for _, summary := range byApp {
+		summary.Score = summary.GetScore()
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].App < summaries[j].App
+	})
+	return summaries
+}
+
+// GetAppSummaryPrettyOutput returns a human-readable table of app summaries
+func GetAppSummaryPrettyOutput(summaries []AppSummary) string {
+	width := len("APP")
+	for _, summary := range summaries {
+		if len(summary.App) > width {
+			width = len(summary.App)
+		}
+	}
+	format := fmt.Sprintf("%%-%ds  %%5v  %%9v  %%7v  %%8v\n", width)
+	str := fmt.Sprintf(format, "APP", "SCORE", "RESOURCES", "DANGERS", "WARNINGS")
+	for _, summary := range summaries {
+		str += fmt.Sprintf(format, summary.App, summary.Score, summary.Resources, summary.Dangers, summary.Warnings)
+	}
+	return str
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAppSummaries(t *testing.T) {
+	audit := getOutputTestAudit()
+	tagged := audit.SetApp("payments")
+	assert.Equal(t, "payments", tagged.Results[0].App)
+	assert.Equal(t, "payments", tagged.Results[1].App)
+	assert.Equal(t, "", audit.Results[0].App)
+
+	tagged.Results[1].App = "rbac"
+	summaries := tagged.GetAppSummaries([]string{"empty", "payments", "rbac"})
+	assert.Len(t, summaries, 3)
+
+	assert.Equal(t, "empty", summaries[0].App)
+	assert.Equal(t, 0, summaries[0].Resources)
+	assert.Equal(t, uint(100), summaries[0].Score)
+
+	assert.Equal(t, "payments", summaries[1].App)
+	assert.Equal(t, 1, summaries[1].Resources)
+	assert.Equal(t, audit.Results[0].GetSummary().GetScore(), summaries[1].Score)
+
+	assert.Equal(t, "rbac", summaries[2].App)
+	assert.Equal(t, uint(100), summaries[2].Score)
+
+	pretty := GetAppSummaryPrettyOutput(summaries)
+	lines := strings.Split(strings.TrimSpace(pretty), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "APP"))
+	assert.True(t, strings.HasPrefix(lines[2], "payments"))
+}
@@ -87,3 +87,46 @@ func TestValidateIngress(t *testing.T) {
 	assert.Equal(t, "Security", results.Category)
 	assert.EqualValues(t, "Ingress has TLS configured", results.Message)
 }
+
+func TestValidateWithCheckPathOverride(t *testing.T) {
+	c, err := conf.Parse([]byte(`
+checks:
+  widgetReplicas: warning
+customChecks:
+  widgetReplicas:
+    successMessage: Replicas are set
+    failureMessage: Replicas should be set
+    category: Reliability
+    target: example.com/Widget
+    schema:
+      '$schema': http://json-schema.org/draft-07/schema
+      type: object
+      required:
+      - replicas
+`))
+	assert.NoError(t, err)
+	unst := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "foo"},
+		"spec": map[string]interface{}{
+			"workload": map[string]interface{}{"replicas": 2},
+		},
+	}}
+	res, err := kube.NewGenericResourceFromUnstructured(unst, nil)
+	assert.NoError(t, err)
+
+	actualResult, err := applyNonControllerSchemaChecks(&c, nil, res)
+	assert.NoError(t, err)
+	assert.False(t, actualResult.Results["widgetReplicas"].Success)
+
+	c.CheckPaths = map[string]string{"widgetReplicas": "spec.workload"}
+	actualResult, err = applyNonControllerSchemaChecks(&c, nil, res)
+	assert.NoError(t, err)
+	assert.True(t, actualResult.Results["widgetReplicas"].Success)
+
+	c.CheckPaths = map[string]string{"widgetReplicas": "spec.missing"}
+	actualResult, err = applyNonControllerSchemaChecks(&c, nil, res)
+	assert.NoError(t, err)
+	assert.False(t, actualResult.Results["widgetReplicas"].Success)
+}
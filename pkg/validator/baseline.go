@@ -0,0 +1,170 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// GateExitCodeNamespaceRegression is the exit code used when a namespace's score regressed beyond the threshold
+const GateExitCodeNamespaceRegression = 6
+
+// BaselineEntry identifies a single known failure
+type BaselineEntry struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Container string `json:",omitempty"`
+	Check     string
+}
+
+func (e BaselineEntry) key() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", e.Namespace, e.Kind, e.Name, e.Container, e.Check)
+}
+
+// Baseline contains known failures which should be suppressed in subsequent audits
+type Baseline struct {
+	Entries []BaselineEntry
+	// NamespaceScores are the scores of each namespace when the baseline was captured
+	NamespaceScores map[string]uint `json:",omitempty"`
+}
+
+// NamespaceRegression describes a namespace whose score dropped below its baseline score
+type NamespaceRegression struct {
+	Namespace     string
+	BaselineScore uint
+	Score         uint
+}
+
+// NewBaselineFromAudit captures every failure in the audit as a baseline entry
+func NewBaselineFromAudit(auditData AuditData) Baseline {
+	baseline := Baseline{Entries: []BaselineEntry{}, NamespaceScores: map[string]uint{}}
+	for namespace, summary := range auditData.GetSummaryByNamespace() {
+		baseline.NamespaceScores[namespace] = summary.GetScore()
+	}
+	add := func(result Result, container string, rs ResultSet) {
+		for _, msg := range rs.GetSortedResults() {
+			if msg.Success {
+				continue
+			}
+			baseline.Entries = append(baseline.Entries, BaselineEntry{
+				Namespace: result.Namespace,
+				Kind:      result.Kind,
+				Name:      result.Name,
+				Container: container,
+				Check:     msg.ID,
+			})
+		}
+	}
+	for _, result := range auditData.Results {
+		add(result, "", result.Results)
+		if result.PodResult != nil {
+			add(result, "", result.PodResult.Results)
+			for _, cr := range result.PodResult.ContainerResults {
+				add(result, cr.Name, cr.Results)
+			}
+		}
+	}
+	return baseline
+}
+
+// ReadBaselineFromFile reads a baseline previously written with WriteToFile
+func ReadBaselineFromFile(fileName string) (Baseline, error) {
+	baseline := Baseline{}
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return baseline, err
+	}
+	err = json.Unmarshal(contents, &baseline)
+	if err != nil {
+		return baseline, fmt.Errorf("parsing baseline %s: %w", fileName, err)
+	}
+	return baseline, nil
+}
+
+// WriteToFile writes the baseline as JSON
+func (b Baseline) WriteToFile(fileName string) error {
+	contents, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, contents, 0644)
+}
+
+// ApplyBaseline removes failures which are present in the baseline, so only new issues are reported
+func (res AuditData) ApplyBaseline(b Baseline) AuditData {
+	known := map[string]bool{}
+	for _, entry := range b.Entries {
+		known[entry.key()] = true
+	}
+	filter := func(result Result, container string, rs ResultSet) ResultSet {
+		filtered := ResultSet{}
+		for id, msg := range rs {
+			entry := BaselineEntry{Namespace: result.Namespace, Kind: result.Kind, Name: result.Name, Container: container, Check: msg.ID}
+			if !msg.Success && known[entry.key()] {
+				continue
+			}
+			filtered[id] = msg
+		}
+		return filtered
+	}
+	resCopy := res.mapResultSets(filter)
+	resCopy.Score = resCopy.GetSummary().GetScore()
+	return resCopy
+}
+
+// GetNamespaceRegressions compares the score of each namespace to its baseline score, returning
+// those which dropped by more than maxRegression points. Namespaces missing from either are skipped.
+func (res AuditData) GetNamespaceRegressions(b Baseline, maxRegression uint) []NamespaceRegression {
+	regressions := []NamespaceRegression{}
+	for namespace, summary := range res.GetSummaryByNamespace() {
+		baselineScore, ok := b.NamespaceScores[namespace]
+		if !ok {
+			continue
+		}
+		score := summary.GetScore()
+		if score+maxRegression < baselineScore {
+			regressions = append(regressions, NamespaceRegression{Namespace: namespace, BaselineScore: baselineScore, Score: score})
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		return regressions[i].Namespace < regressions[j].Namespace
+	})
+	return regressions
+}
+
+// mapResultSets returns a copy of the audit with every ResultSet replaced by the output of fn
+func (res AuditData) mapResultSets(fn func(result Result, container string, rs ResultSet) ResultSet) AuditData {
+	resCopy := res
+	resCopy.Results = make([]Result, len(res.Results))
+	for idx, result := range res.Results {
+		result.Results = fn(result, "", result.Results)
+		if result.PodResult != nil {
+			podCopy := *result.PodResult
+			podCopy.Results = fn(result, "", podCopy.Results)
+			podCopy.ContainerResults = make([]ContainerResult, len(result.PodResult.ContainerResults))
+			for cIdx, cr := range result.PodResult.ContainerResults {
+				cr.Results = fn(result, cr.Name, cr.Results)
+				podCopy.ContainerResults[cIdx] = cr
+			}
+			result.PodResult = &podCopy
+		}
+		resCopy.Results[idx] = result
+	}
+	return resCopy
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	audit := getOutputTestAudit()
+	baseline := NewBaselineFromAudit(audit)
+	assert.Equal(t, 4, len(baseline.Entries))
+
+	fileName := filepath.Join(t.TempDir(), "baseline.json")
+	assert.NoError(t, baseline.WriteToFile(fileName))
+	baseline, err := ReadBaselineFromFile(fileName)
+	assert.NoError(t, err)
+
+	filtered := audit.ApplyBaseline(baseline)
+	assert.Equal(t, uint(0), filtered.GetSummary().Dangers)
+	assert.Equal(t, uint(0), filtered.GetSummary().Warnings)
+	assert.Equal(t, uint(100), filtered.Score)
+	assert.Equal(t, uint(2), audit.GetSummary().Dangers, "original audit should not be modified")
+
+	// A new failure in another container is still reported
+	audit.Results[0].PodResult.ContainerResults = append(audit.Results[0].PodResult.ContainerResults, ContainerResult{
+		Name: "sidecar",
+		Results: ResultSet{
+			"cpuLimitsMissing": {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning},
+		},
+	})
+	filtered = audit.ApplyBaseline(baseline)
+	assert.Equal(t, uint(1), filtered.GetSummary().Warnings)
+}
+
+func TestGetNamespaceRegressions(t *testing.T) {
+	audit := getOutputTestAudit()
+	baseline := NewBaselineFromAudit(audit)
+	assert.Equal(t, map[string]uint{"payments": audit.Results[0].GetSummary().GetScore(), "": 100}, baseline.NamespaceScores)
+	assert.Empty(t, audit.GetNamespaceRegressions(baseline, 0))
+
+	baseline.NamespaceScores["payments"] = 90
+	baseline.NamespaceScores["retired"] = 100
+	regressions := audit.GetNamespaceRegressions(baseline, 5)
+	assert.Equal(t, []NamespaceRegression{{Namespace: "payments", BaselineScore: 90, Score: audit.Results[0].GetSummary().GetScore()}}, regressions)
+	assert.Empty(t, audit.GetNamespaceRegressions(baseline, 90))
+}
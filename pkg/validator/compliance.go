@@ -0,0 +1,134 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+const (
+	// ControlPassing is the status of a control whose checks all passed
+	ControlPassing = "PASS"
+	// ControlFailing is the status of a control with at least one failed check
+	ControlFailing = "FAIL"
+	// ControlNotEvaluated is the status of a control whose checks didn't apply to any resources
+	ControlNotEvaluated = "NOT EVALUATED"
+	// ControlNotCovered is the status of a control that no enabled check maps to
+	ControlNotCovered = "NOT COVERED"
+)
+
+// ControlResult summarizes the findings for a single compliance control
+type ControlResult struct {
+	ID       string
+	Title    string
+	Status   string
+	Checks   []string
+	Passed   uint
+	Failed   uint
+	Findings []BaselineEntry `json:",omitempty"`
+}
+
+// ComplianceReport groups audit findings by the controls of a compliance framework
+type ComplianceReport struct {
+	Framework       string
+	Name            string
+	TotalControls   int
+	CoveredControls int
+	PassingControls int
+	Controls        []ControlResult
+}
+
+// GetComplianceReport groups findings by the controls of the framework, using controlChecks
+// to map each control to the checks which cover it. Controls are kept in framework order.
+func (res AuditData) GetComplianceReport(framework config.Framework, controlChecks map[string][]string) ComplianceReport {
+	passed := map[string]uint{}
+	findings := map[string][]BaselineEntry{}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs.GetSortedResults() {
+			if msg.Success {
+				passed[msg.ID]++
+				continue
+			}
+			findings[msg.ID] = append(findings[msg.ID], BaselineEntry{
+				Namespace: result.Namespace,
+				Kind:      result.Kind,
+				Name:      result.Name,
+				Container: container,
+				Check:     msg.ID,
+			})
+		}
+		return rs
+	})
+
+	report := ComplianceReport{
+		Framework:     framework.ID,
+		Name:          framework.Name,
+		TotalControls: len(framework.Controls),
+		Controls:      []ControlResult{},
+	}
+	for _, control := range framework.Controls {
+		controlResult := ControlResult{
+			ID:     control.ID,
+			Title:  control.Title,
+			Checks: controlChecks[control.ID],
+		}
+		for _, checkID := range controlResult.Checks {
+			controlResult.Passed += passed[checkID]
+			controlResult.Failed += uint(len(findings[checkID]))
+			controlResult.Findings = append(controlResult.Findings, findings[checkID]...)
+		}
+		if len(controlResult.Checks) == 0 {
+			controlResult.Status = ControlNotCovered
+		} else {
+			report.CoveredControls++
+			if controlResult.Failed > 0 {
+				controlResult.Status = ControlFailing
+			} else if controlResult.Passed > 0 {
+				controlResult.Status = ControlPassing
+				report.PassingControls++
+			} else {
+				controlResult.Status = ControlNotEvaluated
+			}
+		}
+		if controlResult.Checks == nil {
+			controlResult.Checks = []string{}
+		}
+		report.Controls = append(report.Controls, controlResult)
+	}
+	return report
+}
+
+// GetPrettyOutput returns a human-readable table of the report, listing the findings for each failing control
+func (r ComplianceReport) GetPrettyOutput() string {
+	width := len("CONTROL")
+	for _, control := range r.Controls {
+		if len(control.ID) > width {
+			width = len(control.ID)
+		}
+	}
+	format := fmt.Sprintf("%%-%ds  %%-13v  %%6v  %%6v  %%v\n", width)
+	str := r.Name + "\n"
+	str += fmt.Sprintf(format, "CONTROL", "STATUS", "PASSED", "FAILED", "TITLE")
+	for _, control := range r.Controls {
+		str += fmt.Sprintf(format, control.ID, control.Status, control.Passed, control.Failed, control.Title)
+		for _, finding := range control.Findings {
+			str += "    " + finding.String() + "\n"
+		}
+	}
+	str += fmt.Sprintf("Coverage: %d of %d controls covered by checks, %d passing\n", r.CoveredControls, r.TotalControls, r.PassingControls)
+	return str
+}
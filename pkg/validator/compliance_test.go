@@ -0,0 +1,71 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetComplianceReport(t *testing.T) {
+	framework := config.Framework{
+		ID:   "test",
+		Name: "Test Framework",
+		Controls: []config.Control{
+			{ID: "1.1", Title: "Limit host access"},
+			{ID: "1.2", Title: "Limit RBAC"},
+			{ID: "1.3", Title: "Set resource limits"},
+			{ID: "1.4", Title: "Avoid the default namespace"},
+			{ID: "1.5", Title: "Set probes"},
+		},
+	}
+	controlChecks := map[string][]string{
+		"1.1": {"hostIPCSet"},
+		"1.2": {"clusterrolePodExecAttach"},
+		"1.3": {"cpuLimitsMissing", "memoryLimitsMissing"},
+		"1.5": {"livenessProbeMissing"},
+	}
+	report := getOutputTestAudit().GetComplianceReport(framework, controlChecks)
+	assert.Equal(t, "test", report.Framework)
+	assert.Equal(t, 5, report.TotalControls)
+	assert.Equal(t, 4, report.CoveredControls)
+	assert.Equal(t, 1, report.PassingControls)
+	assert.Len(t, report.Controls, 5)
+
+	assert.Equal(t, ControlFailing, report.Controls[0].Status)
+	assert.Equal(t, uint(1), report.Controls[0].Failed)
+	assert.Equal(t, "payments/Deployment/api: hostIPCSet", report.Controls[0].Findings[0].String())
+
+	assert.Equal(t, ControlPassing, report.Controls[1].Status)
+	assert.Equal(t, uint(1), report.Controls[1].Passed)
+	assert.Empty(t, report.Controls[1].Findings)
+
+	assert.Equal(t, ControlFailing, report.Controls[2].Status)
+	assert.Equal(t, uint(2), report.Controls[2].Failed)
+
+	assert.Equal(t, ControlNotCovered, report.Controls[3].Status)
+	assert.Equal(t, []string{}, report.Controls[3].Checks)
+	assert.Equal(t, ControlNotEvaluated, report.Controls[4].Status)
+
+	pretty := report.GetPrettyOutput()
+	lines := strings.Split(strings.TrimSpace(pretty), "\n")
+	assert.Equal(t, "Test Framework", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "CONTROL"))
+	assert.Contains(t, pretty, "    payments/Deployment/api container api: cpuLimitsMissing\n")
+	assert.Equal(t, "Coverage: 4 of 5 controls covered by checks, 1 passing", lines[len(lines)-1])
+}
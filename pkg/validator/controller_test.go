@@ -60,6 +60,33 @@ func TestValidateController(t *testing.T) {
 	assert.EqualValues(t, expectedResults, actualResult.PodResult.Results)
 }
 
+func TestCheckKinds(t *testing.T) {
+	c, err := conf.Parse([]byte(`
+checks:
+  hostIPCSet:
+    severity: danger
+    kinds:
+    - Deployment
+  hostPIDSet: danger
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, conf.SeverityDanger, c.Checks["hostIPCSet"])
+
+	controller, err := kube.NewGenericResourceFromPod(test.MockPod(), nil)
+	assert.NoError(t, err)
+	controller.Kind = "Deployment"
+	result, err := applyControllerSchemaChecks(&c, nil, controller)
+	assert.NoError(t, err)
+	assert.Contains(t, result.PodResult.Results, "hostIPCSet")
+	assert.Contains(t, result.PodResult.Results, "hostPIDSet")
+
+	controller.Kind = "DaemonSet"
+	result, err = applyControllerSchemaChecks(&c, nil, controller)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.Results, "hostIPCSet")
+	assert.Contains(t, result.PodResult.Results, "hostPIDSet")
+}
+
 func TestControllerLevelChecks(t *testing.T) {
 	testResources := func(res *kube.ResourceProvider) {
 		c := conf.Configuration{
@@ -250,3 +277,52 @@ func TestControllerExemptions(t *testing.T) {
 	assert.Equal(t, "Deployment", actualResults[0].Kind)
 	assert.EqualValues(t, expectedSum, actualResults[0].GetSummary())
 }
+
+func TestVolumeClaimTemplateChecks(t *testing.T) {
+	c := conf.Configuration{
+		Checks: map[string]conf.Severity{
+			"volumeClaimStorageClassMissing": conf.SeverityWarning,
+			"volumeClaimAccessModeRisky":     conf.SeverityWarning,
+		},
+	}
+	statefulSet, err := kube.NewGenericResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  template:
+    spec:
+      containers:
+      - name: db
+        image: postgres:15
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      storageClassName: fast
+      accessModes: [ReadWriteOnce]
+  - metadata:
+      name: shared
+    spec:
+      accessModes: [ReadWriteMany]
+`))
+	assert.NoError(t, err)
+
+	actualResult, err := applyControllerSchemaChecks(&c, nil, statefulSet)
+	assert.NoError(t, err)
+	storageClass := actualResult.Results["volumeClaimStorageClassMissing"]
+	assert.False(t, storageClass.Success)
+	assert.Equal(t, conf.SeverityWarning, storageClass.Severity)
+	assert.Equal(t, "Storage class should be set for volume claim templates: shared", storageClass.Message)
+	accessMode := actualResult.Results["volumeClaimAccessModeRisky"]
+	assert.False(t, accessMode.Success)
+	assert.Equal(t, "Volume claim templates should not use ReadWriteMany, since each replica gets its own claim: shared", accessMode.Message)
+
+	deployment, err := kube.NewGenericResourceFromPod(test.MockPod(), nil)
+	assert.NoError(t, err)
+	deployment.Kind = "Deployment"
+	actualResult, err = applyControllerSchemaChecks(&c, nil, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, actualResult.Results, "volumeClaimStorageClassMissing", "only applies to StatefulSets")
+}
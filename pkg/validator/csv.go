@@ -0,0 +1,52 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+)
+
+// CSVContentType is the content type of CSV output
+const CSVContentType = "text/csv"
+
+// csvHeader are the columns of CSV output
+var csvHeader = []string{"namespace", "kind", "name", "checkID", "severity", "success", "message"}
+
+// GetCSVOutput returns the audit as CSV with a header row and one row per check result, e.g. to
+// review in a spreadsheet. Rows are in the order of the results, with each result's checks sorted by ID.
+func (res AuditData) GetCSVOutput() ([]byte, error) {
+	rows := [][]string{csvHeader}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		checkIDs := make([]string, 0, len(rs))
+		for checkID := range rs {
+			checkIDs = append(checkIDs, checkID)
+		}
+		sort.Strings(checkIDs)
+		for _, checkID := range checkIDs {
+			msg := rs[checkID]
+			rows = append(rows, []string{result.Namespace, result.Kind, result.Name, msg.ID, string(msg.Severity), strconv.FormatBool(msg.Success), msg.Message})
+		}
+		return rs
+	})
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
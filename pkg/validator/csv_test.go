@@ -0,0 +1,56 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCSVOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	msg := audit.Results[0].PodResult.Results["hostIPCSet"]
+	msg.Message = "Host IPC should not be configured, \"hostIPC: true\" is set"
+	audit.Results[0].PodResult.Results["hostIPCSet"] = msg
+
+	expected := `namespace,kind,name,checkID,severity,success,message
+payments,Deployment,api,deploymentMissingReplicas,warning,true,
+payments,Deployment,api,hostIPCSet,danger,false,"Host IPC should not be configured, ""hostIPC: true"" is set"
+payments,Deployment,api,cpuLimitsMissing,warning,false,
+payments,Deployment,api,memoryLimitsMissing,warning,false,
+payments,Deployment,api,readinessProbeMissing,danger,false,
+,ClusterRole,viewer,clusterrolePodExecAttach,danger,true,
+`
+	output, err := audit.GetCSVOutput()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+
+	rows, err := csv.NewReader(bytes.NewReader(output)).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 7)
+	assert.Equal(t, msg.Message, rows[2][6])
+
+	output, err = audit.RemoveSuccessfulResults().GetCSVOutput()
+	assert.NoError(t, err)
+	rows, err = csv.NewReader(bytes.NewReader(output)).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 5, "successful rows should be removed")
+	for _, row := range rows[1:] {
+		assert.Equal(t, "false", row[5])
+	}
+}
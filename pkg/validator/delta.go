@@ -0,0 +1,129 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// GateExitCodeNewDangers is the exit code used when an audit has danger-level failures the earlier audit didn't
+const GateExitCodeNewDangers = 7
+
+// DeltaFailure is a check which fails in the current audit but didn't in the earlier one
+type DeltaFailure struct {
+	BaselineEntry
+	Severity config.Severity
+	Message  string
+}
+
+// AuditDelta describes how an audit changed since an earlier one. Findings are matched by namespace,
+// kind, name, container and check, so a renamed resource's findings are removed and added rather than matched.
+type AuditDelta struct {
+	PreviousScore uint
+	Score         uint
+	// NewFailures fail now but didn't fail in the earlier audit, including on resources it didn't have
+	NewFailures []DeltaFailure
+	// NewPasses failed in the earlier audit and pass now
+	NewPasses []BaselineEntry
+	// Removed failed in the earlier audit, on resources or checks which aren't in the current audit
+	Removed []BaselineEntry
+}
+
+// GetDelta compares the audit against an earlier one
+func (res AuditData) GetDelta(previous AuditData) AuditDelta {
+	delta := AuditDelta{
+		PreviousScore: previous.GetSummary().GetScore(),
+		Score:         res.GetSummary().GetScore(),
+		NewFailures:   []DeltaFailure{},
+		NewPasses:     []BaselineEntry{},
+		Removed:       []BaselineEntry{},
+	}
+	previousFailures := getResultMessagesByEntry(previous, false)
+	currentPasses := getResultMessagesByEntry(res, true)
+	currentFailures := getResultMessagesByEntry(res, false)
+	for key, failure := range currentFailures {
+		if _, ok := previousFailures[key]; !ok {
+			delta.NewFailures = append(delta.NewFailures, DeltaFailure{BaselineEntry: failure.entry, Severity: failure.msg.Severity, Message: failure.msg.Message})
+		}
+	}
+	for key, failure := range previousFailures {
+		if _, ok := currentFailures[key]; ok {
+			continue
+		}
+		if _, ok := currentPasses[key]; ok {
+			delta.NewPasses = append(delta.NewPasses, failure.entry)
+		} else {
+			delta.Removed = append(delta.Removed, failure.entry)
+		}
+	}
+	sort.Slice(delta.NewFailures, func(i, j int) bool {
+		return delta.NewFailures[i].key() < delta.NewFailures[j].key()
+	})
+	sortEntries(delta.NewPasses)
+	sortEntries(delta.Removed)
+	return delta
+}
+
+// NewDangers returns the number of new failures with danger severity
+func (delta AuditDelta) NewDangers() int {
+	count := 0
+	for _, failure := range delta.NewFailures {
+		if failure.Severity == config.SeverityDanger {
+			count++
+		}
+	}
+	return count
+}
+
+// GetPrettyOutput returns a human-readable summary of the delta
+func (delta AuditDelta) GetPrettyOutput() string {
+	str := fmt.Sprintf("Score: %d -> %d (%+d)\n", delta.PreviousScore, delta.Score, int(delta.Score)-int(delta.PreviousScore))
+	for _, failure := range delta.NewFailures {
+		str += fmt.Sprintf("+ %s (%s) - %s\n", failure.String(), failure.Severity, failure.Message)
+	}
+	for _, entry := range delta.NewPasses {
+		str += "- " + entry.String() + "\n"
+	}
+	for _, entry := range delta.Removed {
+		str += "- " + entry.String() + " (no longer audited)\n"
+	}
+	if len(delta.NewFailures) == 0 && len(delta.NewPasses) == 0 && len(delta.Removed) == 0 {
+		str += "No checks changed\n"
+	}
+	return str
+}
+
+type entryMessage struct {
+	entry BaselineEntry
+	msg   ResultMessage
+}
+
+// getResultMessagesByEntry returns the passing or failing checks of the audit, by the key of their entry
+func getResultMessagesByEntry(auditData AuditData, success bool) map[string]entryMessage {
+	messages := map[string]entryMessage{}
+	auditData.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs {
+			if msg.Success == success {
+				entry := BaselineEntry{Namespace: result.Namespace, Kind: result.Kind, Name: result.Name, Container: container, Check: msg.ID}
+				messages[entry.key()] = entryMessage{entry: entry, msg: msg}
+			}
+		}
+		return rs
+	})
+	return messages
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestGetDelta(t *testing.T) {
+	previous := getOutputTestAudit()
+	current := getOutputTestAudit()
+	delta := current.GetDelta(previous)
+	assert.Empty(t, delta.NewFailures)
+	assert.Empty(t, delta.NewPasses)
+	assert.Empty(t, delta.Removed)
+	assert.Equal(t, delta.PreviousScore, delta.Score)
+	assert.Equal(t, 0, delta.NewDangers())
+
+	// fix the CPU limits, and rename the ClusterRole
+	current.Results[0].PodResult.ContainerResults[0].Results["cpuLimitsMissing"] = ResultMessage{ID: "cpuLimitsMissing", Success: true, Severity: config.SeverityWarning}
+	current.Results[1].Name = "reader"
+	current.Results[1].Results = ResultSet{
+		"clusterrolePodExecAttach": {ID: "clusterrolePodExecAttach", Success: false, Severity: config.SeverityDanger, Message: "Allows exec"},
+	}
+	previous.Results[1].Results = ResultSet{
+		"clusterrolePodExecAttach": {ID: "clusterrolePodExecAttach", Success: false, Severity: config.SeverityDanger},
+	}
+	delta = current.GetDelta(previous)
+	assert.Equal(t, []DeltaFailure{{
+		BaselineEntry: BaselineEntry{Kind: "ClusterRole", Name: "reader", Check: "clusterrolePodExecAttach"},
+		Severity:      config.SeverityDanger,
+		Message:       "Allows exec",
+	}}, delta.NewFailures)
+	assert.Equal(t, []BaselineEntry{{Namespace: "payments", Kind: "Deployment", Name: "api", Container: "api", Check: "cpuLimitsMissing"}}, delta.NewPasses)
+	assert.Equal(t, []BaselineEntry{{Kind: "ClusterRole", Name: "viewer", Check: "clusterrolePodExecAttach"}}, delta.Removed)
+	assert.Equal(t, 1, delta.NewDangers())
+
+	expected := "Score: 20 -> 36 (+16)\n" +
+		"+ ClusterRole/reader: clusterrolePodExecAttach (danger) - Allows exec\n" +
+		"- payments/Deployment/api container api: cpuLimitsMissing\n" +
+		"- ClusterRole/viewer: clusterrolePodExecAttach (no longer audited)\n"
+	assert.Equal(t, expected, delta.GetPrettyOutput())
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const deprecatedAPICheckID = "deprecatedAPIVersion"
+
+// applyDeprecatedAPICheck checks that a resource loaded from files doesn't use an API version removed in the
+// target Kubernetes version. The API server returns resources in its preferred version, so clusters are skipped.
+func applyDeprecatedAPICheck(conf *config.Configuration, resourceProvider *kube.ResourceProvider, resource kube.GenericResource) *ResultMessage {
+	policy := conf.DeprecatedAPIPolicy
+	if policy.TargetVersion == "" || resourceProvider == nil || resourceProvider.SourceType != "Path" {
+		return nil
+	}
+	severity, ok := getPolicyCheckSeverity(conf, deprecatedAPICheckID, resource, "")
+	if !ok {
+		return nil
+	}
+	apiVersion := resource.Resource.GetAPIVersion()
+	removed := policy.GetRemovedAPI(apiVersion, resource.Kind)
+	result := ResultMessage{
+		ID:       deprecatedAPICheckID,
+		Severity: severity,
+		Category: "Reliability",
+		Priority: getResultPriority(conf, deprecatedAPICheckID),
+		Success:  removed == nil,
+	}
+	if result.Success {
+		result.Message = fmt.Sprintf("API version %s is available in Kubernetes %s", apiVersion, policy.TargetVersion)
+	} else if removed.Replacement == "" {
+		result.Message = fmt.Sprintf("%s %s was removed in Kubernetes %s without a replacement", apiVersion, resource.Kind, removed.RemovedIn)
+	} else {
+		result.Message = fmt.Sprintf("%s %s was removed in Kubernetes %s, use %s instead", apiVersion, resource.Kind, removed.RemovedIn, removed.Replacement)
+	}
+	return &result
+}
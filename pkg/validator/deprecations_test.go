@@ -0,0 +1,113 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var deprecationsTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+---
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: api
+  namespace: web
+---
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: restricted
+`
+
+func TestDeprecatedAPIs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(deprecationsTestYaml), 0644))
+	provider, err := kube.CreateResourceProviderFromPath(path)
+	assert.NoError(t, err)
+	deployment := provider.Resources["apps/Deployment"][0]
+	ingress := provider.Resources["networking.k8s.io/Ingress"][0]
+	psp := provider.Resources["policy/PodSecurityPolicy"][0]
+
+	c := conf.Configuration{
+		Checks:              map[string]conf.Severity{},
+		DeprecatedAPIPolicy: conf.DeprecatedAPIPolicy{TargetVersion: "1.25"},
+	}
+	result, err := applyNonControllerSchemaChecks(&c, provider, ingress)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, deprecatedAPICheckID, "should be off until it's given a severity")
+
+	c.Checks[deprecatedAPICheckID] = conf.SeverityDanger
+	c.DeprecatedAPIPolicy = conf.DeprecatedAPIPolicy{}
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingress)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, deprecatedAPICheckID, "should be off without a target version")
+
+	c.DeprecatedAPIPolicy = conf.DeprecatedAPIPolicy{TargetVersion: "1.21"}
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingress)
+	assert.NoError(t, err)
+	assert.True(t, result.Results[deprecatedAPICheckID].Success, "Ingress v1beta1 was removed in 1.22")
+
+	c.DeprecatedAPIPolicy = conf.DeprecatedAPIPolicy{TargetVersion: "v1.25.3"}
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingress)
+	assert.NoError(t, err)
+	deprecated := result.Results[deprecatedAPICheckID]
+	assert.False(t, deprecated.Success)
+	assert.Equal(t, conf.SeverityDanger, deprecated.Severity)
+	assert.Equal(t, "networking.k8s.io/v1beta1 Ingress was removed in Kubernetes 1.22, use networking.k8s.io/v1 instead", deprecated.Message)
+
+	result, err = applyNonControllerSchemaChecks(&c, provider, psp)
+	assert.NoError(t, err)
+	assert.Equal(t, "policy/v1beta1 PodSecurityPolicy was removed in Kubernetes 1.25 without a replacement", result.Results[deprecatedAPICheckID].Message)
+
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.True(t, result.Results[deprecatedAPICheckID].Success)
+	assert.Equal(t, "API version apps/v1 is available in Kubernetes v1.25.3", result.Results[deprecatedAPICheckID].Message)
+
+	c.Exemptions = []conf.Exemption{{Rules: []string{deprecatedAPICheckID}, ControllerNames: []string{"api"}}}
+	result, err = applyNonControllerSchemaChecks(&c, provider, ingress)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, deprecatedAPICheckID)
+}
+
+func TestDeprecatedAPIsOnlyApplyToFiles(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(deprecationsTestYaml)
+	c := conf.Configuration{
+		Checks:              map[string]conf.Severity{deprecatedAPICheckID: conf.SeverityDanger},
+		DeprecatedAPIPolicy: conf.DeprecatedAPIPolicy{TargetVersion: "1.25"},
+	}
+	result, err := applyNonControllerSchemaChecks(&c, provider, provider.Resources["networking.k8s.io/Ingress"][0])
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, deprecatedAPICheckID)
+}
@@ -0,0 +1,224 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldChange is a field whose value differs between two audits
+type FieldChange struct {
+	Field  string
+	Before interface{}
+	After  interface{}
+}
+
+// FindingChange is a finding present in both audits with different field values
+type FindingChange struct {
+	BaselineEntry
+	Changes []FieldChange
+}
+
+// AuditDiff describes how the findings changed between two audits
+type AuditDiff struct {
+	Metadata []FieldChange
+	Added    []BaselineEntry
+	Removed  []BaselineEntry
+	Changed  []FindingChange
+}
+
+type finding struct {
+	entry  BaselineEntry
+	fields map[string]interface{}
+}
+
+// DiffAudits compares the failures in two audits. Fields matching ignoreFields, either audit-level
+// paths like AuditTime or ClusterInfo.Pods, or finding-level paths like Message, are removed
+// before comparing, so volatile values don't show up as changes.
+func DiffAudits(before, after AuditData, ignoreFields []string) (AuditDiff, error) {
+	diff := AuditDiff{
+		Metadata: []FieldChange{},
+		Added:    []BaselineEntry{},
+		Removed:  []BaselineEntry{},
+		Changed:  []FindingChange{},
+	}
+	beforeMeta, err := getAuditMetadataFields(before, ignoreFields)
+	if err != nil {
+		return diff, err
+	}
+	afterMeta, err := getAuditMetadataFields(after, ignoreFields)
+	if err != nil {
+		return diff, err
+	}
+	diff.Metadata = diffFields(beforeMeta, afterMeta)
+
+	beforeFindings, err := getFindings(before, ignoreFields)
+	if err != nil {
+		return diff, err
+	}
+	afterFindings, err := getFindings(after, ignoreFields)
+	if err != nil {
+		return diff, err
+	}
+	for key, afterFinding := range afterFindings {
+		beforeFinding, ok := beforeFindings[key]
+		if !ok {
+			diff.Added = append(diff.Added, afterFinding.entry)
+			continue
+		}
+		if changes := diffFields(beforeFinding.fields, afterFinding.fields); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, FindingChange{BaselineEntry: afterFinding.entry, Changes: changes})
+		}
+	}
+	for key, beforeFinding := range beforeFindings {
+		if _, ok := afterFindings[key]; !ok {
+			diff.Removed = append(diff.Removed, beforeFinding.entry)
+		}
+	}
+	sortEntries(diff.Added)
+	sortEntries(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].key() < diff.Changed[j].key()
+	})
+	return diff, nil
+}
+
+// IsEmpty returns true if no findings were added, removed, or changed
+func (diff AuditDiff) IsEmpty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
+
+// GetPrettyOutput returns a human-readable summary of the diff
+func (diff AuditDiff) GetPrettyOutput() string {
+	str := ""
+	for _, change := range diff.Metadata {
+		str += fmt.Sprintf("%s: %v -> %v\n", change.Field, change.Before, change.After)
+	}
+	for _, entry := range diff.Added {
+		str += "+ " + entry.String() + "\n"
+	}
+	for _, entry := range diff.Removed {
+		str += "- " + entry.String() + "\n"
+	}
+	for _, changed := range diff.Changed {
+		str += "~ " + changed.String() + "\n"
+		for _, change := range changed.Changes {
+			str += fmt.Sprintf("    %s: %v -> %v\n", change.Field, change.Before, change.After)
+		}
+	}
+	if str == "" {
+		str = "No differences found\n"
+	}
+	return str
+}
+
+// String returns a human-readable identifier for the entry
+func (e BaselineEntry) String() string {
+	id := e.Kind + "/" + e.Name
+	if e.Namespace != "" {
+		id = e.Namespace + "/" + id
+	}
+	if e.Container != "" {
+		id += " container " + e.Container
+	}
+	return id + ": " + e.Check
+}
+
+func sortEntries(entries []BaselineEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key() < entries[j].key()
+	})
+}
+
+func getAuditMetadataFields(auditData AuditData, ignoreFields []string) (map[string]interface{}, error) {
+	auditData.Results = nil
+	fields, err := flattenFields(auditData, ignoreFields)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, "Results")
+	return fields, nil
+}
+
+func getFindings(auditData AuditData, ignoreFields []string) (map[string]finding, error) {
+	findings := map[string]finding{}
+	var err error
+	auditData.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs {
+			if msg.Success || err != nil {
+				continue
+			}
+			entry := BaselineEntry{Namespace: result.Namespace, Kind: result.Kind, Name: result.Name, Container: container, Check: msg.ID}
+			var fields map[string]interface{}
+			fields, err = flattenFields(msg, ignoreFields)
+			findings[entry.key()] = finding{entry: entry, fields: fields}
+		}
+		return rs
+	})
+	return findings, err
+}
+
+// flattenFields converts obj into a map of dot-separated field paths to values, without the ignored fields
+func flattenFields(obj interface{}, ignoreFields []string) (map[string]interface{}, error) {
+	contents, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	nested := map[string]interface{}{}
+	err = json.Unmarshal(contents, &nested)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	var flatten func(prefix string, value interface{})
+	flatten = func(prefix string, value interface{}) {
+		for _, ignored := range ignoreFields {
+			if prefix == ignored || strings.HasPrefix(prefix, ignored+".") {
+				return
+			}
+		}
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			for k, v := range m {
+				flatten(strings.TrimPrefix(prefix+"."+k, "."), v)
+			}
+			return
+		}
+		fields[prefix] = value
+	}
+	flatten("", nested)
+	return fields, nil
+}
+
+func diffFields(before, after map[string]interface{}) []FieldChange {
+	changes := []FieldChange{}
+	for field, afterValue := range after {
+		if beforeValue := before[field]; !reflect.DeepEqual(beforeValue, afterValue) {
+			changes = append(changes, FieldChange{Field: field, Before: beforeValue, After: afterValue})
+		}
+	}
+	for field, beforeValue := range before {
+		if _, ok := after[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Before: beforeValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestDiffAudits(t *testing.T) {
+	before := getOutputTestAudit()
+	before.AuditTime = "2022-01-01T00:00:00Z"
+	before.Score = 50
+	after := getOutputTestAudit()
+	after.AuditTime = "2022-01-02T00:00:00Z"
+	after.Score = 60
+	containerResults := after.Results[0].PodResult.ContainerResults[0].Results
+	delete(containerResults, "cpuLimitsMissing")
+	containerResults["memoryLimitsMissing"] = ResultMessage{ID: "memoryLimitsMissing", Message: "Memory limits should be set", Severity: config.SeverityWarning}
+	after.Results[1].Results["clusterrolePodExecAttach"] = ResultMessage{ID: "clusterrolePodExecAttach", Severity: config.SeverityDanger}
+
+	diff, err := DiffAudits(before, after, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "AuditTime", Before: "2022-01-01T00:00:00Z", After: "2022-01-02T00:00:00Z"},
+		{Field: "Score", Before: float64(50), After: float64(60)},
+	}, diff.Metadata)
+	assert.Equal(t, []BaselineEntry{{Kind: "ClusterRole", Name: "viewer", Check: "clusterrolePodExecAttach"}}, diff.Added)
+	assert.Equal(t, []BaselineEntry{{Namespace: "payments", Kind: "Deployment", Name: "api", Container: "api", Check: "cpuLimitsMissing"}}, diff.Removed)
+	assert.Len(t, diff.Changed, 1)
+	assert.Equal(t, "memoryLimitsMissing", diff.Changed[0].Check)
+	assert.Equal(t, "Message", diff.Changed[0].Changes[0].Field)
+
+	diff, err = DiffAudits(before, after, []string{"AuditTime", "Score", "Message"})
+	assert.NoError(t, err)
+	assert.Empty(t, diff.Metadata)
+	assert.Empty(t, diff.Changed)
+	assert.Len(t, diff.Added, 1)
+	assert.Len(t, diff.Removed, 1)
+	assert.False(t, diff.IsEmpty())
+
+	diff, err = DiffAudits(before, before, nil)
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+	assert.Equal(t, "No differences found\n", diff.GetPrettyOutput())
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const duplicateResourceCheckID = "duplicateResource"
+
+// getDefinitionLocation returns the file and line a resource was loaded from, or stdin
+func getDefinitionLocation(resource kube.GenericResource) string {
+	location := resource.SourceFile
+	if location == "" {
+		location = "stdin"
+	}
+	if resource.SourceLine > 0 {
+		location += fmt.Sprintf(":%d", resource.SourceLine)
+	}
+	return location
+}
+
+// applyDuplicateResourceCheck checks that a resource loaded from files is only defined once, since applying
+// the files would let a later definition overwrite the earlier ones. Clusters can't have duplicates.
+func applyDuplicateResourceCheck(conf *config.Configuration, resourceProvider *kube.ResourceProvider, resource kube.GenericResource) *ResultMessage {
+	if resourceProvider == nil || resourceProvider.SourceType != "Path" {
+		return nil
+	}
+	severity, ok := getPolicyCheckSeverity(conf, duplicateResourceCheckID, resource, "")
+	if !ok {
+		return nil
+	}
+	definitions := resourceProvider.GetDefinitions(resource)
+	result := ResultMessage{
+		ID:       duplicateResourceCheckID,
+		Severity: severity,
+		Category: "Reliability",
+		Priority: getResultPriority(conf, duplicateResourceCheckID),
+		Success:  len(definitions) <= 1,
+	}
+	if result.Success {
+		result.Message = "Resource is defined once"
+		return &result
+	}
+	for _, definition := range definitions {
+		result.Details = append(result.Details, getDefinitionLocation(definition))
+	}
+	result.Message = fmt.Sprintf("Resource is defined %d times, and the last definition overwrites the others", len(definitions))
+	return &result
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var duplicateTestYaml = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: other
+`
+
+var duplicateOverlayYaml = `apiVersion: v1
+kind: Service
+metadata:
+  name: settings
+  namespace: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: app
+data:
+  key: value
+`
+
+func TestDuplicateResourceCheck(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(duplicateTestYaml), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "overlay.yaml"), []byte(duplicateOverlayYaml), 0644))
+	provider, err := kube.CreateResourceProviderFromPath(dir)
+	assert.NoError(t, err)
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	results, err := ApplyAllSchemaChecksToResourceProvider(&c, provider)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	for _, result := range results {
+		assert.NotContains(t, result.Results, duplicateResourceCheckID, "should be off by default")
+	}
+
+	c.Checks[duplicateResourceCheckID] = conf.SeverityWarning
+	results, err = ApplyAllSchemaChecksToResourceProvider(&c, provider)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	failures := 0
+	for _, result := range results {
+		duplicate := result.Results[duplicateResourceCheckID]
+		if result.Namespace == "app" && result.Kind == "ConfigMap" {
+			failures++
+			assert.False(t, duplicate.Success)
+			assert.Equal(t, conf.SeverityWarning, duplicate.Severity)
+			assert.Equal(t, "Reliability", duplicate.Category)
+			assert.Equal(t, "Resource is defined 2 times, and the last definition overwrites the others", duplicate.Message)
+			assert.Equal(t, []string{filepath.Join(dir, "base.yaml") + ":1", filepath.Join(dir, "overlay.yaml") + ":7"}, duplicate.Details)
+		} else {
+			assert.True(t, duplicate.Success, "%s/%s/%s should only be defined once", result.Namespace, result.Kind, result.Name)
+		}
+	}
+	assert.Equal(t, 2, failures)
+
+	c.Exemptions = []conf.Exemption{{Rules: []string{duplicateResourceCheckID}, Namespace: "app"}}
+	results, err = ApplyAllSchemaChecksToResourceProvider(&c, provider)
+	assert.NoError(t, err)
+	for _, result := range results {
+		if result.Namespace == "app" {
+			assert.NotContains(t, result.Results, duplicateResourceCheckID)
+		}
+	}
+}
+
+func TestDuplicateResourceCheckOnlyAppliesToFiles(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(duplicateOverlayYaml + "---\n" + duplicateTestYaml)
+	c := conf.Configuration{
+		Checks: map[string]conf.Severity{duplicateResourceCheckID: conf.SeverityWarning},
+	}
+	results, err := ApplyAllSchemaChecksToResourceProvider(&c, provider)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	for _, result := range results {
+		assert.NotContains(t, result.Results, duplicateResourceCheckID)
+	}
+}
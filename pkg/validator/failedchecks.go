@@ -0,0 +1,56 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "sort"
+
+// FailedCheck is a check which failed at least once in an audit, with the number of failures
+type FailedCheck struct {
+	ID       string
+	Failures int
+}
+
+// GetFailedChecks returns the distinct checks which failed anywhere in the audit, ordered by the
+// number of failures and then by ID
+func (res AuditData) GetFailedChecks() []FailedCheck {
+	failures := map[string]int{}
+	count := func(rs ResultSet) {
+		for _, msg := range rs {
+			if !msg.Success {
+				failures[msg.ID]++
+			}
+		}
+	}
+	for _, result := range res.Results {
+		count(result.Results)
+		if result.PodResult != nil {
+			count(result.PodResult.Results)
+			for _, cr := range result.PodResult.ContainerResults {
+				count(cr.Results)
+			}
+		}
+	}
+	failedChecks := []FailedCheck{}
+	for id, n := range failures {
+		failedChecks = append(failedChecks, FailedCheck{ID: id, Failures: n})
+	}
+	sort.Slice(failedChecks, func(i, j int) bool {
+		if failedChecks[i].Failures != failedChecks[j].Failures {
+			return failedChecks[i].Failures > failedChecks[j].Failures
+		}
+		return failedChecks[i].ID < failedChecks[j].ID
+	})
+	return failedChecks
+}
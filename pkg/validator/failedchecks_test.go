@@ -0,0 +1,42 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFailedChecks(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results[0].PodResult.ContainerResults = append(audit.Results[0].PodResult.ContainerResults, ContainerResult{
+		Name: "sidecar",
+		Results: ResultSet{
+			"cpuLimitsMissing":    {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning},
+			"memoryLimitsMissing": {ID: "memoryLimitsMissing", Success: true, Severity: config.SeverityWarning},
+		},
+	})
+
+	assert.Equal(t, []FailedCheck{
+		{ID: "cpuLimitsMissing", Failures: 2},
+		{ID: "hostIPCSet", Failures: 1},
+		{ID: "memoryLimitsMissing", Failures: 1},
+		{ID: "readinessProbeMissing", Failures: 1},
+	}, audit.GetFailedChecks())
+
+	assert.Empty(t, AuditData{}.GetFailedChecks())
+}
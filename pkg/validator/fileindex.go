@@ -0,0 +1,73 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"sort"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// FileIndexEntry is a resource defined in an audited file, with the checks it fails
+type FileIndexEntry struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Line      int `json:",omitempty"`
+	Findings  []FileFinding
+}
+
+// FileFinding is a check which a resource in the file index fails
+type FileFinding struct {
+	ID        string
+	Container string `json:",omitempty"`
+	Severity  config.Severity
+	Message   string
+}
+
+// GetFileIndex maps each file the audited resources were loaded from to the resources it defines and
+// their failing checks, e.g. for editors to jump to the right file. Resources are ordered by line, and
+// resources which weren't loaded from a file, e.g. from stdin, aren't indexed.
+func (res AuditData) GetFileIndex() map[string][]FileIndexEntry {
+	index := map[string][]FileIndexEntry{}
+	for _, result := range res.Results {
+		if result.SourceFile == "" {
+			continue
+		}
+		entry := FileIndexEntry{Kind: result.Kind, Name: result.Name, Namespace: result.Namespace, Line: result.SourceLine, Findings: []FileFinding{}}
+		AuditData{Results: []Result{result}}.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+			for _, msg := range rs {
+				if !msg.Success {
+					entry.Findings = append(entry.Findings, FileFinding{ID: msg.ID, Container: container, Severity: msg.Severity, Message: msg.Message})
+				}
+			}
+			return rs
+		})
+		sort.Slice(entry.Findings, func(i, j int) bool {
+			a, b := entry.Findings[i], entry.Findings[j]
+			if a.Container != b.Container {
+				return a.Container < b.Container
+			}
+			return a.ID < b.ID
+		})
+		index[result.SourceFile] = append(index[result.SourceFile], entry)
+	}
+	for _, entries := range index {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Line < entries[j].Line
+		})
+	}
+	return index
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestGetFileIndex(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results[0].SourceFile = "deploy/api.yaml"
+	audit.Results[0].SourceLine = 12
+	audit.Results[0].PodResult.Results["hostIPCSet"] = ResultMessage{ID: "hostIPCSet", Success: false, Severity: config.SeverityDanger, Message: "Host IPC should not be configured"}
+	audit.Results[1].SourceFile = "deploy/rbac.yaml"
+	audit.Results = append(audit.Results, Result{
+		Kind:       "Service",
+		Name:       "api",
+		Namespace:  "payments",
+		SourceFile: "deploy/api.yaml",
+		SourceLine: 1,
+		Results:    ResultSet{},
+	}, Result{
+		Kind:    "ConfigMap",
+		Name:    "from-stdin",
+		Results: ResultSet{},
+	})
+
+	index := audit.GetFileIndex()
+	assert.Len(t, index, 2, "resources from stdin shouldn't be indexed")
+	assert.Equal(t, []FileIndexEntry{
+		{Kind: "Service", Name: "api", Namespace: "payments", Line: 1, Findings: []FileFinding{}},
+		{Kind: "Deployment", Name: "api", Namespace: "payments", Line: 12, Findings: []FileFinding{
+			{ID: "hostIPCSet", Severity: config.SeverityDanger, Message: "Host IPC should not be configured"},
+			{ID: "cpuLimitsMissing", Container: "api", Severity: config.SeverityWarning},
+			{ID: "memoryLimitsMissing", Container: "api", Severity: config.SeverityWarning},
+			{ID: "readinessProbeMissing", Container: "api", Severity: config.SeverityDanger},
+		}},
+	}, index["deploy/api.yaml"])
+	assert.Equal(t, []FileIndexEntry{
+		{Kind: "ClusterRole", Name: "viewer", Findings: []FileFinding{}},
+	}, index["deploy/rbac.yaml"], "passing checks aren't findings")
+}
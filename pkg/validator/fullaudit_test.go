@@ -78,3 +78,26 @@ func TestGetTemplateData(t *testing.T) {
 		assert.Equal(t, found, true)
 	}
 }
+
+func TestRunAuditParallelism(t *testing.T) {
+	c, err := conf.ParseFile("")
+	assert.NoError(t, err)
+	resources, err := kube.CreateResourceProviderFromPath("../kube/test_files/test_1")
+	assert.NoError(t, err)
+
+	c.Parallelism = 1
+	sequential, err := RunAudit(c, resources)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sequential.Results)
+	for idx := 1; idx < len(sequential.Results); idx++ {
+		a, b := sequential.Results[idx-1], sequential.Results[idx]
+		assert.True(t, a.Namespace+"/"+a.Kind+"/"+a.Name <= b.Namespace+"/"+b.Kind+"/"+b.Name, "results should be sorted")
+	}
+
+	c.Parallelism = 8
+	parallel, err := RunAudit(c, resources)
+	assert.NoError(t, err)
+	assert.Equal(t, sequential.Results, parallel.Results)
+	assert.Equal(t, sequential.GetSummary(), parallel.GetSummary())
+	assert.Equal(t, sequential.Score, parallel.Score)
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// Exit codes used when an audit doesn't pass a gate
+const (
+	GateExitCodeDangers  = 3
+	GateExitCodeScore    = 4
+	GateExitCodeWarnings = 5
+)
+
+// GateFailure is a threshold the audit didn't meet, with the exit code it sets
+type GateFailure struct {
+	ExitCode int
+	Reason   string
+}
+
+// EvaluateGate checks the audit against the gate's thresholds. It returns the exit code
+// and reason for the first threshold that isn't met, or 0 and an empty reason if the audit passes.
+func (res AuditData) EvaluateGate(gate config.Gate) (int, string) {
+	failures := res.EvaluateGateFailures(gate)
+	if len(failures) == 0 {
+		return 0, ""
+	}
+	return failures[0].ExitCode, failures[0].Reason
+}
+
+// EvaluateGateFailures checks the audit against every threshold of the gate, returning the
+// ones that aren't met in the order EvaluateGate checks them
+func (res AuditData) EvaluateGateFailures(gate config.Gate) []GateFailure {
+	failures := []GateFailure{}
+	summary := res.GetSummary()
+	if gate.MaxDangers != nil && summary.Dangers > uint(*gate.MaxDangers) {
+		failures = append(failures, GateFailure{GateExitCodeDangers, fmt.Sprintf("%d danger items found in audit, more than the maximum of %d", summary.Dangers, *gate.MaxDangers)})
+	}
+	if gate.MaxWarnings != nil && summary.Warnings > uint(*gate.MaxWarnings) {
+		failures = append(failures, GateFailure{GateExitCodeWarnings, fmt.Sprintf("%d warning items found in audit, more than the maximum of %d", summary.Warnings, *gate.MaxWarnings)})
+	}
+	if score := summary.GetScore(); score < uint(gate.MinScore) {
+		failures = append(failures, GateFailure{GateExitCodeScore, fmt.Sprintf("Audit score of %d is less than the minimum of %d", score, gate.MinScore)})
+	}
+	return failures
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateGate(t *testing.T) {
+	audit := getOutputTestAudit()
+	zero, two, five := 0, 2, 5
+
+	code, reason := audit.EvaluateGate(config.Gate{})
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", reason)
+
+	code, _ = audit.EvaluateGate(config.Gate{MaxDangers: &five, MaxWarnings: &five})
+	assert.Equal(t, 0, code)
+
+	code, reason = audit.EvaluateGate(config.Gate{MaxDangers: &zero})
+	assert.Equal(t, GateExitCodeDangers, code)
+	assert.Equal(t, "2 danger items found in audit, more than the maximum of 0", reason)
+
+	code, _ = audit.EvaluateGate(config.Gate{MaxDangers: &two, MaxWarnings: &zero})
+	assert.Equal(t, GateExitCodeWarnings, code)
+
+	code, _ = audit.EvaluateGate(config.Gate{MaxDangers: &two, MinScore: 90})
+	assert.Equal(t, GateExitCodeScore, code)
+}
+
+func TestEvaluateGateFailures(t *testing.T) {
+	audit := getOutputTestAudit()
+	zero := 0
+
+	assert.Empty(t, audit.EvaluateGateFailures(config.Gate{}))
+
+	failures := audit.EvaluateGateFailures(config.Gate{MaxDangers: &zero, MaxWarnings: &zero, MinScore: 90})
+	assert.Len(t, failures, 3)
+	assert.Equal(t, []int{GateExitCodeDangers, GateExitCodeWarnings, GateExitCodeScore}, []int{failures[0].ExitCode, failures[1].ExitCode, failures[2].ExitCode})
+	assert.Equal(t, "2 danger items found in audit, more than the maximum of 0", failures[0].Reason)
+}
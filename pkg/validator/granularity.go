@@ -0,0 +1,84 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// ScoreGranularity is how container results are counted toward the score
+type ScoreGranularity string
+
+const (
+	// ScoreGranularityContainer counts every container's results, which is the default
+	ScoreGranularityContainer ScoreGranularity = "container"
+	// ScoreGranularityPod counts each container check once per pod, failing if any container fails it
+	ScoreGranularityPod ScoreGranularity = "pod"
+)
+
+// ParseScoreGranularity parses a score granularity, e.g. from a flag
+func ParseScoreGranularity(granularity string) (ScoreGranularity, error) {
+	switch ScoreGranularity(granularity) {
+	case ScoreGranularityContainer, ScoreGranularityPod:
+		return ScoreGranularity(granularity), nil
+	}
+	return "", fmt.Errorf("Invalid score granularity %s, should be one of pod or container", granularity)
+}
+
+// SetScoreGranularity sets how container results are counted, and recalculates the score
+func (res AuditData) SetScoreGranularity(granularity ScoreGranularity) AuditData {
+	resCopy := res
+	resCopy.ScoreGranularity = granularity
+	if granularity == ScoreGranularityContainer {
+		resCopy.ScoreGranularity = ""
+	}
+	resCopy.Score = resCopy.GetSummary().GetScore()
+	return resCopy
+}
+
+// getSummary summarizes a Result, counting container results at the given granularity
+func (c Result) getSummary(granularity ScoreGranularity) CountSummary {
+	if granularity != ScoreGranularityPod || c.PodResult == nil {
+		return c.GetSummary()
+	}
+	summary := c.Results.GetSummary()
+	summary.AddSummary(c.PodResult.Results.GetSummary())
+	worst := ResultSet{}
+	for _, containerResult := range c.PodResult.ContainerResults {
+		for id, msg := range containerResult.Results {
+			if current, ok := worst[id]; !ok || isWorseResult(msg, current) {
+				worst[id] = msg
+			}
+		}
+	}
+	summary.AddSummary(worst.GetSummary())
+	return summary
+}
+
+// isWorseResult returns true if a counts against the score more than b
+func isWorseResult(a, b ResultMessage) bool {
+	rank := func(msg ResultMessage) int {
+		if msg.Success {
+			return 0
+		}
+		if msg.Severity == config.SeverityWarning {
+			return 1
+		}
+		return 2
+	}
+	return rank(a) > rank(b)
+}
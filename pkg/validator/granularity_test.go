@@ -0,0 +1,74 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestParseScoreGranularity(t *testing.T) {
+	granularity, err := ParseScoreGranularity("pod")
+	assert.NoError(t, err)
+	assert.Equal(t, ScoreGranularityPod, granularity)
+
+	_, err = ParseScoreGranularity("namespace")
+	assert.EqualError(t, err, "Invalid score granularity namespace, should be one of pod or container")
+}
+
+func TestSetScoreGranularity(t *testing.T) {
+	auditData := AuditData{
+		Results: []Result{{
+			Name: "api",
+			Kind: "Deployment",
+			PodResult: &PodResult{
+				Results: ResultSet{
+					"hostIPCSet": {ID: "hostIPCSet", Success: true},
+				},
+				ContainerResults: []ContainerResult{{
+					Name: "api",
+					Results: ResultSet{
+						"runAsRootAllowed":   {ID: "runAsRootAllowed", Severity: config.SeverityDanger},
+						"cpuLimitsMissing":   {ID: "cpuLimitsMissing", Severity: config.SeverityWarning},
+						"tagNotSpecified":    {ID: "tagNotSpecified", Success: true},
+						"memoryLimitMissing": {ID: "memoryLimitMissing", Success: true},
+					},
+				}, {
+					Name: "sidecar",
+					Results: ResultSet{
+						"runAsRootAllowed":   {ID: "runAsRootAllowed", Severity: config.SeverityDanger},
+						"cpuLimitsMissing":   {ID: "cpuLimitsMissing", Severity: config.SeverityDanger},
+						"tagNotSpecified":    {ID: "tagNotSpecified", Success: true},
+						"memoryLimitMissing": {ID: "memoryLimitMissing", Severity: config.SeverityWarning},
+					},
+				}},
+			},
+		}},
+	}
+	assert.Equal(t, CountSummary{Successes: 4, Warnings: 2, Dangers: 3}, auditData.GetSummary())
+
+	podData := auditData.SetScoreGranularity(ScoreGranularityPod)
+	assert.Equal(t, ScoreGranularityPod, podData.ScoreGranularity)
+	assert.Equal(t, CountSummary{Successes: 2, Warnings: 1, Dangers: 2}, podData.GetSummary(), "each check should count once with the worst result")
+	assert.Equal(t, podData.GetSummary().GetScore(), podData.Score)
+	assert.Equal(t, CountSummary{Successes: 4, Warnings: 2, Dangers: 3}, podData.Results[0].GetSummary(), "resource summaries should be unchanged")
+
+	containerData := podData.SetScoreGranularity(ScoreGranularityContainer)
+	assert.Equal(t, ScoreGranularity(""), containerData.ScoreGranularity)
+	assert.Equal(t, auditData.GetSummary().GetScore(), containerData.Score)
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResultGroup is a set of resources of the same kind which fail the same checks, e.g. the Deployments of
+// one Helm chart installed many times
+type ResultGroup struct {
+	Kind    string
+	Results []Result
+}
+
+// GroupIdenticalResults groups the results by kind and failing checks, in the order each group first appears
+func (res AuditData) GroupIdenticalResults() []ResultGroup {
+	groups := []ResultGroup{}
+	groupIndexes := map[string]int{}
+	for _, result := range res.Results {
+		key := result.Kind + "|" + strings.Join(result.getFailingCheckKeys(), ",")
+		index, ok := groupIndexes[key]
+		if !ok {
+			index = len(groups)
+			groupIndexes[key] = index
+			groups = append(groups, ResultGroup{Kind: result.Kind})
+		}
+		groups[index].Results = append(groups[index].Results, result)
+	}
+	return groups
+}
+
+// getFailingCheckKeys returns the sorted IDs of the failing checks, qualified by the container they failed in
+func (res Result) getFailingCheckKeys() []string {
+	keys := []string{}
+	add := func(prefix string, rs ResultSet) {
+		for _, msg := range rs {
+			if !msg.Success {
+				keys = append(keys, prefix+msg.ID)
+			}
+		}
+	}
+	add("", res.Results)
+	if res.PodResult != nil {
+		add("", res.PodResult.Results)
+		for _, cr := range res.PodResult.ContainerResults {
+			add(cr.Name+"/", cr.Results)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetGroupedPrettyOutput returns a human-readable string like GetBrandedPrettyOutput, with the resources which
+// fail the same checks collapsed into one entry listing their names
+func (res AuditData) GetGroupedPrettyOutput(useColor bool, branding ReportBranding) string {
+	return res.getPrettyOutput(useColor, branding, true)
+}
+
+// GetPrettyOutput returns a human-readable string, with the results of the first resource in the group
+func (group ResultGroup) GetPrettyOutput() string {
+	if len(group.Results) == 1 {
+		return group.Results[0].GetPrettyOutput()
+	}
+	str := titleColor.Sprint(fmt.Sprintf("%d %s resources with the same failing checks\n", len(group.Results), group.Kind))
+	for _, result := range group.Results {
+		name := result.Name
+		if result.Namespace != "" {
+			name = result.Namespace + "/" + name
+		}
+		str += fmt.Sprintf("    - %s\n", name)
+	}
+	first := group.Results[0]
+	str += first.Results.GetPrettyOutput()
+	if first.PodResult != nil {
+		str += first.PodResult.GetPrettyOutput()
+	}
+	return str
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// getGroupsTestAudit returns the output test audit with two more copies of the api Deployment, and a Deployment
+// which fails different checks
+func getGroupsTestAudit() AuditData {
+	audit := getOutputTestAudit()
+	api := audit.Results[0]
+	worker := api
+	worker.Name = "worker"
+	billing := api
+	billing.Namespace = "billing"
+	web := Result{
+		Kind:      "Deployment",
+		Name:      "web",
+		Namespace: "payments",
+		PodResult: &PodResult{
+			Results: ResultSet{
+				"hostIPCSet": {ID: "hostIPCSet", Success: false, Severity: config.SeverityDanger},
+			},
+		},
+	}
+	audit.Results = append(audit.Results, worker, web, billing)
+	return audit
+}
+
+func TestGroupIdenticalResults(t *testing.T) {
+	groups := getGroupsTestAudit().GroupIdenticalResults()
+	assert.Equal(t, 3, len(groups))
+	assert.Equal(t, "Deployment", groups[0].Kind)
+	names := []string{}
+	for _, result := range groups[0].Results {
+		names = append(names, result.Namespace+"/"+result.Name)
+	}
+	assert.Equal(t, []string{"payments/api", "payments/worker", "billing/api"}, names)
+	assert.Equal(t, "ClusterRole", groups[1].Kind)
+	assert.Equal(t, 1, len(groups[1].Results))
+	assert.Equal(t, "web", groups[2].Results[0].Name)
+
+	// a check failing in a differently named container isn't the same failure
+	audit := getGroupsTestAudit()
+	renamed := *audit.Results[2].PodResult
+	renamed.ContainerResults = []ContainerResult{{Name: "sidecar", Results: audit.Results[0].PodResult.ContainerResults[0].Results}}
+	audit.Results[2].PodResult = &renamed
+	assert.Equal(t, 4, len(audit.GroupIdenticalResults()))
+}
+
+func TestGetGroupedPrettyOutput(t *testing.T) {
+	audit := getGroupsTestAudit()
+	output := audit.GetGroupedPrettyOutput(false, ReportBranding{Title: "Review", Footer: "Footer"})
+	assert.True(t, strings.HasPrefix(output, "Review\n"))
+	assert.True(t, strings.HasSuffix(output, "\nFooter\n"))
+	assert.Contains(t, output, "3 Deployment resources with the same failing checks\n    - payments/api\n    - payments/worker\n    - billing/api\n")
+	assert.Equal(t, 1, strings.Count(output, "cpuLimitsMissing"))
+	assert.Contains(t, output, "ClusterRole viewer\n")
+	assert.Contains(t, output, "Deployment web in namespace payments\n")
+
+	ungrouped := audit.GetBrandedPrettyOutput(false, ReportBranding{})
+	assert.Equal(t, 3, strings.Count(ungrouped, "cpuLimitsMissing"))
+}
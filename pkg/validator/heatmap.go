@@ -0,0 +1,121 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// HeatmapMaxGridNamespaces is the most namespaces the heatmap grid shows, since each is a column
+	HeatmapMaxGridNamespaces = 10
+	// HeatmapMaxGridChecks is the most checks the heatmap grid shows, since each is a row
+	HeatmapMaxGridChecks = 40
+
+	heatmapNoNamespace = "(none)"
+)
+
+// Heatmap counts the failures of each check in each namespace. Counts[i][j] is the number of
+// failures of Checks[j] in Namespaces[i]. Cluster-scoped resources, and others without a namespace, have an empty namespace.
+type Heatmap struct {
+	Namespaces []string
+	Checks     []string
+	Counts     [][]int
+}
+
+// GetHeatmap counts failures by namespace and check. Every namespace with audited resources is
+// included, and only the checks which failed at least once.
+func (res AuditData) GetHeatmap() Heatmap {
+	counts := map[string]map[string]int{}
+	checks := map[string]bool{}
+	for _, result := range res.Results {
+		if _, ok := counts[result.Namespace]; !ok {
+			counts[result.Namespace] = map[string]int{}
+		}
+	}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs {
+			if !msg.Success {
+				counts[result.Namespace][msg.ID]++
+				checks[msg.ID] = true
+			}
+		}
+		return rs
+	})
+
+	heatmap := Heatmap{Namespaces: []string{}, Checks: []string{}, Counts: [][]int{}}
+	for namespace := range counts {
+		heatmap.Namespaces = append(heatmap.Namespaces, namespace)
+	}
+	for checkID := range checks {
+		heatmap.Checks = append(heatmap.Checks, checkID)
+	}
+	sort.Strings(heatmap.Namespaces)
+	sort.Strings(heatmap.Checks)
+	for _, namespace := range heatmap.Namespaces {
+		row := make([]int, len(heatmap.Checks))
+		for idx, checkID := range heatmap.Checks {
+			row[idx] = counts[namespace][checkID]
+		}
+		heatmap.Counts = append(heatmap.Counts, row)
+	}
+	return heatmap
+}
+
+// FitsGrid returns true if the heatmap is small enough to print as a terminal grid
+func (h Heatmap) FitsGrid() bool {
+	return len(h.Namespaces) <= HeatmapMaxGridNamespaces && len(h.Checks) <= HeatmapMaxGridChecks
+}
+
+// GetPrettyOutput returns the heatmap as a grid with a row for each check and a column for each namespace.
+// Cells without failures are shown as a dot.
+func (h Heatmap) GetPrettyOutput() string {
+	if len(h.Checks) == 0 {
+		return "No failures found\n"
+	}
+	checkWidth := len("CHECK")
+	for _, checkID := range h.Checks {
+		if len(checkID) > checkWidth {
+			checkWidth = len(checkID)
+		}
+	}
+	headers := make([]string, len(h.Namespaces))
+	for idx, namespace := range h.Namespaces {
+		headers[idx] = namespace
+		if namespace == "" {
+			headers[idx] = heatmapNoNamespace
+		}
+	}
+
+	str := fmt.Sprintf("%-*s", checkWidth, "CHECK")
+	for _, header := range headers {
+		str += "  " + header
+	}
+	str = strings.TrimRight(str, " ") + "\n"
+	for checkIdx, checkID := range h.Checks {
+		line := fmt.Sprintf("%-*s", checkWidth, checkID)
+		for nsIdx, header := range headers {
+			cell := "."
+			if count := h.Counts[nsIdx][checkIdx]; count > 0 {
+				cell = fmt.Sprintf("%d", count)
+			}
+			line += fmt.Sprintf("  %*s", len(header), cell)
+		}
+		str += line + "\n"
+	}
+	return str
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestGetHeatmap(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results = append(audit.Results, Result{
+		Kind:      "Deployment",
+		Name:      "worker",
+		Namespace: "payments",
+		PodResult: &PodResult{
+			ContainerResults: []ContainerResult{
+				{Name: "worker", Results: ResultSet{"cpuLimitsMissing": {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning}}},
+				{Name: "sidecar", Results: ResultSet{"cpuLimitsMissing": {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning}}},
+			},
+		},
+	}, Result{
+		Kind:      "Deployment",
+		Name:      "web",
+		Namespace: "frontend",
+		Results: ResultSet{
+			"deploymentMissingReplicas": {ID: "deploymentMissingReplicas", Success: true, Severity: config.SeverityWarning},
+		},
+	})
+
+	heatmap := audit.GetHeatmap()
+	assert.Equal(t, []string{"", "frontend", "payments"}, heatmap.Namespaces)
+	assert.Equal(t, []string{"cpuLimitsMissing", "hostIPCSet", "memoryLimitsMissing", "readinessProbeMissing"}, heatmap.Checks)
+	assert.Equal(t, [][]int{
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{3, 1, 1, 1},
+	}, heatmap.Counts)
+	assert.True(t, heatmap.FitsGrid())
+
+	expected := "CHECK                  (none)  frontend  payments\n" +
+		"cpuLimitsMissing            .         .         3\n" +
+		"hostIPCSet                  .         .         1\n" +
+		"memoryLimitsMissing         .         .         1\n" +
+		"readinessProbeMissing       .         .         1\n"
+	assert.Equal(t, expected, heatmap.GetPrettyOutput())
+
+	assert.Equal(t, "No failures found\n", AuditData{}.GetHeatmap().GetPrettyOutput())
+
+	large := Heatmap{Namespaces: make([]string, HeatmapMaxGridNamespaces+1)}
+	assert.False(t, large.FitsGrid())
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thoas/go-funk"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const hostPortCheckID = "hostPortUsed"
+
+// applyHostPortCheck checks that a container doesn't declare host ports, other than those allowed by the policy
+func applyHostPortCheck(conf *config.Configuration, controller kube.GenericResource, container *corev1.Container) *ResultMessage {
+	severity, ok := getPolicyCheckSeverity(conf, hostPortCheckID, controller, container.Name)
+	if !ok {
+		return nil
+	}
+	ports := []string{}
+	for _, port := range container.Ports {
+		if port.HostPort != 0 && !funk.ContainsInt32(conf.HostPortPolicy.AllowedPorts, port.HostPort) {
+			ports = append(ports, fmt.Sprintf("%d", port.HostPort))
+		}
+	}
+	result := ResultMessage{
+		ID:       hostPortCheckID,
+		Severity: severity,
+		Category: "Security",
+		Priority: getResultPriority(conf, hostPortCheckID),
+		Success:  len(ports) == 0,
+	}
+	if result.Success {
+		result.Message = "Host ports are not used"
+		return &result
+	}
+	result.Details = ports
+	noun := "port"
+	if len(ports) > 1 {
+		noun = "ports"
+	}
+	result.Message = fmt.Sprintf("Container uses host %s %s, which bypass service networking", noun, strings.Join(ports, ", "))
+	return &result
+}
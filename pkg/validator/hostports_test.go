@@ -0,0 +1,89 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var hostPortTestYaml = `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+  namespace: monitoring
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: setup
+        image: setup:v1
+      containers:
+      - name: exporter
+        image: exporter:v1
+        ports:
+        - containerPort: 9100
+          hostPort: 9100
+        - containerPort: 8080
+          hostPort: 8080
+        - containerPort: 8443
+          hostPort: 8443
+        - containerPort: 9090
+`
+
+func TestHostPortPolicy(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(hostPortTestYaml)
+	daemonSet := provider.Resources["apps/DaemonSet"][0]
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	result, err := applyControllerSchemaChecks(&c, provider, daemonSet)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[1].Results, hostPortCheckID, "should be off by default")
+
+	c.Checks[hostPortCheckID] = conf.SeverityDanger
+	c.HostPortPolicy = conf.HostPortPolicy{AllowedPorts: []int32{9100}}
+	result, err = applyControllerSchemaChecks(&c, provider, daemonSet)
+	assert.NoError(t, err)
+	assert.Len(t, result.PodResult.ContainerResults, 2)
+	assert.True(t, result.PodResult.ContainerResults[0].Results[hostPortCheckID].Success)
+	hostPort := result.PodResult.ContainerResults[1].Results[hostPortCheckID]
+	assert.False(t, hostPort.Success)
+	assert.Equal(t, conf.SeverityDanger, hostPort.Severity)
+	assert.Equal(t, "Security", hostPort.Category)
+	assert.Equal(t, "Container uses host ports 8080, 8443, which bypass service networking", hostPort.Message)
+	assert.Equal(t, []string{"8080", "8443"}, hostPort.Details)
+
+	c.HostPortPolicy.AllowedPorts = []int32{9100, 8080, 8443}
+	result, err = applyControllerSchemaChecks(&c, provider, daemonSet)
+	assert.NoError(t, err)
+	assert.True(t, result.PodResult.ContainerResults[1].Results[hostPortCheckID].Success)
+
+	c.HostPortPolicy.AllowedPorts = nil
+	c.Exemptions = []conf.Exemption{{Rules: []string{hostPortCheckID}, ContainerNames: []string{"exporter"}}}
+	result, err = applyControllerSchemaChecks(&c, provider, daemonSet)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[1].Results, hostPortCheckID)
+
+	c.Exemptions = nil
+	c.Checks[hostPortCheckID] = conf.SeverityIgnore
+	result, err = applyControllerSchemaChecks(&c, provider, daemonSet)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[1].Results, hostPortCheckID, "should be skipped when ignored in checks")
+}
@@ -0,0 +1,241 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"bytes"
+	"html/template"
+	"math"
+	"sort"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// HTMLContentType is the content type of the html output
+const HTMLContentType = "text/html; charset=utf-8"
+
+// htmlGaugeRadius is the radius of the circle of the score gauge
+const htmlGaugeRadius = 54
+
+// htmlFinding is a failing check listed in the html output
+type htmlFinding struct {
+	Kind      string
+	Name      string
+	Container string
+	ID        string
+	Message   string
+}
+
+// htmlSeverity lists the failing checks of one severity in a namespace
+type htmlSeverity struct {
+	Severity config.Severity
+	Findings []htmlFinding
+}
+
+// htmlNamespace is the section of the html output for one namespace
+type htmlNamespace struct {
+	Namespace  string
+	Summary    CountSummary
+	Score      uint
+	ScoreClass string
+	Severities []htmlSeverity
+}
+
+// htmlReport is the data rendered by htmlTemplate
+type htmlReport struct {
+	Title       string
+	Footer      string
+	AuditTime   string
+	Summary     CountSummary
+	Score       uint
+	ScoreClass  string
+	GaugeRadius int
+	GaugeLength float64
+	GaugeDash   float64
+	ClusterInfo ClusterInfo
+	Namespaces  []htmlNamespace
+	HasFindings bool
+	SourceName  string
+	SourceType  string
+}
+
+// getScoreClass returns the CSS class coloring a score
+func getScoreClass(score uint) string {
+	if score >= 80 {
+		return "good"
+	} else if score >= 60 {
+		return "fair"
+	}
+	return "poor"
+}
+
+// GetHTMLOutput returns a self-contained html page summarizing the audit, with a score gauge and the failing
+// checks grouped by namespace and severity, e.g. to attach to an email. The branding sets its title and footer.
+func (res AuditData) GetHTMLOutput(branding ReportBranding) ([]byte, error) {
+	summary := res.GetSummary()
+	score := summary.GetScore()
+	gaugeLength := 2 * math.Pi * htmlGaugeRadius
+	report := htmlReport{
+		Title:       branding.Title,
+		Footer:      branding.Footer,
+		AuditTime:   res.AuditTime,
+		Summary:     summary,
+		Score:       score,
+		ScoreClass:  getScoreClass(score),
+		GaugeRadius: htmlGaugeRadius,
+		GaugeLength: gaugeLength,
+		GaugeDash:   gaugeLength * float64(score) / 100,
+		ClusterInfo: res.ClusterInfo,
+		SourceName:  res.SourceName,
+		SourceType:  res.SourceType,
+	}
+	if report.Title == "" {
+		report.Title = "Polaris audit of " + res.SourceType + " " + res.SourceName
+	}
+
+	summaries := res.GetSummaryByNamespace()
+	findings := map[string]map[config.Severity][]htmlFinding{}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs {
+			if msg.Success {
+				continue
+			}
+			if findings[result.Namespace] == nil {
+				findings[result.Namespace] = map[config.Severity][]htmlFinding{}
+			}
+			findings[result.Namespace][msg.Severity] = append(findings[result.Namespace][msg.Severity], htmlFinding{
+				Kind:      result.Kind,
+				Name:      result.Name,
+				Container: container,
+				ID:        msg.ID,
+				Message:   msg.Message,
+			})
+		}
+		return rs
+	})
+
+	namespaces := []string{}
+	for namespace := range summaries {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		nsScore := summaries[namespace].GetScore()
+		section := htmlNamespace{Namespace: namespace, Summary: summaries[namespace], Score: nsScore, ScoreClass: getScoreClass(nsScore)}
+		for _, severity := range []config.Severity{config.SeverityDanger, config.SeverityWarning} {
+			severityFindings := findings[namespace][severity]
+			if len(severityFindings) == 0 {
+				continue
+			}
+			sort.Slice(severityFindings, func(i, j int) bool {
+				a, b := severityFindings[i], severityFindings[j]
+				if a.Kind != b.Kind {
+					return a.Kind < b.Kind
+				}
+				if a.Name != b.Name {
+					return a.Name < b.Name
+				}
+				if a.Container != b.Container {
+					return a.Container < b.Container
+				}
+				return a.ID < b.ID
+			})
+			section.Severities = append(section.Severities, htmlSeverity{Severity: severity, Findings: severityFindings})
+			report.HasFindings = true
+		}
+		report.Namespaces = append(report.Namespaces, section)
+	}
+
+	buf := bytes.Buffer{}
+	if err := htmlTemplate.Execute(&buf, report); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292f; margin: 0; background: #f6f8fa; }
+main { max-width: 960px; margin: 0 auto; padding: 24px; }
+header { display: flex; align-items: center; gap: 24px; background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 24px; }
+h1 { font-size: 22px; margin: 0 0 8px; }
+h2 { font-size: 18px; margin: 0; }
+h3 { font-size: 15px; margin: 16px 0 8px; }
+.meta { color: #57606a; font-size: 14px; margin: 4px 0; }
+.gauge text { font-size: 28px; font-weight: 600; }
+.good { color: #1a7f37; stroke: #1a7f37; }
+.fair { color: #9a6700; stroke: #9a6700; }
+.poor { color: #cf222e; stroke: #cf222e; }
+.counts span { display: inline-block; margin-right: 16px; font-size: 14px; }
+section { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 16px 24px; margin-top: 16px; }
+.section-header { display: flex; justify-content: space-between; align-items: baseline; }
+table { width: 100%; border-collapse: collapse; font-size: 14px; }
+th, td { text-align: left; padding: 6px 8px; border-top: 1px solid #d8dee4; vertical-align: top; }
+th { color: #57606a; font-weight: 600; }
+.danger { color: #cf222e; }
+.warning { color: #9a6700; }
+.passed { color: #1a7f37; }
+footer { color: #57606a; font-size: 14px; margin-top: 24px; }
+</style>
+</head>
+<body>
+<main>
+<header>
+<svg class="gauge" width="140" height="140" viewBox="0 0 140 140" role="img" aria-label="Score {{ .Score }}">
+<circle cx="70" cy="70" r="{{ .GaugeRadius }}" fill="none" stroke="#d8dee4" stroke-width="12"/>
+<circle class="{{ .ScoreClass }}" cx="70" cy="70" r="{{ .GaugeRadius }}" fill="none" stroke-width="12" stroke-linecap="round" stroke-dasharray="{{ printf "%.2f" .GaugeDash }} {{ printf "%.2f" .GaugeLength }}" transform="rotate(-90 70 70)"/>
+<text class="{{ .ScoreClass }}" x="70" y="80" text-anchor="middle" fill="currentColor" stroke="none">{{ .Score }}</text>
+</svg>
+<div>
+<h1>{{ .Title }}</h1>
+<p class="meta">Audited {{ .SourceType }} {{ .SourceName }} at {{ .AuditTime }}</p>
+<p class="meta">Nodes: {{ .ClusterInfo.Nodes }} | Namespaces: {{ .ClusterInfo.Namespaces }} | Controllers: {{ .ClusterInfo.Controllers }}</p>
+<p class="counts"><span class="passed">{{ .Summary.Successes }} passing</span><span class="warning">{{ .Summary.Warnings }} warnings</span><span class="danger">{{ .Summary.Dangers }} dangerous</span></p>
+</div>
+</header>
+{{- if not .HasFindings }}
+<section><p>No checks failed.</p></section>
+{{- end }}
+{{- range .Namespaces }}
+{{- if .Severities }}
+<section>
+<div class="section-header">
+<h2>{{ if .Namespace }}Namespace {{ .Namespace }}{{ else }}Resources without a namespace{{ end }}</h2>
+<span class="meta">Score <strong class="{{ .ScoreClass }}">{{ .Score }}</strong> | {{ .Summary.Warnings }} warnings | {{ .Summary.Dangers }} dangerous</span>
+</div>
+{{- range .Severities }}
+<h3 class="{{ .Severity }}">{{ if eq .Severity "danger" }}Danger{{ else }}Warning{{ end }} ({{ len .Findings }})</h3>
+<table>
+<tr><th>Resource</th><th>Container</th><th>Check</th><th>Message</th></tr>
+{{- range .Findings }}
+<tr><td>{{ .Kind }}/{{ .Name }}</td><td>{{ .Container }}</td><td>{{ .ID }}</td><td>{{ .Message }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</section>
+{{- end }}
+{{- end }}
+{{- if .Footer }}
+<footer>{{ .Footer }}</footer>
+{{- end }}
+</main>
+</body>
+</html>
+`))
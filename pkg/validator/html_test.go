@@ -0,0 +1,54 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHTMLOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.SourceType = "Path"
+	audit.SourceName = "./deploy"
+	msg := audit.Results[0].PodResult.Results["hostIPCSet"]
+	msg.Message = "Host IPC should not be <configured>"
+	audit.Results[0].PodResult.Results["hostIPCSet"] = msg
+
+	output, err := audit.GetHTMLOutput(ReportBranding{})
+	assert.NoError(t, err)
+	html := string(output)
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Contains(t, html, "<style>")
+	assert.NotContains(t, html, "<link", "the page should be self-contained")
+	assert.Contains(t, html, "<title>Polaris audit of Path ./deploy</title>")
+	assert.Contains(t, html, `aria-label="Score 40"`)
+	assert.Contains(t, html, "<h2>Namespace payments</h2>")
+	assert.NotContains(t, html, "Resources without a namespace", "namespaces without failures should be left out")
+	assert.Contains(t, html, `<h3 class="danger">Danger (2)</h3>`)
+	assert.Contains(t, html, `<h3 class="warning">Warning (2)</h3>`)
+	assert.Contains(t, html, "<tr><td>Deployment/api</td><td></td><td>hostIPCSet</td><td>Host IPC should not be &lt;configured&gt;</td></tr>")
+	assert.NotContains(t, html, "deploymentMissingReplicas", "passing checks should be left out")
+	assert.Less(t, strings.Index(html, "Danger (2)"), strings.Index(html, "Warning (2)"))
+	assert.NotContains(t, html, "<footer>")
+
+	output, err = audit.GetHTMLOutput(ReportBranding{Title: "Acme Platform Review", Footer: "Questions? Ask #platform"})
+	assert.NoError(t, err)
+	html = string(output)
+	assert.Contains(t, html, "<title>Acme Platform Review</title>")
+	assert.Contains(t, html, "<footer>Questions? Ask #platform</footer>")
+}
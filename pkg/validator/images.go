@@ -0,0 +1,96 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const imageDigestCheckID = "imagePinnedByDigest"
+
+var sha256DigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// imageReference is a parsed container image, e.g. registry.example.com/team/app:1.0@sha256:...
+type imageReference struct {
+	// Name is the fully qualified repository, e.g. docker.io/library/nginx for nginx
+	Name   string
+	Tag    string
+	Digest string
+}
+
+// parseImageReference splits an image into its repository, tag, and digest, qualifying
+// Docker Hub images with their registry the same way the container runtime does
+func parseImageReference(image string) imageReference {
+	ref := imageReference{}
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		ref.Digest = image[idx+1:]
+		image = image[:idx]
+	}
+	// a colon after the last slash separates the tag, rather than a registry port
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		ref.Tag = image[idx+1:]
+		image = image[:idx]
+	}
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		image = "docker.io/library/" + image
+	} else if !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		image = "docker.io/" + image
+	}
+	ref.Name = image
+	return ref
+}
+
+// isImageAllowed returns true if the image matches one of the allowed prefixes, either as written or fully qualified
+func isImageAllowed(image string, ref imageReference, allowed []string) bool {
+	for _, prefix := range allowed {
+		if strings.HasPrefix(image, prefix) || strings.HasPrefix(ref.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyImageDigestCheck checks that a container's image is pinned by a sha256 digest, unless it's allowed by the policy
+func applyImageDigestCheck(conf *config.Configuration, controller kube.GenericResource, container *corev1.Container) *ResultMessage {
+	severity, ok := getPolicyCheckSeverity(conf, imageDigestCheckID, controller, container.Name)
+	if !ok {
+		return nil
+	}
+	ref := parseImageReference(container.Image)
+	if isImageAllowed(container.Image, ref, conf.ImageDigestPolicy.AllowedImages) {
+		return nil
+	}
+	result := ResultMessage{
+		ID:       imageDigestCheckID,
+		Severity: severity,
+		Category: "Security",
+		Priority: getResultPriority(conf, imageDigestCheckID),
+		Success:  sha256DigestPattern.MatchString(ref.Digest),
+	}
+	if result.Success {
+		result.Message = "Image is pinned by digest"
+	} else {
+		result.Message = fmt.Sprintf("Image %s should be pinned by digest, e.g. %s@sha256:...", container.Image, ref.Name)
+	}
+	return &result
+}
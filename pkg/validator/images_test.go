@@ -0,0 +1,87 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var imagesTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: registry.example.com:5000/team/migrate:v1
+      containers:
+      - name: api
+        image: registry.example.com/team/api:v1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef
+      - name: proxy
+        image: nginx:1.25
+      - name: agent
+        image: gcr.io/distroless/static:nonroot
+`
+
+func TestParseImageReference(t *testing.T) {
+	assert.Equal(t, imageReference{Name: "docker.io/library/nginx"}, parseImageReference("nginx"))
+	assert.Equal(t, imageReference{Name: "docker.io/fairwinds/polaris", Tag: "8.0"}, parseImageReference("fairwinds/polaris:8.0"))
+	assert.Equal(t, imageReference{Name: "localhost:5000/app", Tag: "dev"}, parseImageReference("localhost:5000/app:dev"))
+	assert.Equal(t, imageReference{Name: "quay.io/app", Tag: "v1", Digest: "sha256:abc"}, parseImageReference("quay.io/app:v1@sha256:abc"))
+}
+
+func TestImageDigestPolicy(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(imagesTestYaml)
+	deployment := provider.Resources["apps/Deployment"][0]
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	result, err := applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[1].Results, imageDigestCheckID, "should be off by default")
+
+	c.Checks[imageDigestCheckID] = conf.SeverityDanger
+	c.ImageDigestPolicy = conf.ImageDigestPolicy{
+		AllowedImages: []string{"gcr.io/distroless/"},
+	}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+
+	migrate := result.PodResult.ContainerResults[0].Results[imageDigestCheckID]
+	assert.False(t, migrate.Success)
+	assert.Equal(t, "Image registry.example.com:5000/team/migrate:v1 should be pinned by digest, e.g. registry.example.com:5000/team/migrate@sha256:...", migrate.Message)
+
+	api := result.PodResult.ContainerResults[1].Results[imageDigestCheckID]
+	assert.True(t, api.Success)
+
+	proxy := result.PodResult.ContainerResults[2].Results[imageDigestCheckID]
+	assert.False(t, proxy.Success)
+	assert.Equal(t, conf.SeverityDanger, proxy.Severity)
+
+	assert.NotContains(t, result.PodResult.ContainerResults[3].Results, imageDigestCheckID, "allowed images should be skipped")
+
+	c.ImageDigestPolicy.AllowedImages = append(c.ImageDigestPolicy.AllowedImages, "docker.io/library/")
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[2].Results, imageDigestCheckID)
+}
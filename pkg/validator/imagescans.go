@@ -0,0 +1,76 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const imageScanCheckID = "imageScanMissing"
+
+// getImageScanAnnotation returns the scan annotation of the workload, or else of its pod template
+func getImageScanAnnotation(controller kube.GenericResource, annotation string) (string, bool) {
+	if value, ok := controller.ObjectMeta.GetAnnotations()[annotation]; ok {
+		return value, true
+	}
+	podTemplate, ok := controller.PodTemplate.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	podAnnotations, _, _ := unstructured.NestedStringMap(podTemplate, "metadata", "annotations")
+	value, ok := podAnnotations[annotation]
+	return value, ok
+}
+
+// applyImageScanCheck checks that a workload is annotated with the time its images were scanned, and that the
+// scan isn't older than the policy allows
+func applyImageScanCheck(conf *config.Configuration, controller kube.GenericResource, now time.Time) *ResultMessage {
+	severity, ok := getPolicyCheckSeverity(conf, imageScanCheckID, controller, "")
+	if !ok {
+		return nil
+	}
+	annotation := conf.ImageScanPolicy.GetAnnotation()
+	maxAgeDays := conf.ImageScanPolicy.GetMaxAgeDays()
+	result := ResultMessage{
+		ID:       imageScanCheckID,
+		Severity: severity,
+		Category: "Security",
+		Priority: getResultPriority(conf, imageScanCheckID),
+	}
+	value, ok := getImageScanAnnotation(controller, annotation)
+	if !ok {
+		result.Message = fmt.Sprintf("Images have no scan, the %s annotation should be set", annotation)
+		return &result
+	}
+	scannedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		result.Message = fmt.Sprintf("Annotation %s should be an RFC 3339 time, e.g. 2023-01-02T15:04:05Z, not %q", annotation, value)
+		return &result
+	}
+	result.Details = []string{scannedAt.UTC().Format(time.RFC3339)}
+	if age := now.Sub(scannedAt); age > time.Duration(maxAgeDays)*24*time.Hour {
+		result.Message = fmt.Sprintf("Images were last scanned %d days ago, more than the maximum of %d", int(age.Hours()/24), maxAgeDays)
+		return &result
+	}
+	result.Success = true
+	result.Message = fmt.Sprintf("Images were scanned in the last %d days", maxAgeDays)
+	return &result
+}
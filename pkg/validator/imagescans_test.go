@@ -0,0 +1,109 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var imageScanTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  annotations:
+    scanned-at: "2023-03-01T12:00:00Z"
+spec:
+  template:
+    metadata:
+      annotations:
+        security.example.com/scanned: "2023-02-01T12:00:00Z"
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker:v1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: batch
+  annotations:
+    scanned-at: "last week"
+spec:
+  template:
+    spec:
+      containers:
+      - name: batch
+        image: batch:v1
+`
+
+func TestImageScanCheck(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(imageScanTestYaml)
+	deployments := map[string]kube.GenericResource{}
+	for _, deployment := range provider.Resources["apps/Deployment"] {
+		deployments[deployment.ObjectMeta.GetName()] = deployment
+	}
+	now := time.Date(2023, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	assert.Nil(t, applyImageScanCheck(&c, deployments["api"], now), "should be off by default")
+
+	c.Checks[imageScanCheckID] = conf.SeverityWarning
+	result := applyImageScanCheck(&c, deployments["api"], now)
+	assert.True(t, result.Success)
+	assert.Equal(t, conf.SeverityWarning, result.Severity)
+	assert.Equal(t, "Images were scanned in the last 7 days", result.Message)
+	assert.Equal(t, []string{"2023-03-01T12:00:00Z"}, result.Details)
+
+	result = applyImageScanCheck(&c, deployments["worker"], now)
+	assert.False(t, result.Success)
+	assert.Equal(t, "Images have no scan, the scanned-at annotation should be set", result.Message)
+
+	result = applyImageScanCheck(&c, deployments["batch"], now)
+	assert.False(t, result.Success)
+	assert.Equal(t, `Annotation scanned-at should be an RFC 3339 time, e.g. 2023-01-02T15:04:05Z, not "last week"`, result.Message)
+
+	result = applyImageScanCheck(&c, deployments["api"], now.Add(7*24*time.Hour))
+	assert.False(t, result.Success)
+	assert.Equal(t, "Images were last scanned 11 days ago, more than the maximum of 7", result.Message)
+
+	// the annotation can be set on the pod template
+	c.Checks[imageScanCheckID] = conf.SeverityDanger
+	c.ImageScanPolicy = conf.ImageScanPolicy{Annotation: "security.example.com/scanned", MaxAgeDays: 60}
+	result = applyImageScanCheck(&c, deployments["api"], now)
+	assert.True(t, result.Success)
+	assert.Equal(t, conf.SeverityDanger, result.Severity)
+	assert.Equal(t, []string{"2023-02-01T12:00:00Z"}, result.Details)
+
+	c.Exemptions = []conf.Exemption{{Rules: []string{imageScanCheckID}, ControllerNames: []string{"worker"}}}
+	assert.Nil(t, applyImageScanCheck(&c, deployments["worker"], now))
+}
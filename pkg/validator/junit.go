@@ -0,0 +1,104 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+// junitTime is reported for every suite and test case, since checks aren't timed
+const junitTime = "0"
+
+// JUnitTestSuites is a JUnit XML report with one test suite per resource
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite contains the checks run against a single resource
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a single check, with a failure if it didn't pass
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes a failed check. The type is the check's severity.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// GetJUnitOutput returns the audit as a JUnit XML report. Each resource is a test suite named
+// namespace/kind/name, and each check a test case. Failures include the check's remediation
+// from remediations when there is one.
+func (res AuditData) GetJUnitOutput(remediations map[string]string) JUnitTestSuites {
+	report := JUnitTestSuites{Name: "Polaris", Time: junitTime, Suites: []JUnitTestSuite{}}
+	for _, result := range res.Results {
+		suite := JUnitTestSuite{Name: getResourceID(result), Time: junitTime, TestCases: []JUnitTestCase{}}
+		suite.addTestCases("", result.Results, remediations)
+		if result.PodResult != nil {
+			suite.addTestCases("", result.PodResult.Results, remediations)
+			for _, cr := range result.PodResult.ContainerResults {
+				suite.addTestCases(cr.Name, cr.Results, remediations)
+			}
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+	return report
+}
+
+func (suite *JUnitTestSuite) addTestCases(container string, rs ResultSet, remediations map[string]string) {
+	checkIDs := make([]string, 0, len(rs))
+	for checkID := range rs {
+		checkIDs = append(checkIDs, checkID)
+	}
+	sort.Strings(checkIDs)
+	for _, checkID := range checkIDs {
+		msg := rs[checkID]
+		testCase := JUnitTestCase{Name: msg.ID, ClassName: suite.Name, Time: junitTime}
+		if container != "" {
+			// container checks are repeated for every container, so name them after the container too
+			testCase.Name += " (container " + container + ")"
+		}
+		if !msg.Success {
+			text := msg.Message
+			if remediation, ok := remediations[msg.ID]; ok {
+				text += "\nRemediation: " + remediation
+			}
+			testCase.Failure = &JUnitFailure{Message: msg.Message, Type: string(msg.Severity), Text: text}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+}
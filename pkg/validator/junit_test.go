@@ -0,0 +1,69 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJUnitOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	cpuLimits := audit.Results[0].PodResult.ContainerResults[0].Results["cpuLimitsMissing"]
+	cpuLimits.Message = "CPU limits should be set"
+	audit.Results[0].PodResult.ContainerResults[0].Results["cpuLimitsMissing"] = cpuLimits
+
+	report := audit.GetJUnitOutput(map[string]string{"cpuLimitsMissing": "Add resource limits"})
+	assert.Equal(t, 6, report.Tests)
+	assert.Equal(t, 4, report.Failures)
+	assert.Len(t, report.Suites, 2)
+
+	api := report.Suites[0]
+	assert.Equal(t, "payments/Deployment/api", api.Name)
+	assert.Equal(t, 5, api.Tests)
+	assert.Equal(t, 4, api.Failures)
+	names := []string{}
+	for _, testCase := range api.TestCases {
+		names = append(names, testCase.Name)
+		assert.Equal(t, "payments/Deployment/api", testCase.ClassName)
+		assert.Equal(t, "0", testCase.Time)
+	}
+	assert.Equal(t, []string{
+		"deploymentMissingReplicas",
+		"hostIPCSet",
+		"cpuLimitsMissing (container api)",
+		"memoryLimitsMissing (container api)",
+		"readinessProbeMissing (container api)",
+	}, names)
+	assert.Nil(t, api.TestCases[0].Failure)
+	assert.Equal(t, "danger", api.TestCases[1].Failure.Type)
+	assert.Equal(t, &JUnitFailure{
+		Message: "CPU limits should be set",
+		Type:    "warning",
+		Text:    "CPU limits should be set\nRemediation: Add resource limits",
+	}, api.TestCases[2].Failure)
+
+	viewer := report.Suites[1]
+	assert.Equal(t, "ClusterRole/viewer", viewer.Name)
+	assert.Equal(t, 1, viewer.Tests)
+	assert.Equal(t, 0, viewer.Failures)
+
+	output, err := xml.Marshal(report)
+	assert.NoError(t, err)
+	assert.Contains(t, string(output), `<testsuites name="Polaris" tests="6" failures="4" time="0"><testsuite name="payments/Deployment/api" tests="5" failures="4" time="0">`)
+	assert.Contains(t, string(output), `<testcase name="deploymentMissingReplicas" classname="payments/Deployment/api" time="0"></testcase>`)
+}
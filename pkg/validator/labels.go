@@ -0,0 +1,72 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const requiredLabelsCheckID = "requiredLabelsMissing"
+
+// applyRequiredLabelsCheck checks that a resource has every label required for its kind,
+// with values matching the configured patterns
+func applyRequiredLabelsCheck(conf *config.Configuration, resource kube.GenericResource) *ResultMessage {
+	required := conf.RequiredLabels.GetLabelsForKind(resource.Kind)
+	if len(required) == 0 {
+		return nil
+	}
+	severity, ok := getPolicyCheckSeverity(conf, requiredLabelsCheckID, resource, "")
+	if !ok {
+		return nil
+	}
+	labels := resource.ObjectMeta.GetLabels()
+	missing := []string{}
+	invalid := []string{}
+	for _, label := range required {
+		value, ok := labels[label.Key]
+		if !ok {
+			missing = append(missing, label.Key)
+			continue
+		}
+		if !label.MatchesPattern(value) {
+			invalid = append(invalid, fmt.Sprintf("%s=%s", label.Key, value))
+		}
+	}
+	result := ResultMessage{
+		ID:       requiredLabelsCheckID,
+		Severity: severity,
+		Category: "Reliability",
+		Priority: getResultPriority(conf, requiredLabelsCheckID),
+		Success:  len(missing) == 0 && len(invalid) == 0,
+	}
+	if result.Success {
+		result.Message = "All required labels are set"
+		return &result
+	}
+	problems := []string{}
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+		result.Details = append(result.Details, missing...)
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "invalid values for "+strings.Join(invalid, ", "))
+	}
+	result.Message = fmt.Sprintf("Required labels are not set: %s", strings.Join(problems, "; "))
+	return &result
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var labelsTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+  labels:
+    team: platform
+    cost-center: infra
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: web
+  labels:
+    team: platform
+`
+
+func TestRequiredLabels(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(labelsTestYaml)
+	deployment := provider.Resources["apps/Deployment"][0]
+	service := provider.Resources["Service"][0]
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	result, err := applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredLabelsCheckID, "should be off by default")
+
+	c.RequiredLabels = conf.RequiredLabels{
+		Labels: []conf.RequiredLabel{
+			{Key: "team"},
+			{Key: "cost-center", Pattern: "^[0-9]+$", Kinds: []string{"Deployment"}},
+			{Key: "tier", Kinds: []string{"Deployment"}},
+		},
+	}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredLabelsCheckID, "should be off until it's given a severity")
+
+	c.Checks[requiredLabelsCheckID] = conf.SeverityWarning
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	labels := result.Results[requiredLabelsCheckID]
+	assert.False(t, labels.Success)
+	assert.Equal(t, conf.SeverityWarning, labels.Severity)
+	assert.Equal(t, "Required labels are not set: missing tier; invalid values for cost-center=infra", labels.Message)
+	assert.Equal(t, []string{"tier"}, labels.Details)
+
+	result, err = applyNonControllerSchemaChecks(&c, provider, service)
+	assert.NoError(t, err)
+	assert.True(t, result.Results[requiredLabelsCheckID].Success, "labels for other kinds shouldn't apply")
+
+	c.Checks[requiredLabelsCheckID] = conf.SeverityIgnore
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, requiredLabelsCheckID)
+}
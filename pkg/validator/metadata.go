@@ -0,0 +1,66 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadMetadataFromFile reads a JSON object of arbitrary context, e.g. about a CI build, to attach to an audit
+func ReadMetadataFromFile(fileName string) (map[string]interface{}, error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]interface{}{}
+	err = json.Unmarshal(contents, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("parsing metadata %s, should be a JSON object: %w", fileName, err)
+	}
+	return metadata, nil
+}
+
+// ParseMetadataFlags parses metadata in the format key=value
+func ParseMetadataFlags(flags []string) (map[string]interface{}, error) {
+	metadata := map[string]interface{}{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid metadata %s, should be in the format key=value", flag)
+		}
+		metadata[parts[0]] = parts[1]
+	}
+	return metadata, nil
+}
+
+// AddMetadata returns a copy of the audit with the metadata merged in. Keys which are
+// already set are overwritten.
+func (res AuditData) AddMetadata(metadata map[string]interface{}) AuditData {
+	if len(metadata) == 0 {
+		return res
+	}
+	resCopy := res
+	resCopy.Metadata = map[string]interface{}{}
+	for key, value := range res.Metadata {
+		resCopy.Metadata[key] = value
+	}
+	for key, value := range metadata {
+		resCopy.Metadata[key] = value
+	}
+	return resCopy
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "context.json")
+	assert.NoError(t, os.WriteFile(fileName, []byte(`{"build": {"id": 42}, "branch": "main"}`), 0644))
+	fromFile, err := ReadMetadataFromFile(fileName)
+	assert.NoError(t, err)
+
+	fromFlags, err := ParseMetadataFlags([]string{"branch=release", "commit=abc=123"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"branch": "release", "commit": "abc=123"}, fromFlags)
+
+	audit := getOutputTestAudit().AddMetadata(fromFile).AddMetadata(fromFlags)
+	assert.Equal(t, map[string]interface{}{
+		"build":  map[string]interface{}{"id": float64(42)},
+		"branch": "release",
+		"commit": "abc=123",
+	}, audit.Metadata)
+	assert.Equal(t, "main", fromFile["branch"])
+
+	_, err = ParseMetadataFlags([]string{"branch"})
+	assert.EqualError(t, err, "invalid metadata branch, should be in the format key=value")
+
+	assert.NoError(t, os.WriteFile(fileName, []byte(`["not", "an", "object"]`), 0644))
+	_, err = ReadMetadataFromFile(fileName)
+	assert.Error(t, err)
+}
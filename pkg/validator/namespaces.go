@@ -0,0 +1,76 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+)
+
+const namespaceSummaryNoNamespace = "(none)"
+
+// NamespaceSummary provides the score and counts for the resources in a namespace
+type NamespaceSummary struct {
+	Namespace string
+	Score     uint
+	Resources int
+	CountSummary
+}
+
+// GetNamespaceSummaries scores each namespace with audited resources, lowest score first, so the namespaces
+// which bring the overall score down are at the top. Resources without a namespace, such as cluster-scoped ones,
+// are summarized under an empty namespace.
+func (res AuditData) GetNamespaceSummaries() []NamespaceSummary {
+	resources := map[string]int{}
+	for _, result := range res.Results {
+		resources[result.Namespace]++
+	}
+	summaries := []NamespaceSummary{}
+	for namespace, summary := range res.GetSummaryByNamespace() {
+		summaries = append(summaries, NamespaceSummary{
+			Namespace:    namespace,
+			Score:        summary.GetScore(),
+			Resources:    resources[namespace],
+			CountSummary: summary,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Score != summaries[j].Score {
+			return summaries[i].Score < summaries[j].Score
+		}
+		return summaries[i].Namespace < summaries[j].Namespace
+	})
+	return summaries
+}
+
+// GetNamespaceSummaryPrettyOutput returns a human-readable table of namespace summaries
+func GetNamespaceSummaryPrettyOutput(summaries []NamespaceSummary) string {
+	width := len("NAMESPACE")
+	for _, summary := range summaries {
+		if len(summary.Namespace) > width {
+			width = len(summary.Namespace)
+		}
+	}
+	format := fmt.Sprintf("%%-%ds  %%5v  %%9v  %%7v  %%8v\n", width)
+	str := fmt.Sprintf(format, "NAMESPACE", "SCORE", "RESOURCES", "DANGERS", "WARNINGS")
+	for _, summary := range summaries {
+		namespace := summary.Namespace
+		if namespace == "" {
+			namespace = namespaceSummaryNoNamespace
+		}
+		str += fmt.Sprintf(format, namespace, summary.Score, summary.Resources, summary.Dangers, summary.Warnings)
+	}
+	return str
+}
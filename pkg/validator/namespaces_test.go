@@ -0,0 +1,50 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNamespaceSummaries(t *testing.T) {
+	audit := getOutputTestAudit()
+
+	summaries := audit.GetNamespaceSummaries()
+	assert.Len(t, summaries, 2)
+
+	byNamespace := audit.GetSummaryByNamespace()
+	for _, summary := range summaries {
+		assert.Equal(t, byNamespace[summary.Namespace], summary.CountSummary)
+		assert.Equal(t, byNamespace[summary.Namespace].GetScore(), summary.Score)
+		assert.Equal(t, 1, summary.Resources)
+	}
+	assert.Equal(t, "payments", summaries[0].Namespace)
+	assert.Equal(t, "", summaries[1].Namespace)
+	assert.Less(t, summaries[0].Score, summaries[1].Score)
+
+	total := audit.GetSummary()
+	assert.Equal(t, total.Dangers, summaries[0].Dangers+summaries[1].Dangers)
+	assert.Equal(t, total.Warnings, summaries[0].Warnings+summaries[1].Warnings)
+
+	pretty := GetNamespaceSummaryPrettyOutput(summaries)
+	lines := strings.Split(strings.TrimSpace(pretty), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "NAMESPACE"))
+	assert.True(t, strings.HasPrefix(lines[1], "payments"))
+	assert.True(t, strings.HasPrefix(lines[2], "(none)"))
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const namingConventionCheckID = "namingConventionMismatched"
+
+// applyNamingConventionCheck checks that a resource's name matches every naming convention for its kind
+func applyNamingConventionCheck(conf *config.Configuration, resource kube.GenericResource) *ResultMessage {
+	conventions := conf.NamingConventions.GetConventionsForKind(resource.Kind)
+	if len(conventions) == 0 {
+		return nil
+	}
+	severity, ok := getPolicyCheckSeverity(conf, namingConventionCheckID, resource, "")
+	if !ok {
+		return nil
+	}
+	name := resource.ObjectMeta.GetName()
+	violated := []string{}
+	for _, convention := range conventions {
+		if !convention.Matches(name) {
+			violated = append(violated, convention.Pattern)
+		}
+	}
+	result := ResultMessage{
+		ID:       namingConventionCheckID,
+		Severity: severity,
+		Category: "Reliability",
+		Priority: getResultPriority(conf, namingConventionCheckID),
+		Success:  len(violated) == 0,
+	}
+	if result.Success {
+		result.Message = "Name follows the naming conventions"
+		return &result
+	}
+	result.Details = violated
+	noun := "convention"
+	if len(violated) > 1 {
+		noun = "conventions"
+	}
+	result.Message = fmt.Sprintf("Name %s doesn't match the naming %s %s", name, noun, strings.Join(violated, ", "))
+	return &result
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var namingTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: Payments_API
+  namespace: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: payments-api
+  namespace: web
+`
+
+func TestNamingConventions(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(namingTestYaml)
+	deployment := provider.Resources["apps/Deployment"][0]
+	service := provider.Resources["Service"][0]
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	result, err := applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, namingConventionCheckID, "should be off by default")
+
+	c.NamingConventions = conf.NamingConventions{
+		Conventions: []conf.NamingConvention{
+			{Pattern: "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"},
+			{Pattern: "^payments-", Kinds: []string{"Deployment", "Service"}},
+			{Pattern: "^acme-", Kinds: []string{"StatefulSet"}},
+		},
+	}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, namingConventionCheckID, "should be off until it has a severity in checks")
+
+	c.Checks[namingConventionCheckID] = conf.SeverityDanger
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	naming := result.Results[namingConventionCheckID]
+	assert.False(t, naming.Success)
+	assert.Equal(t, conf.SeverityDanger, naming.Severity)
+	assert.Equal(t, "Name Payments_API doesn't match the naming conventions ^[a-z0-9]([-a-z0-9]*[a-z0-9])?$, ^payments-", naming.Message)
+	assert.Equal(t, []string{"^[a-z0-9]([-a-z0-9]*[a-z0-9])?$", "^payments-"}, naming.Details)
+
+	result, err = applyNonControllerSchemaChecks(&c, provider, service)
+	assert.NoError(t, err)
+	assert.True(t, result.Results[namingConventionCheckID].Success)
+
+	c.CheckKinds = map[string][]string{namingConventionCheckID: {"StatefulSet"}}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, namingConventionCheckID, "should only apply to the kinds in checks")
+	c.CheckKinds = nil
+
+	c.Exemptions = []conf.Exemption{{Rules: []string{namingConventionCheckID}, ControllerNames: []string{"Payments"}}}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, namingConventionCheckID)
+
+	c.Exemptions = nil
+	c.Checks[namingConventionCheckID] = conf.SeverityIgnore
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, namingConventionCheckID, "should be skipped when ignored in checks")
+}
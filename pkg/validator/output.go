@@ -15,7 +15,11 @@
 package validator
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"time"
 
@@ -49,8 +53,11 @@ type AuditData struct {
 	SourceName           string
 	DisplayName          string
 	ClusterInfo          ClusterInfo
+	Metadata             map[string]interface{} `json:",omitempty"`
 	Results              []Result
 	Score                uint
+	ScoreGranularity     ScoreGranularity            `json:",omitempty"`
+	FileIndex            map[string][]FileIndexEntry `json:",omitempty"`
 }
 
 // RemoveSuccessfulResults removes all tests that have passed
@@ -89,6 +96,12 @@ type ResultMessage struct {
 	Severity  config.Severity
 	Category  string
 	Mutations []config.Mutation
+	// Priority describes how actionable a failure is, and is empty for the default neutral priority
+	Priority config.Priority `json:",omitempty"`
+	// PromotionReason explains why the severity was raised above the configured one
+	PromotionReason string `json:",omitempty"`
+	// Evidence describes what a passing check inspected and the values it observed, with --verbose-results
+	Evidence string `json:",omitempty"`
 }
 
 // ResultSet contiains the results for a set of checks
@@ -113,9 +126,14 @@ type Result struct {
 	Name        string
 	Namespace   string
 	Kind        string
+	Owner       string `json:",omitempty"`
+	App         string `json:",omitempty"`
 	Results     ResultSet
 	PodResult   *PodResult
 	CreatedTime time.Time
+	// SourceFile and SourceLine locate the resource's YAML when it was audited from a file
+	SourceFile string `json:",omitempty"`
+	SourceLine int    `json:",omitempty"`
 }
 
 func (res Result) removeSuccessfulResults() Result {
@@ -183,15 +201,45 @@ func fillString(id string, l int) string {
 	return id
 }
 
+// ReportBranding customizes the header and footer of pretty output, e.g. to white-label shared reports
+type ReportBranding struct {
+	// Title replaces the "Polaris audited" line at the top of the report
+	Title string
+	// Footer is added after the results
+	Footer string
+}
+
 // GetPrettyOutput returns a human-readable string
 func (res AuditData) GetPrettyOutput(useColor bool) string {
+	return res.GetBrandedPrettyOutput(useColor, ReportBranding{})
+}
+
+// GetBrandedPrettyOutput returns a human-readable string with a custom title and footer
+func (res AuditData) GetBrandedPrettyOutput(useColor bool, branding ReportBranding) string {
+	return res.getPrettyOutput(useColor, branding, false)
+}
+
+func (res AuditData) getPrettyOutput(useColor bool, branding ReportBranding, groupIdentical bool) string {
 	color.NoColor = !useColor
-	str := titleColor.Sprint(fmt.Sprintf("Polaris audited %s %s at %s\n", res.SourceType, res.SourceName, res.AuditTime))
+	title := fmt.Sprintf("Polaris audited %s %s at %s", res.SourceType, res.SourceName, res.AuditTime)
+	if branding.Title != "" {
+		title = branding.Title
+	}
+	str := titleColor.Sprint(title + "\n")
 	str += color.CyanString(fmt.Sprintf("    Nodes: %d | Namespaces: %d | Controllers: %d\n", res.ClusterInfo.Nodes, res.ClusterInfo.Namespaces, res.ClusterInfo.Controllers))
 	str += color.GreenString(fmt.Sprintf("    Final score: %d\n", res.Score))
 	str += "\n"
-	for _, result := range res.Results {
-		str += result.GetPrettyOutput() + "\n"
+	if groupIdentical {
+		for _, group := range res.GroupIdenticalResults() {
+			str += group.GetPrettyOutput() + "\n"
+		}
+	} else {
+		for _, result := range res.Results {
+			str += result.GetPrettyOutput() + "\n"
+		}
+	}
+	if branding.Footer != "" {
+		str += branding.Footer + "\n"
 	}
 	color.NoColor = false
 	return str
@@ -233,7 +281,14 @@ const minIDLength = 40
 func (res ResultSet) GetPrettyOutput() string {
 	indent := "    "
 	str := ""
-	for _, msg := range res {
+	// sorted by check ID, so the output is the same from run to run
+	ids := make([]string, 0, len(res))
+	for id := range res {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		msg := res[id]
 		status := color.GreenString(successMessage)
 		if !msg.Success {
 			if msg.Severity == config.SeverityWarning {
@@ -245,8 +300,105 @@ func (res ResultSet) GetPrettyOutput() string {
 		if color.NoColor {
 			status = strings.Fields(status)[1] // remove emoji
 		}
+		if !msg.Success && msg.Priority != "" {
+			status += fmt.Sprintf(" (%s)", msg.Priority)
+		}
 		str += fmt.Sprintf("%s%s %s\n", indent, checkColor.Sprint(fillString(msg.ID, minIDLength-len(indent))), status)
 		str += fmt.Sprintf("%s    %s - %s\n", indent, msg.Category, msg.Message)
+		if msg.Evidence != "" {
+			str += fmt.Sprintf("%s    Observed: %s\n", indent, msg.Evidence)
+		}
+	}
+	return str
+}
+
+// GetStatusOutput returns one line per resource, e.g. namespace/kind/name: FAIL(3 dangers)
+func (res AuditData) GetStatusOutput() string {
+	str := ""
+	for _, result := range res.Results {
+		str += result.GetStatusOutput() + "\n"
 	}
 	return str
 }
+
+// GetStatusOutput returns a one-line PASS/FAIL summary for the resource
+func (res Result) GetStatusOutput() string {
+	id := res.Kind + "/" + res.Name
+	if res.Namespace != "" {
+		id = res.Namespace + "/" + id
+	}
+	summary := res.GetSummary()
+	if summary.Dangers == 0 && summary.Warnings == 0 {
+		return id + ": PASS"
+	}
+	counts := []string{}
+	if summary.Dangers > 0 {
+		counts = append(counts, pluralize(summary.Dangers, "danger"))
+	}
+	if summary.Warnings > 0 {
+		counts = append(counts, pluralize(summary.Warnings, "warning"))
+	}
+	return fmt.Sprintf("%s: FAIL(%s)", id, strings.Join(counts, ", "))
+}
+
+func pluralize(count uint, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+type ndjsonMeta struct {
+	Type                 string `json:"type"`
+	PolarisOutputVersion string
+	AuditTime            string
+	SourceType           string
+	SourceName           string
+	DisplayName          string
+	ClusterInfo          ClusterInfo
+	Metadata             map[string]interface{} `json:",omitempty"`
+	Score                uint
+	ScoreGranularity     ScoreGranularity `json:",omitempty"`
+}
+
+type ndjsonResult struct {
+	Type string `json:"type"`
+	Result
+}
+
+// GetNDJSONOutput returns a metadata line followed by one line per result, as newline-delimited JSON
+func (res AuditData) GetNDJSONOutput() ([]byte, error) {
+	buf := bytes.Buffer{}
+	err := res.WriteNDJSON(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteNDJSON writes the NDJSON output to w one line at a time, so it can be streamed
+func (res AuditData) WriteNDJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(ndjsonMeta{
+		Type:                 "meta",
+		PolarisOutputVersion: res.PolarisOutputVersion,
+		AuditTime:            res.AuditTime,
+		SourceType:           res.SourceType,
+		SourceName:           res.SourceName,
+		DisplayName:          res.DisplayName,
+		ClusterInfo:          res.ClusterInfo,
+		Metadata:             res.Metadata,
+		Score:                res.Score,
+		ScoreGranularity:     res.ScoreGranularity,
+	})
+	if err != nil {
+		return err
+	}
+	for _, result := range res.Results {
+		err = encoder.Encode(ndjsonResult{Type: "result", Result: result})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func getOutputTestAudit() AuditData {
+	return AuditData{
+		Results: []Result{
+			{
+				Kind:      "Deployment",
+				Name:      "api",
+				Namespace: "payments",
+				Results: ResultSet{
+					"deploymentMissingReplicas": {ID: "deploymentMissingReplicas", Success: true, Severity: config.SeverityWarning},
+				},
+				PodResult: &PodResult{
+					Results: ResultSet{
+						"hostIPCSet": {ID: "hostIPCSet", Success: false, Severity: config.SeverityDanger},
+					},
+					ContainerResults: []ContainerResult{{
+						Name: "api",
+						Results: ResultSet{
+							"cpuLimitsMissing":      {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning},
+							"memoryLimitsMissing":   {ID: "memoryLimitsMissing", Success: false, Severity: config.SeverityWarning},
+							"readinessProbeMissing": {ID: "readinessProbeMissing", Success: false, Severity: config.SeverityDanger},
+						},
+					}},
+				},
+			},
+			{
+				Kind: "ClusterRole",
+				Name: "viewer",
+				Results: ResultSet{
+					"clusterrolePodExecAttach": {ID: "clusterrolePodExecAttach", Success: true, Severity: config.SeverityDanger},
+				},
+			},
+		},
+	}
+}
+
+func TestGetStatusOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	expected := "payments/Deployment/api: FAIL(2 dangers, 2 warnings)\n" +
+		"ClusterRole/viewer: PASS\n"
+	assert.Equal(t, expected, audit.GetStatusOutput())
+
+	assert.Equal(t, "payments/Deployment/api: FAIL(2 dangers, 2 warnings)\n", audit.RemoveSuccessfulResults().GetStatusOutput())
+}
+
+func TestGetNDJSONOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.PolarisOutputVersion = PolarisOutputVersion
+	audit.SourceName = "test"
+	output, err := audit.GetNDJSONOutput()
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	assert.Equal(t, 3, len(lines))
+
+	meta := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &meta))
+	assert.Equal(t, "meta", meta["type"])
+	assert.Equal(t, "test", meta["SourceName"])
+	assert.NotContains(t, meta, "Results")
+
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &result))
+	assert.Equal(t, "result", result["type"])
+	assert.Equal(t, "api", result["Name"])
+	assert.Equal(t, "payments", result["Namespace"])
+}
+
+func TestGetBrandedPrettyOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.SourceType = "Path"
+	audit.SourceName = "./deploy"
+	audit.AuditTime = "2023-03-01T12:00:00Z"
+
+	output := audit.GetPrettyOutput(false)
+	assert.True(t, strings.HasPrefix(output, "Polaris audited Path ./deploy at 2023-03-01T12:00:00Z\n"))
+	assert.Equal(t, output, audit.GetBrandedPrettyOutput(false, ReportBranding{}))
+
+	output = audit.GetBrandedPrettyOutput(false, ReportBranding{Title: "Acme Workload Review", Footer: "Questions? #platform-team"})
+	assert.True(t, strings.HasPrefix(output, "Acme Workload Review\n    Nodes: 0"))
+	assert.NotContains(t, output, "Polaris audited")
+	assert.True(t, strings.HasSuffix(output, "\nQuestions? #platform-team\n"))
+}
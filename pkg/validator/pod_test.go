@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	conf "github.com/fairwindsops/polaris/pkg/config"
@@ -209,3 +210,75 @@ func TestExemption(t *testing.T) {
 	assert.EqualValues(t, expectedSum, actualPodResult.GetSummary())
 	assert.EqualValues(t, expectedResults, actualPodResult.PodResult.Results)
 }
+
+func TestEphemeralContainersPod(t *testing.T) {
+	c := conf.Configuration{
+		Checks: map[string]conf.Severity{
+			"ephemeralContainersSet": conf.SeverityWarning,
+		},
+	}
+
+	p := test.MockPod()
+	p.Spec.EphemeralContainers = []corev1.EphemeralContainer{
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox:1.36"}},
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "sidecar", Image: "example.com/tools:v1"}},
+	}
+	workload, err := kube.NewGenericResourceFromPod(p, p)
+	assert.NoError(t, err)
+
+	actualPodResult, err := applyControllerSchemaChecks(&c, nil, workload)
+	assert.NoError(t, err)
+	result := actualPodResult.PodResult.Results["ephemeralContainersSet"]
+	assert.False(t, result.Success)
+	assert.Equal(t, conf.SeverityWarning, result.Severity)
+	assert.Equal(t, "Ephemeral containers should not be left running: busybox:1.36 example.com/tools:v1", result.Message)
+
+	p.Spec.EphemeralContainers = nil
+	workload, err = kube.NewGenericResourceFromPod(p, p)
+	assert.NoError(t, err)
+	actualPodResult, err = applyControllerSchemaChecks(&c, nil, workload)
+	assert.NoError(t, err)
+	result = actualPodResult.PodResult.Results["ephemeralContainersSet"]
+	assert.True(t, result.Success)
+	assert.Equal(t, "No ephemeral containers are running", result.Message)
+}
+
+func TestValidateProbeMisconfigured(t *testing.T) {
+	c := conf.Configuration{
+		Checks: map[string]conf.Severity{
+			"probeMisconfigured": conf.SeverityWarning,
+		},
+	}
+
+	probe := &corev1.Probe{
+		ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}},
+		FailureThreshold: 1,
+	}
+	p := test.MockPod()
+	p.Spec.Containers[0].LivenessProbe = probe
+	p.Spec.Containers[0].ReadinessProbe = probe
+	workload, err := kube.NewGenericResourceFromPod(p, p)
+	assert.NoError(t, err)
+
+	actualPodResult, err := applyControllerSchemaChecks(&c, nil, workload)
+	assert.NoError(t, err)
+	result := actualPodResult.PodResult.ContainerResults[0].Results["probeMisconfigured"]
+	assert.False(t, result.Success)
+	assert.Equal(t, conf.SeverityWarning, result.Severity)
+	assert.Equal(t, "Probes are misconfigured. The liveness probe restarts the container after a single failure with no initial delay."+
+		" The liveness and readiness probes are identical, so a slow container is restarted instead of taken out of service.", result.Message)
+
+	p.Spec.Containers[0].ReadinessProbe = nil
+	p.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+		ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}},
+		InitialDelaySeconds: 5,
+		FailureThreshold:    1,
+	}
+	workload, err = kube.NewGenericResourceFromPod(p, p)
+	assert.NoError(t, err)
+	actualPodResult, err = applyControllerSchemaChecks(&c, nil, workload)
+	assert.NoError(t, err)
+	result = actualPodResult.PodResult.ContainerResults[0].Results["probeMisconfigured"]
+	assert.True(t, result.Success)
+	assert.Equal(t, "Probe timings are safe", result.Message)
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+// getPolicyCheckSeverity returns the severity of a policy check, i.e. one implemented here rather than with a
+// schema, for the resource. It returns false if the check isn't enabled in checks, is limited to other kinds,
+// or the resource or container is exempt.
+func getPolicyCheckSeverity(conf *config.Configuration, checkID string, resource kube.GenericResource, containerName string) (config.Severity, bool) {
+	if !conf.DisallowExemptions && !conf.DisallowAnnotationExemptions && hasExemptionAnnotation(resource.ObjectMeta, checkID) {
+		return "", false
+	}
+	if !conf.IsActionable(checkID, resource.ObjectMeta, containerName) || !conf.IsCheckEnabledForKind(checkID, resource.Kind) {
+		return "", false
+	}
+	severity, _ := conf.GetSeverity(checkID, resource.ObjectMeta)
+	return severity, true
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "github.com/fairwindsops/polaris/pkg/config"
+
+// FilterByPriority removes failures with a priority below the minimum, so teams can focus on
+// e.g. must-fix findings regardless of severity. The score is kept, since the removed failures still count.
+func (res AuditData) FilterByPriority(minimum config.Priority) AuditData {
+	filter := func(result Result, container string, rs ResultSet) ResultSet {
+		filtered := ResultSet{}
+		for id, msg := range rs {
+			if !msg.Success && !msg.Priority.IsAtLeast(minimum) {
+				continue
+			}
+			filtered[id] = msg
+		}
+		return filtered
+	}
+	return res.mapResultSets(filter)
+}
+
+// getResultPriority returns the priority to report for a check. Neutral priorities are left
+// empty, so results only carry a priority when one has been set.
+func getResultPriority(conf *config.Configuration, checkID string) config.Priority {
+	priority := conf.GetPriority(checkID)
+	if priority == config.PriorityNeutral {
+		return ""
+	}
+	return priority
+}
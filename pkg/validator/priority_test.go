@@ -0,0 +1,45 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterByPriority(t *testing.T) {
+	audit := getOutputTestAudit()
+	containerResults := audit.Results[0].PodResult.ContainerResults[0].Results
+	readiness := containerResults["readinessProbeMissing"]
+	readiness.Priority = config.PriorityMustFix
+	containerResults["readinessProbeMissing"] = readiness
+	memory := containerResults["memoryLimitsMissing"]
+	memory.Priority = config.PriorityNiceToHave
+	containerResults["memoryLimitsMissing"] = memory
+	audit.Score = audit.GetSummary().GetScore()
+
+	filtered := audit.FilterByPriority(config.PriorityNeutral)
+	assert.Equal(t, "payments/Deployment/api: FAIL(2 dangers, 1 warning)\n", filtered.RemoveSuccessfulResults().GetStatusOutput())
+
+	filtered = audit.FilterByPriority(config.PriorityMustFix)
+	assert.Equal(t, "payments/Deployment/api: FAIL(1 danger)\n", filtered.RemoveSuccessfulResults().GetStatusOutput())
+	assert.Contains(t, filtered.Results[0].Results, "deploymentMissingReplicas")
+	assert.Equal(t, audit.Score, filtered.Score, "the hidden failures should still count toward the score")
+	assert.NotEqual(t, filtered.GetSummary().GetScore(), filtered.Score)
+
+	assert.Equal(t, "payments/Deployment/api: FAIL(2 dangers, 2 warnings)\n", audit.RemoveSuccessfulResults().GetStatusOutput())
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const resourceProfileCheckID = "resourceProfileMismatch"
+
+// matchesResourceList returns true if every quantity in the profile is set to the same value in actual
+func matchesResourceList(profile map[string]string, actual corev1.ResourceList) bool {
+	for resourceName, expectedStr := range profile {
+		expected, err := resource.ParseQuantity(expectedStr)
+		if err != nil {
+			return false
+		}
+		quantity, ok := actual[corev1.ResourceName(resourceName)]
+		if !ok || quantity.Cmp(expected) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// applyResourceProfileCheck checks that a container's requests and limits match one of the configured profiles
+func applyResourceProfileCheck(conf *config.Configuration, controller kube.GenericResource, container *corev1.Container) *ResultMessage {
+	if len(conf.ResourceProfiles) == 0 {
+		return nil
+	}
+	severity, ok := getPolicyCheckSeverity(conf, resourceProfileCheckID, controller, container.Name)
+	if !ok {
+		return nil
+	}
+	names := []string{}
+	for name := range conf.ResourceProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := ResultMessage{
+		ID:       resourceProfileCheckID,
+		Severity: severity,
+		Category: "Efficiency",
+		Priority: getResultPriority(conf, resourceProfileCheckID),
+	}
+	for _, name := range names {
+		profile := conf.ResourceProfiles[name]
+		if matchesResourceList(profile.Requests, container.Resources.Requests) && matchesResourceList(profile.Limits, container.Resources.Limits) {
+			result.Success = true
+			result.Message = fmt.Sprintf("Resources match the %s profile", name)
+			return &result
+		}
+	}
+	result.Message = fmt.Sprintf("Resources should match one of the allowed profiles: %s", strings.Join(names, ", "))
+	return &result
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var profilesTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+        resources:
+          requests:
+            cpu: 500m
+            memory: 512Mi
+          limits:
+            memory: 512Mi
+      - name: sidecar
+        image: sidecar:v1
+        resources:
+          requests:
+            cpu: 50m
+`
+
+func TestResourceProfiles(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(profilesTestYaml)
+	deployment := provider.Resources["apps/Deployment"][0]
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{resourceProfileCheckID: conf.SeverityWarning}}
+	result, err := applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[0].Results, resourceProfileCheckID, "should be off without profiles")
+
+	c.ResourceProfiles = map[string]conf.ResourceProfile{
+		"small": {
+			Requests: map[string]string{"cpu": "100m", "memory": "128Mi"},
+			Limits:   map[string]string{"memory": "128Mi"},
+		},
+		"medium": {
+			Requests: map[string]string{"cpu": "0.5", "memory": "512Mi"},
+			Limits:   map[string]string{"memory": "512Mi"},
+		},
+	}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+
+	api := result.PodResult.ContainerResults[0].Results[resourceProfileCheckID]
+	assert.True(t, api.Success)
+	assert.Equal(t, "Resources match the medium profile", api.Message)
+
+	sidecar := result.PodResult.ContainerResults[1].Results[resourceProfileCheckID]
+	assert.False(t, sidecar.Success)
+	assert.Equal(t, conf.SeverityWarning, sidecar.Severity)
+	assert.Equal(t, "Resources should match one of the allowed profiles: medium, small", sidecar.Message)
+
+	c.Exemptions = []conf.Exemption{{Rules: []string{resourceProfileCheckID}, ControllerNames: []string{deployment.ObjectMeta.GetName()}, ContainerNames: []string{"sidecar"}}}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.Contains(t, result.PodResult.ContainerResults[0].Results, resourceProfileCheckID)
+	assert.NotContains(t, result.PodResult.ContainerResults[1].Results, resourceProfileCheckID)
+
+	delete(c.Checks, resourceProfileCheckID)
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.PodResult.ContainerResults[1].Results, resourceProfileCheckID, "should be off until it's given a severity")
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4"
+
+// prometheusSeries identifies the checks counted by one series of the checks gauges
+type prometheusSeries struct {
+	Check     string
+	Severity  config.Severity
+	Namespace string
+	Kind      string
+}
+
+// prometheusCounts are the values of one series of the checks gauges
+type prometheusCounts struct {
+	Passed int
+	Failed int
+}
+
+func (s prometheusSeries) labels() string {
+	return fmt.Sprintf(`check="%s",severity="%s",namespace="%s",kind="%s"`,
+		escapePrometheusLabel(s.Check), escapePrometheusLabel(string(s.Severity)), escapePrometheusLabel(s.Namespace), escapePrometheusLabel(s.Kind))
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapePrometheusLabel(value string) string {
+	return prometheusLabelEscaper.Replace(value)
+}
+
+// GetPrometheusOutput returns the audit as gauges in the Prometheus text exposition format, e.g. to push
+// to a Pushgateway. Checks are counted by check ID, severity, namespace and kind, and every series has
+// both a passed and a failed count, so fixed failures drop to 0 rather than disappearing.
+func (res AuditData) GetPrometheusOutput() string {
+	counts := map[prometheusSeries]prometheusCounts{}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs {
+			series := prometheusSeries{Check: msg.ID, Severity: msg.Severity, Namespace: result.Namespace, Kind: result.Kind}
+			seriesCounts := counts[series]
+			if msg.Success {
+				seriesCounts.Passed++
+			} else {
+				seriesCounts.Failed++
+			}
+			counts[series] = seriesCounts
+		}
+		return rs
+	})
+	allSeries := []prometheusSeries{}
+	for series := range counts {
+		allSeries = append(allSeries, series)
+	}
+	sort.Slice(allSeries, func(i, j int) bool {
+		return allSeries[i].labels() < allSeries[j].labels()
+	})
+
+	str := "# HELP polaris_score Score of the Polaris audit, from 0 to 100.\n"
+	str += "# TYPE polaris_score gauge\n"
+	str += fmt.Sprintf("polaris_score %d\n", res.GetSummary().GetScore())
+	str += "# HELP polaris_checks_passed Number of passing checks.\n"
+	str += "# TYPE polaris_checks_passed gauge\n"
+	for _, series := range allSeries {
+		str += fmt.Sprintf("polaris_checks_passed{%s} %d\n", series.labels(), counts[series].Passed)
+	}
+	str += "# HELP polaris_checks_failed Number of failing checks.\n"
+	str += "# TYPE polaris_checks_failed gauge\n"
+	for _, series := range allSeries {
+		str += fmt.Sprintf("polaris_checks_failed{%s} %d\n", series.labels(), counts[series].Failed)
+	}
+	return str
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestGetPrometheusOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results = append(audit.Results, Result{
+		Kind:      "Deployment",
+		Name:      "worker",
+		Namespace: "payments",
+		Results: ResultSet{
+			"deploymentMissingReplicas": {ID: "deploymentMissingReplicas", Success: false, Severity: config.SeverityWarning},
+		},
+	})
+	expected := `# HELP polaris_score Score of the Polaris audit, from 0 to 100.
+# TYPE polaris_score gauge
+polaris_score 36
+# HELP polaris_checks_passed Number of passing checks.
+# TYPE polaris_checks_passed gauge
+polaris_checks_passed{check="clusterrolePodExecAttach",severity="danger",namespace="",kind="ClusterRole"} 1
+polaris_checks_passed{check="cpuLimitsMissing",severity="warning",namespace="payments",kind="Deployment"} 0
+polaris_checks_passed{check="deploymentMissingReplicas",severity="warning",namespace="payments",kind="Deployment"} 1
+polaris_checks_passed{check="hostIPCSet",severity="danger",namespace="payments",kind="Deployment"} 0
+polaris_checks_passed{check="memoryLimitsMissing",severity="warning",namespace="payments",kind="Deployment"} 0
+polaris_checks_passed{check="readinessProbeMissing",severity="danger",namespace="payments",kind="Deployment"} 0
+# HELP polaris_checks_failed Number of failing checks.
+# TYPE polaris_checks_failed gauge
+polaris_checks_failed{check="clusterrolePodExecAttach",severity="danger",namespace="",kind="ClusterRole"} 0
+polaris_checks_failed{check="cpuLimitsMissing",severity="warning",namespace="payments",kind="Deployment"} 1
+polaris_checks_failed{check="deploymentMissingReplicas",severity="warning",namespace="payments",kind="Deployment"} 1
+polaris_checks_failed{check="hostIPCSet",severity="danger",namespace="payments",kind="Deployment"} 1
+polaris_checks_failed{check="memoryLimitsMissing",severity="warning",namespace="payments",kind="Deployment"} 1
+polaris_checks_failed{check="readinessProbeMissing",severity="danger",namespace="payments",kind="Deployment"} 1
+`
+	assert.Equal(t, expected, audit.GetPrometheusOutput())
+}
+
+func TestEscapePrometheusLabel(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapePrometheusLabel("a\"b\\c\nd"))
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// ApplySeverityPromotion counts how many consecutive audits each finding has failed, given the
+// streaks from the previous audit, and promotes warnings to dangers once a finding has failed
+// afterFailures audits in a row. A non-positive afterFailures only updates the streaks.
+// The updated streaks are returned so they can be stored for the next audit.
+func (res AuditData) ApplySeverityPromotion(previousStreaks map[string]int, afterFailures int) (AuditData, map[string]int) {
+	streaks := map[string]int{}
+	promote := func(result Result, container string, rs ResultSet) ResultSet {
+		promoted := ResultSet{}
+		for id, msg := range rs {
+			if !msg.Success {
+				key := BaselineEntry{Namespace: result.Namespace, Kind: result.Kind, Name: result.Name, Container: container, Check: msg.ID}.key()
+				streaks[key] = previousStreaks[key] + 1
+				if afterFailures > 0 && streaks[key] >= afterFailures && msg.Severity == config.SeverityWarning {
+					msg.Severity = config.SeverityDanger
+					msg.PromotionReason = fmt.Sprintf("Promoted from %s to %s after failing %d consecutive audits", config.SeverityWarning, config.SeverityDanger, streaks[key])
+				}
+			}
+			promoted[id] = msg
+		}
+		return promoted
+	}
+	resCopy := res.mapResultSets(promote)
+	resCopy.Score = resCopy.GetSummary().GetScore()
+	return resCopy, streaks
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySeverityPromotion(t *testing.T) {
+	audit := getOutputTestAudit()
+	before := audit.GetSummary()
+
+	promoted, streaks := audit.ApplySeverityPromotion(nil, 2)
+	assert.Equal(t, before, promoted.GetSummary(), "a single failure should not be promoted")
+	assert.Len(t, streaks, int(before.Dangers+before.Warnings))
+	for _, streak := range streaks {
+		assert.Equal(t, 1, streak)
+	}
+
+	promoted, streaks = audit.ApplySeverityPromotion(streaks, 2)
+	after := promoted.GetSummary()
+	assert.Equal(t, uint(0), after.Warnings)
+	assert.Equal(t, before.Dangers+before.Warnings, after.Dangers)
+	assert.Equal(t, after.GetScore(), promoted.Score)
+	for _, streak := range streaks {
+		assert.Equal(t, 2, streak)
+	}
+	reasons := 0
+	for _, msg := range promoted.Results[0].PodResult.ContainerResults[0].Results {
+		if msg.PromotionReason != "" {
+			reasons++
+			assert.Contains(t, msg.PromotionReason, "2 consecutive audits")
+		}
+	}
+	assert.Equal(t, int(before.Warnings), reasons)
+	assert.Equal(t, before, audit.GetSummary(), "original audit should not be modified")
+
+	_, streaks = audit.RemoveSuccessfulResults().ApplyBaseline(NewBaselineFromAudit(audit)).ApplySeverityPromotion(streaks, 2)
+	assert.Empty(t, streaks, "passing findings should reset their streak")
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// Redact returns a copy of the audit with redact applied to its names, messages, details, and evidence,
+// e.g. to remove secret values which were templated into them
+func (res AuditData) Redact(redact func(string) string) AuditData {
+	resCopy := res
+	resCopy.SourceName = redact(res.SourceName)
+	resCopy.DisplayName = redact(res.DisplayName)
+	resCopy.Results = make([]Result, len(res.Results))
+	for idx, result := range res.Results {
+		result.Name = redact(result.Name)
+		result.Owner = redact(result.Owner)
+		result.Results = result.Results.redact(redact)
+		if result.PodResult != nil {
+			podCopy := *result.PodResult
+			podCopy.Name = redact(podCopy.Name)
+			podCopy.Results = podCopy.Results.redact(redact)
+			podCopy.ContainerResults = make([]ContainerResult, len(result.PodResult.ContainerResults))
+			for cIdx, containerResult := range result.PodResult.ContainerResults {
+				containerResult.Name = redact(containerResult.Name)
+				containerResult.Results = containerResult.Results.redact(redact)
+				podCopy.ContainerResults[cIdx] = containerResult
+			}
+			result.PodResult = &podCopy
+		}
+		resCopy.Results[idx] = result
+	}
+	return resCopy
+}
+
+func (res ResultSet) redact(redact func(string) string) ResultSet {
+	if res == nil {
+		return nil
+	}
+	resCopy := ResultSet{}
+	for key, msg := range res {
+		msg.Message = redact(msg.Message)
+		msg.Evidence = redact(msg.Evidence)
+		msg.PromotionReason = redact(msg.PromotionReason)
+		if msg.Details != nil {
+			details := make([]string, len(msg.Details))
+			for idx, detail := range msg.Details {
+				details[idx] = redact(detail)
+			}
+			msg.Details = details
+		}
+		resCopy[key] = msg
+	}
+	return resCopy
+}
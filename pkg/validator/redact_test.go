@@ -0,0 +1,56 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	// values which JSON escapes, so they'd be missed when redacting marshaled output
+	secret := "p\"a\\ss\nword"
+	audit := AuditData{
+		SourceName: "cluster",
+		Results: []Result{{
+			Kind: "Deployment",
+			Name: "api",
+			Results: ResultSet{
+				"env": {ID: "env", Message: "DB password " + secret + " is set"},
+			},
+			PodResult: &PodResult{
+				Name: "api",
+				ContainerResults: []ContainerResult{{
+					Name: "api",
+					Results: ResultSet{
+						"args": {ID: "args", Message: "ok", Details: []string{"--password=" + secret}, Evidence: "args: " + secret},
+					},
+				}},
+			},
+		}},
+	}
+	redacted := audit.Redact(func(str string) string {
+		return strings.ReplaceAll(str, secret, "<redacted>")
+	})
+	assert.Equal(t, "DB password <redacted> is set", redacted.Results[0].Results["env"].Message)
+	containerResult := redacted.Results[0].PodResult.ContainerResults[0].Results["args"]
+	assert.Equal(t, []string{"--password=<redacted>"}, containerResult.Details)
+	assert.Equal(t, "args: <redacted>", containerResult.Evidence)
+	assert.Equal(t, "api", redacted.Results[0].Name)
+	assert.Equal(t, "DB password "+secret+" is set", audit.Results[0].Results["env"].Message, "original audit should not be modified")
+	assert.Equal(t, "--password="+secret, audit.Results[0].PodResult.ContainerResults[0].Results["args"].Details[0])
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thoas/go-funk"
+)
+
+// RunbookResource is a resource that needs a remediation action, with the containers it applies to
+type RunbookResource struct {
+	Namespace  string
+	Kind       string
+	Name       string
+	Containers []string `json:",omitempty"`
+}
+
+// String returns the resource as namespace/kind/name
+func (r RunbookResource) String() string {
+	id := r.Kind + "/" + r.Name
+	if r.Namespace != "" {
+		id = r.Namespace + "/" + id
+	}
+	return id
+}
+
+// RunbookAction is a single remediation action, with the checks it fixes and the resources it applies to
+type RunbookAction struct {
+	Action    string
+	Checks    []string
+	Findings  int
+	Resources []RunbookResource
+}
+
+// GetRunbook groups the failures in the audit by remediation action, using remediations to map each
+// check to its action. Checks without a remediation get an action of their own. Actions which apply
+// to the most resources come first.
+func (res AuditData) GetRunbook(remediations map[string]string) []RunbookAction {
+	actions := map[string]*RunbookAction{}
+	resourceIdx := map[string]map[string]int{}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		for _, msg := range rs.GetSortedResults() {
+			if msg.Success {
+				continue
+			}
+			name, ok := remediations[msg.ID]
+			if !ok {
+				name = fmt.Sprintf("Fix %s findings", msg.ID)
+			}
+			action, ok := actions[name]
+			if !ok {
+				action = &RunbookAction{Action: name, Checks: []string{}, Resources: []RunbookResource{}}
+				actions[name] = action
+				resourceIdx[name] = map[string]int{}
+			}
+			action.Findings++
+			if !funk.ContainsString(action.Checks, msg.ID) {
+				action.Checks = append(action.Checks, msg.ID)
+			}
+			resource := RunbookResource{Namespace: result.Namespace, Kind: result.Kind, Name: result.Name}
+			idx, ok := resourceIdx[name][resource.String()]
+			if !ok {
+				idx = len(action.Resources)
+				resourceIdx[name][resource.String()] = idx
+				action.Resources = append(action.Resources, resource)
+			}
+			if container != "" && !funk.ContainsString(action.Resources[idx].Containers, container) {
+				action.Resources[idx].Containers = append(action.Resources[idx].Containers, container)
+			}
+		}
+		return rs
+	})
+
+	runbook := []RunbookAction{}
+	for _, action := range actions {
+		sort.Strings(action.Checks)
+		sort.Slice(action.Resources, func(i, j int) bool {
+			return action.Resources[i].String() < action.Resources[j].String()
+		})
+		runbook = append(runbook, *action)
+	}
+	sort.Slice(runbook, func(i, j int) bool {
+		if len(runbook[i].Resources) != len(runbook[j].Resources) {
+			return len(runbook[i].Resources) > len(runbook[j].Resources)
+		}
+		return runbook[i].Action < runbook[j].Action
+	})
+	return runbook
+}
+
+// GetRunbookPrettyOutput returns a human-readable runbook, listing the affected resources under each action
+func GetRunbookPrettyOutput(runbook []RunbookAction) string {
+	if len(runbook) == 0 {
+		return "No remediation needed\n"
+	}
+	str := ""
+	for idx, action := range runbook {
+		str += fmt.Sprintf("%d. %s on %s (%s)\n", idx+1, action.Action, pluralize(uint(len(action.Resources)), "resource"), strings.Join(action.Checks, ", "))
+		for _, resource := range action.Resources {
+			str += "    " + resource.String()
+			if len(resource.Containers) > 0 {
+				str += " containers: " + strings.Join(resource.Containers, ", ")
+			}
+			str += "\n"
+		}
+	}
+	return str
+}
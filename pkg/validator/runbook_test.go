@@ -0,0 +1,70 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRunbook(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results = append(audit.Results, Result{
+		Kind:      "Deployment",
+		Name:      "worker",
+		Namespace: "payments",
+		PodResult: &PodResult{
+			ContainerResults: []ContainerResult{
+				{Name: "worker", Results: ResultSet{
+					"cpuLimitsMissing": {ID: "cpuLimitsMissing", Success: false, Severity: config.SeverityWarning},
+				}},
+				{Name: "sidecar", Results: ResultSet{
+					"memoryLimitsMissing": {ID: "memoryLimitsMissing", Success: false, Severity: config.SeverityWarning},
+				}},
+			},
+		},
+	})
+	remediations := map[string]string{
+		"cpuLimitsMissing":      "Add resource limits",
+		"memoryLimitsMissing":   "Add resource limits",
+		"readinessProbeMissing": "Add health probes",
+	}
+	runbook := audit.GetRunbook(remediations)
+	assert.Len(t, runbook, 3)
+
+	assert.Equal(t, "Add resource limits", runbook[0].Action)
+	assert.Equal(t, []string{"cpuLimitsMissing", "memoryLimitsMissing"}, runbook[0].Checks)
+	assert.Equal(t, 4, runbook[0].Findings)
+	assert.Equal(t, []RunbookResource{
+		{Namespace: "payments", Kind: "Deployment", Name: "api", Containers: []string{"api"}},
+		{Namespace: "payments", Kind: "Deployment", Name: "worker", Containers: []string{"worker", "sidecar"}},
+	}, runbook[0].Resources)
+
+	assert.Equal(t, "Add health probes", runbook[1].Action)
+	assert.Equal(t, "Fix hostIPCSet findings", runbook[2].Action)
+	assert.Equal(t, []RunbookResource{{Namespace: "payments", Kind: "Deployment", Name: "api"}}, runbook[2].Resources)
+
+	expected := "1. Add resource limits on 2 resources (cpuLimitsMissing, memoryLimitsMissing)\n" +
+		"    payments/Deployment/api containers: api\n" +
+		"    payments/Deployment/worker containers: worker, sidecar\n" +
+		"2. Add health probes on 1 resource (readinessProbeMissing)\n" +
+		"    payments/Deployment/api containers: api\n" +
+		"3. Fix hostIPCSet findings on 1 resource (hostIPCSet)\n" +
+		"    payments/Deployment/api\n"
+	assert.Equal(t, expected, GetRunbookPrettyOutput(runbook))
+	assert.Equal(t, "No remediation needed\n", GetRunbookPrettyOutput(AuditData{}.GetRunbook(remediations)))
+}
@@ -0,0 +1,260 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+const (
+	// SARIFVersion is the version of the SARIF specification the output conforms to
+	SARIFVersion = "2.1.0"
+	// SARIFSchema is the JSON schema of SARIF 2.1.0 documents
+	SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	polarisInformationURI = "https://github.com/FairwindsOps/polaris"
+	polarisChecksDocsURI  = "https://polaris.docs.fairwinds.com/checks/"
+)
+
+// SARIFLog is a SARIF document with a single run of Polaris
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun contains the rules Polaris checked and the failures it found
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes Polaris and its rules
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes Polaris and its rules
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a single check
+type SARIFRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     *SARIFMessage          `json:"shortDescription,omitempty"`
+	FullDescription      *SARIFMessage          `json:"fullDescription,omitempty"`
+	Help                 *SARIFMessage          `json:"help,omitempty"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	DefaultConfiguration SARIFRuleConfiguration `json:"defaultConfiguration"`
+	Properties           SARIFRuleProperties    `json:"properties"`
+}
+
+// SARIFRuleConfiguration contains the level of a rule's results
+type SARIFRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFRuleProperties contains the category of a check, which GitHub shows as a tag
+type SARIFRuleProperties struct {
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a single failed check
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation locates a result in a file, or by the resource it was found in
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation is a region of a file
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is the path of a file
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the line a resource starts on
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFLogicalLocation identifies a resource as namespace/kind/name
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel maps a Polaris severity to a SARIF level
+func sarifLevel(severity config.Severity) string {
+	if severity == config.SeverityDanger {
+		return "error"
+	}
+	return "warning"
+}
+
+// GetSARIFOutput returns the failures in the audit as a SARIF 2.1.0 document. Rules are described by the
+// enabled checks in conf. Results found in files audited with --audit-path are located by file and line,
+// and other results by the namespace, kind and name of the resource.
+func (res AuditData) GetSARIFOutput(conf config.Configuration, toolVersion string) SARIFLog {
+	rules := []SARIFRule{}
+	ruleIndexes := map[string]int{}
+	checkIDs := []string{}
+	for checkID, severity := range conf.Checks {
+		if severity != config.SeverityIgnore {
+			checkIDs = append(checkIDs, checkID)
+		}
+	}
+	sort.Strings(checkIDs)
+	for _, checkID := range checkIDs {
+		ruleIndexes[checkID] = len(rules)
+		rules = append(rules, getSARIFRule(conf, checkID))
+	}
+
+	results := []SARIFResult{}
+	res.mapResultSets(func(result Result, container string, rs ResultSet) ResultSet {
+		failedIDs := []string{}
+		for checkID, msg := range rs {
+			if !msg.Success {
+				failedIDs = append(failedIDs, checkID)
+			}
+		}
+		sort.Strings(failedIDs)
+		for _, checkID := range failedIDs {
+			msg := rs[checkID]
+			ruleIndex, ok := ruleIndexes[msg.ID]
+			if !ok {
+				// checks implemented in code, e.g. WebAssembly checks, may not be listed in conf.Checks
+				ruleIndex = len(rules)
+				ruleIndexes[msg.ID] = ruleIndex
+				rules = append(rules, SARIFRule{
+					ID:                   msg.ID,
+					DefaultConfiguration: SARIFRuleConfiguration{Level: sarifLevel(msg.Severity)},
+					Properties:           SARIFRuleProperties{Category: msg.Category, Tags: []string{msg.Category}},
+				})
+			}
+			results = append(results, SARIFResult{
+				RuleID:    msg.ID,
+				RuleIndex: ruleIndex,
+				Level:     sarifLevel(msg.Severity),
+				Message:   SARIFMessage{Text: getSARIFMessage(result, container, msg)},
+				Locations: []SARIFLocation{getSARIFLocation(result)},
+			})
+		}
+		return rs
+	})
+
+	return SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "Polaris",
+				Version:        toolVersion,
+				InformationURI: polarisInformationURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func getSARIFRule(conf config.Configuration, checkID string) SARIFRule {
+	rule := SARIFRule{
+		ID:                   checkID,
+		DefaultConfiguration: SARIFRuleConfiguration{Level: sarifLevel(conf.Checks[checkID])},
+	}
+	check, ok := conf.CustomChecks[checkID]
+	if !ok {
+		check, ok = config.BuiltInChecks[checkID]
+		if ok && check.Category != "" {
+			rule.HelpURI = polarisChecksDocsURI + strings.ToLower(check.Category)
+		}
+	}
+	if !ok {
+		return rule
+	}
+	if check.FailureMessage != "" {
+		rule.ShortDescription = &SARIFMessage{Text: check.FailureMessage}
+		rule.FullDescription = &SARIFMessage{Text: check.FailureMessage}
+	}
+	if check.Remediation != "" {
+		rule.Help = &SARIFMessage{Text: check.Remediation}
+	}
+	if check.Category != "" {
+		rule.Properties = SARIFRuleProperties{Category: check.Category, Tags: []string{check.Category}}
+	}
+	return rule
+}
+
+func getSARIFMessage(result Result, container string, msg ResultMessage) string {
+	text := msg.Message
+	if container != "" {
+		text += " (container " + container + ")"
+	}
+	return text + " in " + getResourceID(result)
+}
+
+func getSARIFLocation(result Result) SARIFLocation {
+	location := SARIFLocation{
+		LogicalLocations: []SARIFLogicalLocation{{
+			FullyQualifiedName: getResourceID(result),
+			Kind:               "resource",
+		}},
+	}
+	if result.SourceFile != "" {
+		location.PhysicalLocation = &SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: filepath.ToSlash(filepath.Clean(result.SourceFile))},
+		}
+		if result.SourceLine > 0 {
+			location.PhysicalLocation.Region = &SARIFRegion{StartLine: result.SourceLine}
+		}
+	}
+	return location
+}
+
+// getResourceID identifies a resource as namespace/kind/name, or kind/name for cluster-scoped resources
+func getResourceID(result Result) string {
+	id := result.Kind + "/" + result.Name
+	if result.Namespace != "" {
+		id = result.Namespace + "/" + id
+	}
+	return id
+}
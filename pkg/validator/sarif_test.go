@@ -0,0 +1,88 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSARIFOutput(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results[0].SourceFile = "./deploy/api.yaml"
+	audit.Results[0].SourceLine = 3
+	audit.Results = append(audit.Results, Result{
+		Kind: "Deployment",
+		Name: "worker",
+		Results: ResultSet{
+			"requiredLabelsMissing": {ID: "requiredLabelsMissing", Message: "Required labels are not set", Success: false, Severity: config.SeverityWarning, Category: "Reliability"},
+		},
+	})
+	conf := config.Configuration{
+		Checks: map[string]config.Severity{
+			"hostIPCSet":            config.SeverityDanger,
+			"cpuLimitsMissing":      config.SeverityWarning,
+			"memoryLimitsMissing":   config.SeverityWarning,
+			"readinessProbeMissing": config.SeverityDanger,
+			"tagNotSpecified":       config.SeverityIgnore,
+		},
+	}
+	sarif := audit.GetSARIFOutput(conf, "1.0.0")
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Len(t, sarif.Runs, 1)
+	driver := sarif.Runs[0].Tool.Driver
+	assert.Equal(t, "Polaris", driver.Name)
+	assert.Equal(t, "1.0.0", driver.Version)
+
+	ruleIDs := []string{}
+	for _, rule := range driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(t, []string{"cpuLimitsMissing", "hostIPCSet", "memoryLimitsMissing", "readinessProbeMissing", "requiredLabelsMissing"}, ruleIDs)
+	hostIPC := driver.Rules[1]
+	assert.Equal(t, "error", hostIPC.DefaultConfiguration.Level)
+	assert.Equal(t, config.BuiltInChecks["hostIPCSet"].FailureMessage, hostIPC.ShortDescription.Text)
+	assert.Equal(t, "Remove access to host namespaces", hostIPC.Help.Text)
+	assert.Equal(t, "https://polaris.docs.fairwinds.com/checks/security", hostIPC.HelpURI)
+	assert.Equal(t, "Reliability", driver.Rules[4].Properties.Category)
+
+	results := sarif.Runs[0].Results
+	assert.Len(t, results, 5)
+	assert.Equal(t, "hostIPCSet", results[0].RuleID)
+	assert.Equal(t, 1, results[0].RuleIndex)
+	assert.Equal(t, "error", results[0].Level)
+	physical := results[0].Locations[0].PhysicalLocation
+	assert.Equal(t, "deploy/api.yaml", physical.ArtifactLocation.URI)
+	assert.Equal(t, 3, physical.Region.StartLine)
+	assert.Equal(t, "payments/Deployment/api", results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, "cpuLimitsMissing", results[1].RuleID)
+	assert.Equal(t, "warning", results[1].Level)
+	assert.Equal(t, "readinessProbeMissing", results[3].RuleID)
+	assert.Equal(t, "error", results[3].Level)
+
+	worker := results[4]
+	assert.Equal(t, "requiredLabelsMissing", worker.RuleID)
+	assert.Equal(t, 4, worker.RuleIndex)
+	assert.Nil(t, worker.Locations[0].PhysicalLocation)
+	assert.Equal(t, "Deployment/worker", worker.Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, "Required labels are not set in Deployment/worker", worker.Message.Text)
+
+	output, err := json.Marshal(sarif)
+	assert.NoError(t, err)
+	assert.Contains(t, string(output), `"$schema":"https://json.schemastore.org/sarif-2.1.0.json"`)
+}
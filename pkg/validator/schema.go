@@ -15,11 +15,14 @@
 package validator
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/qri-io/jsonschema"
 	"github.com/sirupsen/logrus"
@@ -83,6 +86,9 @@ func resolveCheck(conf *config.Configuration, checkID string, test schemaTestCas
 	if !check.IsActionable(test.Target, test.Resource.Kind, test.IsInitContianer) {
 		return nil, nil
 	}
+	if !conf.IsCheckEnabledForKind(check.ID, test.Resource.Kind) {
+		return nil, nil
+	}
 	templateInput, err := getTemplateInput(test)
 	if err != nil {
 		return nil, err
@@ -133,15 +139,17 @@ func getTemplateInput(test schemaTestCase) (map[string]interface{}, error) {
 	return templateInput, nil
 }
 
-func makeResult(conf *config.Configuration, check *config.SchemaCheck, passes bool, issues []jsonschema.ValError) ResultMessage {
+func makeResult(conf *config.Configuration, check *config.SchemaCheck, objMeta metaV1.Object, passes bool, issues []jsonschema.ValError) ResultMessage {
 	details := []string{}
 	for _, issue := range issues {
 		details = append(details, issue.Message)
 	}
+	severity, _ := conf.GetSeverity(check.ID, objMeta)
 	result := ResultMessage{
 		ID:       check.ID,
-		Severity: conf.Checks[check.ID],
+		Severity: severity,
 		Category: check.Category,
+		Priority: getResultPriority(conf, check.ID),
 		Success:  passes,
 		// FIXME: need to fix the tests before adding this back
 		//Details: details,
@@ -156,8 +164,20 @@ func makeResult(conf *config.Configuration, check *config.SchemaCheck, passes bo
 
 const exemptionAnnotationKey = "polaris.fairwinds.com/exempt"
 const exemptionAnnotationPattern = "polaris.fairwinds.com/%s-exempt"
+const exemptionUntilAnnotationKey = "polaris.fairwinds.com/exempt-until"
+const exemptionUntilAnnotationPattern = "polaris.fairwinds.com/%s-exempt-until"
+
+// exemptionExpiryWarningPeriod is how long before an exempt-until date a warning is logged
+const exemptionExpiryWarningPeriod = 14 * 24 * time.Hour
+
+// loggedExemptionWarnings has the exempt-until warnings already logged, so each is only logged once
+var loggedExemptionWarnings sync.Map
 
 func hasExemptionAnnotation(objMeta metaV1.Object, checkID string) bool {
+	return hasExemptionAnnotationAt(objMeta, checkID, time.Now())
+}
+
+func hasExemptionAnnotationAt(objMeta metaV1.Object, checkID string, now time.Time) bool {
 	annot := objMeta.GetAnnotations()
 	val := annot[exemptionAnnotationKey]
 	if strings.ToLower(val) == "true" {
@@ -168,22 +188,110 @@ func hasExemptionAnnotation(objMeta metaV1.Object, checkID string) bool {
 	if strings.ToLower(val) == "true" {
 		return true
 	}
-	return false
+	return isExemptUntil(objMeta, exemptionUntilAnnotationKey, now) ||
+		isExemptUntil(objMeta, fmt.Sprintf(exemptionUntilAnnotationPattern, checkID), now)
+}
+
+// isExemptUntil returns true if the annotation is set to a date like 2024-12-31 which hasn't passed yet.
+// The exemption applies through the end of that day in UTC.
+func isExemptUntil(objMeta metaV1.Object, annotationKey string, now time.Time) bool {
+	val, ok := objMeta.GetAnnotations()[annotationKey]
+	if !ok {
+		return false
+	}
+	until, err := time.Parse("2006-01-02", strings.TrimSpace(val))
+	if err != nil {
+		warnExemptionOnce("Ignoring %s on %s, %q should be a date like 2024-12-31", annotationKey, resourceDisplayName(objMeta), val)
+		return false
+	}
+	expiry := until.AddDate(0, 0, 1)
+	if !now.Before(expiry) {
+		warnExemptionOnce("The exemption %s on %s expired on %s", annotationKey, resourceDisplayName(objMeta), val)
+		return false
+	}
+	if expiry.Sub(now) <= exemptionExpiryWarningPeriod {
+		warnExemptionOnce("The exemption %s on %s expires on %s", annotationKey, resourceDisplayName(objMeta), val)
+	}
+	return true
+}
+
+func warnExemptionOnce(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if _, logged := loggedExemptionWarnings.LoadOrStore(message, true); !logged {
+		logrus.Warn(message)
+	}
+}
+
+func resourceDisplayName(objMeta metaV1.Object) string {
+	if objMeta.GetNamespace() == "" {
+		return objMeta.GetName()
+	}
+	return objMeta.GetNamespace() + "/" + objMeta.GetName()
 }
 
-// ApplyAllSchemaChecksToResourceProvider applies all available checks to a ResourceProvider
+// ApplyAllSchemaChecksToResourceProvider applies all available checks to a ResourceProvider, validating up to
+// conf.GetParallelism() resources at once. The results are sorted by namespace, kind and name.
 func ApplyAllSchemaChecksToResourceProvider(conf *config.Configuration, resourceProvider *kube.ResourceProvider) ([]Result, error) {
-	results := []Result{}
+	return StreamAllSchemaChecksToResourceProvider(conf, resourceProvider, nil)
+}
+
+// StreamAllSchemaChecksToResourceProvider is like ApplyAllSchemaChecksToResourceProvider, and also calls onResult
+// with each resource's result as soon as it's validated, e.g. to update a ScoreAccumulator. Results arrive in no
+// particular order, and onResult is never called concurrently.
+func StreamAllSchemaChecksToResourceProvider(conf *config.Configuration, resourceProvider *kube.ResourceProvider, onResult func(Result)) ([]Result, error) {
 	if resourceProvider == nil {
 		return nil, errors.New("No resource provider set, cannot apply schema checks")
 	}
-	for _, resources := range resourceProvider.Resources {
-		kindResults, err := ApplyAllSchemaChecksToAllResources(conf, resourceProvider, resources)
-		if err != nil {
-			return results, err
+	resources := []kube.GenericResource{}
+	for _, kindResources := range resourceProvider.Resources {
+		resources = append(resources, kindResources...)
+	}
+	// each worker only writes the results and errors at the indexes of its own resources
+	allResults := make([]Result, len(resources))
+	errs := make([]error, len(resources))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	onResultMutex := sync.Mutex{}
+	for worker := 0; worker < conf.GetParallelism() && worker < len(resources); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				allResults[idx], errs[idx] = ApplyAllSchemaChecks(conf, resourceProvider, resources[idx])
+				if onResult != nil && errs[idx] == nil && allResults[idx].Kind != "" && allResults[idx].Name != "" {
+					onResultMutex.Lock()
+					onResult(allResults[idx])
+					onResultMutex.Unlock()
+				}
+			}
+		}()
+	}
+	for idx := range resources {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+
+	results := []Result{}
+	for idx, result := range allResults {
+		if errs[idx] != nil {
+			return results, errs[idx]
+		}
+		if result.Kind != "" && result.Name != "" {
+			results = append(results, result)
 		}
-		results = append(results, kindResults...)
 	}
+	// resources are grouped by kind in a map, so sort the results to keep them stable
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
 	return results, nil
 }
 
@@ -212,9 +320,12 @@ func ApplyAllSchemaChecks(conf *config.Configuration, resourceProvider *kube.Res
 
 func applyNonControllerSchemaChecks(conf *config.Configuration, resourceProvider *kube.ResourceProvider, resource kube.GenericResource) (Result, error) {
 	finalResult := Result{
-		Kind:      resource.Kind,
-		Name:      resource.ObjectMeta.GetName(),
-		Namespace: resource.ObjectMeta.GetNamespace(),
+		Kind:       resource.Kind,
+		Name:       resource.ObjectMeta.GetName(),
+		Namespace:  resource.ObjectMeta.GetNamespace(),
+		Owner:      conf.GetOwner(resource.ObjectMeta),
+		SourceFile: resource.SourceFile,
+		SourceLine: resource.SourceLine,
 	}
 	resultSet, err := applyTopLevelSchemaChecks(conf, resourceProvider, resource, false)
 	finalResult.Results = resultSet
@@ -223,15 +334,21 @@ func applyNonControllerSchemaChecks(conf *config.Configuration, resourceProvider
 
 func applyControllerSchemaChecks(conf *config.Configuration, resourceProvider *kube.ResourceProvider, resource kube.GenericResource) (Result, error) {
 	finalResult := Result{
-		Kind:      resource.Kind,
-		Name:      resource.ObjectMeta.GetName(),
-		Namespace: resource.ObjectMeta.GetNamespace(),
+		Kind:       resource.Kind,
+		Name:       resource.ObjectMeta.GetName(),
+		Namespace:  resource.ObjectMeta.GetNamespace(),
+		Owner:      conf.GetOwner(resource.ObjectMeta),
+		SourceFile: resource.SourceFile,
+		SourceLine: resource.SourceLine,
 	}
 	resultSet, err := applyTopLevelSchemaChecks(conf, resourceProvider, resource, true)
 	if err != nil {
 		return finalResult, err
 	}
 	finalResult.Results = resultSet
+	if scanResult := applyImageScanCheck(conf, resource, time.Now()); scanResult != nil {
+		finalResult.Results[scanResult.ID] = *scanResult
+	}
 
 	nonControllerResults, err := applyTopLevelSchemaChecks(conf, resourceProvider, resource, false)
 	if err != nil {
@@ -259,6 +376,12 @@ func applyControllerSchemaChecks(conf *config.Configuration, resourceProvider *k
 		if err != nil {
 			return finalResult, err
 		}
+		if digestResult := applyImageDigestCheck(conf, resource, &container); digestResult != nil {
+			results[digestResult.ID] = *digestResult
+		}
+		if hostPortResult := applyHostPortCheck(conf, resource, &container); hostPortResult != nil {
+			results[hostPortResult.ID] = *hostPortResult
+		}
 		cRes := ContainerResult{
 			Name:    container.Name,
 			Results: results,
@@ -270,6 +393,18 @@ func applyControllerSchemaChecks(conf *config.Configuration, resourceProvider *k
 		if err != nil {
 			return finalResult, err
 		}
+		if vpaResult := applyVPARecommendationCheck(conf, resourceProvider, resource, &container); vpaResult != nil {
+			results[vpaResult.ID] = *vpaResult
+		}
+		if profileResult := applyResourceProfileCheck(conf, resource, &container); profileResult != nil {
+			results[profileResult.ID] = *profileResult
+		}
+		if digestResult := applyImageDigestCheck(conf, resource, &container); digestResult != nil {
+			results[digestResult.ID] = *digestResult
+		}
+		if hostPortResult := applyHostPortCheck(conf, resource, &container); hostPortResult != nil {
+			results[hostPortResult.ID] = *hostPortResult
+		}
 		cRes := ContainerResult{
 			Name:    container.Name,
 			Results: results,
@@ -287,8 +422,35 @@ func applyTopLevelSchemaChecks(conf *config.Configuration, resources *kube.Resou
 	}
 	if isController {
 		test.Target = config.TargetController
+		return applySchemaChecks(conf, test)
 	}
-	return applySchemaChecks(conf, test)
+	results, err := applySchemaChecks(conf, test)
+	if err != nil {
+		return results, err
+	}
+	if labelsResult := applyRequiredLabelsCheck(conf, res); labelsResult != nil {
+		results[labelsResult.ID] = *labelsResult
+	}
+	if annotationsResult := applyRequiredAnnotationsCheck(conf, res); annotationsResult != nil {
+		results[annotationsResult.ID] = *annotationsResult
+	}
+	if namingResult := applyNamingConventionCheck(conf, res); namingResult != nil {
+		results[namingResult.ID] = *namingResult
+	}
+	if deprecatedResult := applyDeprecatedAPICheck(conf, resources, res); deprecatedResult != nil {
+		results[deprecatedResult.ID] = *deprecatedResult
+	}
+	if duplicateResult := applyDuplicateResourceCheck(conf, resources, res); duplicateResult != nil {
+		results[duplicateResult.ID] = *duplicateResult
+	}
+	wasmResults, err := applyWasmChecks(conf, res)
+	if err != nil {
+		return results, err
+	}
+	for checkID, result := range wasmResults {
+		results[checkID] = result
+	}
+	return results, nil
 }
 
 func applyPodSchemaChecks(conf *config.Configuration, resources *kube.ResourceProvider, controller kube.GenericResource) (ResultSet, error) {
@@ -315,6 +477,10 @@ func applySchemaChecks(conf *config.Configuration, test schemaTestCase) (ResultS
 	results := ResultSet{}
 	checkIDs := getSortedKeys(conf.Checks)
 	for _, checkID := range checkIDs {
+		if config.PolicyChecks[checkID] || conf.IsWasmCheck(checkID) {
+			// applied separately, e.g. by applyNamingConventionCheck or applyWasmChecks
+			continue
+		}
 		result, err := applySchemaCheck(conf, checkID, test)
 		if err != nil {
 			return results, err
@@ -336,7 +502,19 @@ func applySchemaCheck(conf *config.Configuration, checkID string, test schemaTes
 	var passes bool
 	var issues []jsonschema.ValError
 	var prefix string
-	if check.SchemaTarget != "" {
+	// checked is the object validated against the schema, used to describe passing results
+	var checked interface{}
+	if path, ok := conf.CheckPaths[checkID]; ok {
+		segments, err := config.ParseFieldPath(path)
+		if err != nil {
+			return nil, err
+		}
+		checked = config.GetFieldAtPath(test.Resource.Resource.Object, segments)
+		passes, issues, err = check.CheckObject(checked)
+		if err != nil {
+			return nil, err
+		}
+	} else if check.SchemaTarget != "" {
 		if check.SchemaTarget == config.TargetPodSpec && check.Target == config.TargetContainer {
 			podCopy := *test.Resource.PodSpec
 			podCopy.InitContainers = []corev1.Container{}
@@ -348,14 +526,17 @@ func applySchemaCheck(conf *config.Configuration, checkID string, test schemaTes
 			if prefix != "" {
 				prefix += "/containers/" + strconv.Itoa(containerIndex)
 			}
+			checked = &podCopy
 			passes, issues, err = check.CheckPodSpec(&podCopy)
 		} else {
 			return nil, fmt.Errorf("Unknown combination of target (%s) and schema target (%s)", check.Target, check.SchemaTarget)
 		}
 	} else if check.Target == config.TargetPodSpec {
+		checked = test.Resource.PodSpec
 		passes, issues, err = check.CheckPodSpec(test.Resource.PodSpec)
 		prefix = getJSONSchemaPrefix(test.Resource.Kind)
 	} else if check.Target == config.TargetPodTemplate {
+		checked = test.Resource.PodTemplate
 		passes, issues, err = check.CheckPodTemplate(test.Resource.PodTemplate)
 		prefix = getJSONSchemaPrefix(test.Resource.Kind)
 	} else if check.Target == config.TargetContainer {
@@ -366,8 +547,11 @@ func applySchemaCheck(conf *config.Configuration, checkID string, test schemaTes
 		if prefix != "" {
 			prefix += "/containers/" + strconv.Itoa(containerIndex)
 		}
-		passes, issues, err = check.CheckContainer(test.Container)
+		container := getEffectiveContainer(test.Resource.PodSpec, test.Container)
+		checked = container
+		passes, issues, err = check.CheckContainer(container)
 	} else {
+		checked = test.Resource.Resource.Object
 		passes, issues, err = check.CheckObject(test.Resource.Resource.Object)
 	}
 	if err != nil {
@@ -407,7 +591,13 @@ func applySchemaCheck(conf *config.Configuration, checkID string, test schemaTes
 		logrus.Debugf("there were no issues validating the schema for test-case %s", test.ShortString())
 
 	}
-	result := makeResult(conf, check, passes, issues)
+	result := makeResult(conf, check, test.Resource.ObjectMeta, passes, issues)
+	if passes && conf.VerboseResults {
+		result.Evidence, err = describeCheckedFields(check.GetCheckedFields(), checked)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !passes {
 		if funk.Contains(conf.Mutations, checkID) && len(check.Mutations) > 0 {
 			mutations := funk.Map(check.Mutations, func(mutation config.Mutation) config.Mutation {
@@ -421,6 +611,43 @@ func applySchemaCheck(conf *config.Configuration, checkID string, test schemaTes
 	return &result, nil
 }
 
+// maxEvidenceValueLength keeps verbose results brief when a checked field is large
+const maxEvidenceValueLength = 120
+
+// describeCheckedFields summarizes the values of the checked fields, e.g. `image="nginx:1.25"; securityContext=<unset>`
+func describeCheckedFields(fields []string, checked interface{}) (string, error) {
+	jsonBytes, err := json.Marshal(checked)
+	if err != nil {
+		return "", err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(jsonBytes, &obj); err != nil || len(fields) == 0 {
+		// the schema applies to the value as a whole, e.g. a --check-path field
+		return "value=" + truncateEvidence(string(jsonBytes)), nil
+	}
+	observed := []string{}
+	for _, field := range fields {
+		value, ok := obj[field]
+		if !ok {
+			observed = append(observed, field+"=<unset>")
+			continue
+		}
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		observed = append(observed, field+"="+truncateEvidence(string(valueBytes)))
+	}
+	return strings.Join(observed, "; "), nil
+}
+
+func truncateEvidence(value string) string {
+	if len(value) > maxEvidenceValueLength {
+		return value[:maxEvidenceValueLength] + "..."
+	}
+	return value
+}
+
 func getSortedKeys(m map[string]config.Severity) []string {
 	keys := make([]string, 0, len(m))
 	for key := range m {
@@ -16,12 +16,14 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	conf "github.com/fairwindsops/polaris/pkg/config"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var customCheckExemptions = `
@@ -44,6 +46,19 @@ exemptions:
   - foo
 `
 
+var celCheckConf = `
+checks:
+  memoryLimitAtLeastRequest: danger
+customChecks:
+  memoryLimitAtLeastRequest:
+    successMessage: Memory limit is at least the request
+    failureMessage: Memory limit should be at least the request
+    category: Efficiency
+    target: Container
+    cel: >
+      quantity(object.resources.limits.memory) >= quantity(object.resources.requests.memory)
+`
+
 var resourceConfRanges = `
 checks:
   memoryRequestsRange: danger
@@ -253,6 +268,41 @@ func TestValidateResourcesFullyValid(t *testing.T) {
 	testValidate(t, &container, &resourceConfMinimal, "foo", []ResultMessage{}, []ResultMessage{}, expectedSuccesses)
 }
 
+func TestVerboseResults(t *testing.T) {
+	memoryRequest, err := resource.ParseQuantity("400Mi")
+	assert.NoError(t, err, "Error parsing quantity")
+	container := &corev1.Container{
+		Name: "Empty",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				"memory": memoryRequest,
+			},
+		},
+	}
+	controller := getEmptyWorkload(t, "")
+
+	parsedConf, err := conf.Parse([]byte(resourceConfRanges))
+	assert.NoError(t, err, "Expected no error when parsing config")
+	results, err := applyContainerSchemaChecks(&parsedConf, nil, controller, container, false)
+	assert.NoError(t, err)
+	assert.Empty(t, results["memoryRequestsRange"].Evidence)
+
+	parsedConf.VerboseResults = true
+	results, err = applyContainerSchemaChecks(&parsedConf, nil, controller, container, false)
+	assert.NoError(t, err)
+	assert.True(t, results["memoryRequestsRange"].Success)
+	assert.Equal(t, `resources={"requests":{"memory":"400Mi"}}`, results["memoryRequestsRange"].Evidence)
+	assert.False(t, results["memoryLimitsRange"].Success)
+	assert.Empty(t, results["memoryLimitsRange"].Evidence)
+
+	evidence, err := describeCheckedFields([]string{"image", "name"}, container)
+	assert.NoError(t, err)
+	assert.Equal(t, `image=<unset>; name="Empty"`, evidence)
+	evidence, err = describeCheckedFields([]string{}, "nginx:1.25")
+	assert.NoError(t, err)
+	assert.Equal(t, `value="nginx:1.25"`, evidence)
+}
+
 func TestValidateCustomCheckExemptions(t *testing.T) {
 	container := corev1.Container{
 		Name:  "example",
@@ -275,3 +325,58 @@ func TestValidateCustomCheckExemptions(t *testing.T) {
 	}
 	testValidate(t, &container, &customCheckExemptions, "notexempt", expectedDangers, expectedWarnings, expectedSuccesses)
 }
+
+func TestValidateCELCheck(t *testing.T) {
+	container := corev1.Container{
+		Name: "example",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{"memory": resource.MustParse("512Mi")},
+			Limits:   corev1.ResourceList{"memory": resource.MustParse("1Gi")},
+		},
+	}
+	expectedSuccesses := []ResultMessage{
+		{
+			ID:       "memoryLimitAtLeastRequest",
+			Success:  true,
+			Severity: "danger",
+			Message:  "Memory limit is at least the request",
+			Category: "Efficiency",
+		},
+	}
+	testValidate(t, &container, &celCheckConf, "api", []ResultMessage{}, []ResultMessage{}, expectedSuccesses)
+
+	container.Resources.Limits["memory"] = resource.MustParse("256Mi")
+	expectedDangers := []ResultMessage{
+		{
+			ID:       "memoryLimitAtLeastRequest",
+			Success:  false,
+			Severity: "danger",
+			Message:  "Memory limit should be at least the request",
+			Category: "Efficiency",
+		},
+	}
+	testValidate(t, &container, &celCheckConf, "api", expectedDangers, []ResultMessage{}, []ResultMessage{})
+}
+
+func TestExemptUntilAnnotation(t *testing.T) {
+	now := time.Date(2024, 12, 20, 12, 0, 0, 0, time.UTC)
+	objMeta := &metaV1.ObjectMeta{Name: "web", Namespace: "default"}
+
+	objMeta.SetAnnotations(map[string]string{exemptionUntilAnnotationKey: "2024-12-31"})
+	assert.True(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", now))
+	assert.True(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)), "the exemption should apply through the last day")
+	assert.False(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	objMeta.SetAnnotations(map[string]string{"polaris.fairwinds.com/hostIPCSet-exempt-until": "2024-12-31"})
+	assert.True(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", now))
+	assert.False(t, hasExemptionAnnotationAt(objMeta, "hostPIDSet", now))
+
+	objMeta.SetAnnotations(map[string]string{"polaris.fairwinds.com/hostIPCSet-exempt-until": "2024-12-01"})
+	assert.False(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", now))
+
+	objMeta.SetAnnotations(map[string]string{exemptionUntilAnnotationKey: "next week"})
+	assert.False(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", now))
+
+	objMeta.SetAnnotations(map[string]string{exemptionAnnotationKey: "true", exemptionUntilAnnotationKey: "2024-12-01"})
+	assert.True(t, hasExemptionAnnotationAt(objMeta, "hostIPCSet", now), "exempt=true shouldn't expire")
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getEffectiveContainer returns a copy of the container whose securityContext includes the
+// pod-level settings it inherits. As in Kubernetes, only fields which exist at both levels are
+// inherited, and only when the container doesn't set them itself: runAsUser, runAsGroup,
+// runAsNonRoot, seLinuxOptions, seccompProfile, and windowsOptions.
+func getEffectiveContainer(podSpec *corev1.PodSpec, container *corev1.Container) *corev1.Container {
+	if podSpec == nil || podSpec.SecurityContext == nil {
+		return container
+	}
+	pod := podSpec.SecurityContext
+	effective := container.DeepCopy()
+	if effective.SecurityContext == nil {
+		effective.SecurityContext = &corev1.SecurityContext{}
+	}
+	sc := effective.SecurityContext
+	if sc.RunAsUser == nil && pod.RunAsUser != nil {
+		sc.RunAsUser = pod.RunAsUser
+	}
+	if sc.RunAsGroup == nil && pod.RunAsGroup != nil {
+		sc.RunAsGroup = pod.RunAsGroup
+	}
+	if sc.RunAsNonRoot == nil && pod.RunAsNonRoot != nil {
+		sc.RunAsNonRoot = pod.RunAsNonRoot
+	}
+	if sc.SELinuxOptions == nil && pod.SELinuxOptions != nil {
+		sc.SELinuxOptions = pod.SELinuxOptions
+	}
+	if sc.SeccompProfile == nil && pod.SeccompProfile != nil {
+		sc.SeccompProfile = pod.SeccompProfile
+	}
+	if sc.WindowsOptions == nil && pod.WindowsOptions != nil {
+		sc.WindowsOptions = pod.WindowsOptions
+	}
+	if *sc == (corev1.SecurityContext{}) {
+		// nothing was inherited, so don't add an empty securityContext
+		return container
+	}
+	return effective
+}
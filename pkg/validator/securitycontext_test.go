@@ -0,0 +1,102 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+func TestGetEffectiveContainer(t *testing.T) {
+	trueVar := true
+	falseVar := false
+	user := int64(1000)
+	pod := &corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   &trueVar,
+			RunAsUser:      &user,
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}
+	container := &corev1.Container{
+		Name: "api",
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot: &falseVar,
+		},
+	}
+	effective := getEffectiveContainer(pod, container)
+	assert.False(t, *effective.SecurityContext.RunAsNonRoot, "the container's own setting should win")
+	assert.Equal(t, int64(1000), *effective.SecurityContext.RunAsUser)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, effective.SecurityContext.SeccompProfile.Type)
+	assert.Nil(t, container.SecurityContext.RunAsUser, "the original container shouldn't be modified")
+
+	bare := &corev1.Container{Name: "api"}
+	assert.Same(t, bare, getEffectiveContainer(&corev1.PodSpec{}, bare))
+	assert.Same(t, bare, getEffectiveContainer(&corev1.PodSpec{SecurityContext: &corev1.PodSecurityContext{FSGroup: &user}}, bare),
+		"pod-only fields like fsGroup aren't inherited")
+}
+
+func TestValidateInheritedSecurityContext(t *testing.T) {
+	c, err := conf.Parse([]byte(`
+checks:
+  containerRunAsNonRoot: danger
+  notReadOnlyRootFilesystem: warning
+customChecks:
+  containerRunAsNonRoot:
+    successMessage: Container runs as non-root
+    failureMessage: Container should run as non-root
+    category: Security
+    target: Container
+    schema:
+      '$schema': http://json-schema.org/draft-07/schema
+      type: object
+      required:
+      - securityContext
+      properties:
+        securityContext:
+          type: object
+          required:
+          - runAsNonRoot
+          properties:
+            runAsNonRoot:
+              const: true
+`))
+	assert.NoError(t, err)
+	trueVar := true
+	falseVar := false
+	pod := corev1.Pod{Spec: corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot: &trueVar,
+		},
+		Containers: []corev1.Container{{Name: "api"}},
+	}}
+	workload, err := kube.NewGenericResourceFromPod(pod, nil)
+	assert.NoError(t, err)
+
+	results, err := applyContainerSchemaChecks(&c, nil, workload, &pod.Spec.Containers[0], false)
+	assert.NoError(t, err)
+	assert.True(t, results["containerRunAsNonRoot"].Success, "runAsNonRoot should be inherited from the pod")
+	assert.False(t, results["notReadOnlyRootFilesystem"].Success, "readOnlyRootFilesystem can't be set on the pod")
+
+	override := corev1.Container{Name: "api", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: &falseVar}}
+	results, err = applyContainerSchemaChecks(&c, nil, workload, &override, false)
+	assert.NoError(t, err)
+	assert.False(t, results["containerRunAsNonRoot"].Success, "the container should be able to override the pod")
+}
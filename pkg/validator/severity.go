@@ -0,0 +1,34 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "github.com/fairwindsops/polaris/pkg/config"
+
+// FilterBySeverity removes checks below the minimum severity, whether they pass or fail,
+// and recalculates the score so the summary matches the checks that are left
+func (res AuditData) FilterBySeverity(minimum config.Severity) AuditData {
+	filter := func(result Result, container string, rs ResultSet) ResultSet {
+		filtered := ResultSet{}
+		for id, msg := range rs {
+			if msg.Severity.IsAtLeast(minimum) {
+				filtered[id] = msg
+			}
+		}
+		return filtered
+	}
+	resCopy := res.mapResultSets(filter)
+	resCopy.Score = resCopy.GetSummary().GetScore()
+	return resCopy
+}
@@ -0,0 +1,38 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterBySeverity(t *testing.T) {
+	audit := getOutputTestAudit()
+
+	filtered := audit.FilterBySeverity(config.SeverityWarning)
+	assert.Equal(t, audit.GetSummary(), filtered.GetSummary())
+
+	filtered = audit.FilterBySeverity(config.SeverityDanger)
+	assert.Equal(t, "payments/Deployment/api: FAIL(2 dangers)\n", filtered.RemoveSuccessfulResults().GetStatusOutput())
+	summary := filtered.GetSummary()
+	assert.Equal(t, uint(0), summary.Warnings)
+	assert.Equal(t, uint(2), summary.Dangers)
+	assert.Equal(t, summary.GetScore(), filtered.Score)
+
+	assert.Equal(t, "payments/Deployment/api: FAIL(2 dangers, 2 warnings)\n", audit.RemoveSuccessfulResults().GetStatusOutput())
+}
@@ -140,7 +140,7 @@ func (c Result) GetSummaryByCategory() CountSummaryByCategory {
 func (a AuditData) GetSummary() CountSummary {
 	summary := CountSummary{}
 	for _, res := range a.Results {
-		summary.AddSummary(res.GetSummary())
+		summary.AddSummary(res.getSummary(a.ScoreGranularity))
 	}
 	return summary
 }
@@ -156,6 +156,17 @@ func (a AuditData) GetSummaryByCategory() CountSummaryByCategory {
 	return summaries
 }
 
+// GetSummaryByNamespace summarizes the results in each namespace. Cluster-scoped resources are summarized under an empty namespace.
+func (a AuditData) GetSummaryByNamespace() map[string]CountSummary {
+	summaries := map[string]CountSummary{}
+	for _, result := range a.Results {
+		summary := summaries[result.Namespace]
+		summary.AddSummary(result.getSummary(a.ScoreGranularity))
+		summaries[result.Namespace] = summary
+	}
+	return summaries
+}
+
 // GetResultsByNamespace organizes results by namespace
 func (a AuditData) GetResultsByNamespace() map[string][]*Result {
 	allResults := map[string][]*Result{}
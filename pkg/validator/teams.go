@@ -0,0 +1,76 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnownedTeam is the team name used for resources without an owner
+const UnownedTeam = "unowned"
+
+// TeamSummary provides the score and counts for the resources owned by a team
+type TeamSummary struct {
+	Team      string
+	Score     uint
+	Resources int
+	CountSummary
+}
+
+// GetTeamSummaries aggregates results by owning team. Every team given is included,
+// so teams without any findings still appear with a perfect score.
+func (res AuditData) GetTeamSummaries(teams []string) []TeamSummary {
+	byTeam := map[string]*TeamSummary{}
+	for _, team := range teams {
+		byTeam[team] = &TeamSummary{Team: team}
+	}
+	for _, result := range res.Results {
+		team := result.Owner
+		if team == "" {
+			team = UnownedTeam
+		}
+		if _, ok := byTeam[team]; !ok {
+			byTeam[team] = &TeamSummary{Team: team}
+		}
+		byTeam[team].Resources++
+		byTeam[team].AddSummary(result.getSummary(res.ScoreGranularity))
+	}
+	summaries := []TeamSummary{}
+	for _, summary := range byTeam {
+		summary.Score = summary.GetScore()
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Team < summaries[j].Team
+	})
+	return summaries
+}
+
+// GetTeamSummaryPrettyOutput returns a human-readable table of team summaries
+func GetTeamSummaryPrettyOutput(summaries []TeamSummary) string {
+	width := len("TEAM")
+	for _, summary := range summaries {
+		if len(summary.Team) > width {
+			width = len(summary.Team)
+		}
+	}
+	format := fmt.Sprintf("%%-%ds  %%5v  %%9v  %%7v  %%8v\n", width)
+	str := fmt.Sprintf(format, "TEAM", "SCORE", "RESOURCES", "DANGERS", "WARNINGS")
+	for _, summary := range summaries {
+		str += fmt.Sprintf(format, summary.Team, summary.Score, summary.Resources, summary.Dangers, summary.Warnings)
+	}
+	return str
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTeamSummaries(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.Results[0].Owner = "payments"
+
+	summaries := audit.GetTeamSummaries([]string{"payments", "platform"})
+	assert.Len(t, summaries, 3)
+
+	assert.Equal(t, "payments", summaries[0].Team)
+	assert.Equal(t, 1, summaries[0].Resources)
+	assert.Equal(t, audit.Results[0].GetSummary().GetScore(), summaries[0].Score)
+
+	assert.Equal(t, "platform", summaries[1].Team)
+	assert.Equal(t, 0, summaries[1].Resources)
+	assert.Equal(t, uint(100), summaries[1].Score)
+	assert.Equal(t, uint(0), summaries[1].Dangers)
+
+	assert.Equal(t, UnownedTeam, summaries[2].Team)
+	assert.Equal(t, 1, summaries[2].Resources)
+
+	total := audit.GetSummary()
+	assert.Equal(t, total.Dangers, summaries[0].Dangers+summaries[2].Dangers)
+	assert.Equal(t, total.Warnings, summaries[0].Warnings+summaries[2].Warnings)
+
+	pretty := GetTeamSummaryPrettyOutput(summaries)
+	lines := strings.Split(strings.TrimSpace(pretty), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "TEAM"))
+	assert.True(t, strings.HasPrefix(lines[2], "platform"))
+	assert.Contains(t, lines[2], "100")
+}
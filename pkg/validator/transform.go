@@ -0,0 +1,48 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// TransformWithExec pipes the audit as JSON through an external command and
+// parses the command's output as the transformed audit
+func TransformWithExec(auditData AuditData, command string) (AuditData, error) {
+	input, err := json.Marshal(auditData)
+	if err != nil {
+		return auditData, fmt.Errorf("marshalling audit for transform: %w", err)
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return auditData, fmt.Errorf("running transform %q: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	transformed := AuditData{}
+	if err := json.Unmarshal(stdout.Bytes(), &transformed); err != nil {
+		return auditData, fmt.Errorf("transform %q did not return valid audit JSON: %w", command, err)
+	}
+	if transformed.PolarisOutputVersion == "" {
+		return auditData, fmt.Errorf("transform %q did not return valid audit JSON: missing PolarisOutputVersion", command)
+	}
+	return transformed, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformWithExec(t *testing.T) {
+	audit := getOutputTestAudit()
+	audit.PolarisOutputVersion = PolarisOutputVersion
+	audit.DisplayName = "before"
+
+	transformed, err := TransformWithExec(audit, "cat")
+	assert.NoError(t, err)
+	assert.Equal(t, audit.Results[0].Name, transformed.Results[0].Name)
+
+	transformed, err = TransformWithExec(audit, `sed 's/"DisplayName":"before"/"DisplayName":"after"/'`)
+	assert.NoError(t, err)
+	assert.Equal(t, "after", transformed.DisplayName)
+
+	_, err = TransformWithExec(audit, "echo not json")
+	assert.Error(t, err)
+
+	_, err = TransformWithExec(audit, "echo '{}'")
+	assert.Error(t, err)
+
+	_, err = TransformWithExec(audit, "exit 1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+const vpaRecommendationCheckID = "requestsDeviateFromVPA"
+
+// getVPARecommendations finds the VPA target recommendations for each container of a controller
+func getVPARecommendations(resourceProvider *kube.ResourceProvider, controller kube.GenericResource) map[string]map[string]interface{} {
+	if resourceProvider == nil {
+		return nil
+	}
+	for _, vpa := range resourceProvider.Resources[kube.VPAGroupKind] {
+		if vpa.ObjectMeta.GetNamespace() != controller.ObjectMeta.GetNamespace() {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(vpa.Resource.Object, "spec", "targetRef", "kind")
+		name, _, _ := unstructured.NestedString(vpa.Resource.Object, "spec", "targetRef", "name")
+		if kind != controller.Kind || name != controller.ObjectMeta.GetName() {
+			continue
+		}
+		containerRecommendations, _, _ := unstructured.NestedSlice(vpa.Resource.Object, "status", "recommendation", "containerRecommendations")
+		recommendations := map[string]map[string]interface{}{}
+		for _, rec := range containerRecommendations {
+			recMap, ok := rec.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			containerName, _, _ := unstructured.NestedString(recMap, "containerName")
+			target, _, _ := unstructured.NestedMap(recMap, "target")
+			recommendations[containerName] = target
+		}
+		return recommendations
+	}
+	return nil
+}
+
+// applyVPARecommendationCheck compares a container's requests against the VPA recommendation, if any
+func applyVPARecommendationCheck(conf *config.Configuration, resourceProvider *kube.ResourceProvider, controller kube.GenericResource, container *corev1.Container) *ResultMessage {
+	severity, ok := getPolicyCheckSeverity(conf, vpaRecommendationCheckID, controller, container.Name)
+	if !ok {
+		return nil
+	}
+	target, ok := getVPARecommendations(resourceProvider, controller)[container.Name]
+	if !ok {
+		return nil
+	}
+	maxDeviation := conf.VPARecommendations.GetMaxDeviationPercent()
+	deviations := []string{}
+	checked := false
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, ok := container.Resources.Requests[resourceName]
+		if !ok {
+			continue
+		}
+		recommendedStr, ok := target[string(resourceName)].(string)
+		if !ok {
+			continue
+		}
+		recommended, err := resource.ParseQuantity(recommendedStr)
+		if err != nil || recommended.IsZero() {
+			continue
+		}
+		checked = true
+		deviation := math.Abs(request.AsApproximateFloat64()-recommended.AsApproximateFloat64()) / recommended.AsApproximateFloat64() * 100
+		if deviation > float64(maxDeviation) {
+			deviations = append(deviations, fmt.Sprintf("%s request %s should be close to the recommended %s", resourceName, request.String(), recommended.String()))
+		}
+	}
+	if !checked {
+		return nil
+	}
+	result := ResultMessage{
+		ID:       vpaRecommendationCheckID,
+		Severity: severity,
+		Category: "Efficiency",
+		Priority: getResultPriority(conf, vpaRecommendationCheckID),
+		Success:  len(deviations) == 0,
+	}
+	if result.Success {
+		result.Message = fmt.Sprintf("Requests are within %d%% of the VPA recommendation", maxDeviation)
+	} else {
+		result.Message = fmt.Sprintf("Requests deviate more than %d%% from the VPA recommendation: %s", maxDeviation, strings.Join(deviations, ", "))
+	}
+	return &result
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+var vpaTestYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:v1
+        resources:
+          requests:
+            cpu: 100m
+            memory: 128Mi
+      - name: sidecar
+        image: sidecar:v1
+        resources:
+          requests:
+            cpu: 50m
+---
+apiVersion: autoscaling.k8s.io/v1
+kind: VerticalPodAutoscaler
+metadata:
+  name: api
+  namespace: web
+  creationTimestamp: "2022-01-01T00:00:00Z"
+spec:
+  targetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: api
+status:
+  recommendation:
+    containerRecommendations:
+    - containerName: api
+      target:
+        cpu: 500m
+        memory: 140Mi
+    - containerName: sidecar
+      target:
+        cpu: 60m
+`
+
+func TestVPARecommendations(t *testing.T) {
+	c := conf.Configuration{
+		Checks: map[string]conf.Severity{vpaRecommendationCheckID: conf.SeverityWarning},
+	}
+	provider := kube.CreateResourceProviderFromYaml(vpaTestYaml)
+	deployment := provider.Resources["apps/Deployment"][0]
+
+	result, err := applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	apiResult := result.PodResult.ContainerResults[0].Results[vpaRecommendationCheckID]
+	assert.False(t, apiResult.Success)
+	assert.Equal(t, conf.SeverityWarning, apiResult.Severity)
+	assert.Equal(t, "Requests deviate more than 50% from the VPA recommendation: cpu request 100m should be close to the recommended 500m", apiResult.Message)
+	sidecarResult := result.PodResult.ContainerResults[1].Results[vpaRecommendationCheckID]
+	assert.True(t, sidecarResult.Success)
+
+	c.VPARecommendations.MaxDeviationPercent = 90
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.True(t, result.PodResult.ContainerResults[0].Results[vpaRecommendationCheckID].Success)
+
+	c.Checks[vpaRecommendationCheckID] = conf.SeverityDanger
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.Equal(t, conf.SeverityDanger, result.PodResult.ContainerResults[0].Results[vpaRecommendationCheckID].Severity)
+
+	delete(c.Checks, vpaRecommendationCheckID)
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	_, ok := result.PodResult.ContainerResults[0].Results[vpaRecommendationCheckID]
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+// wasmCheckCategory is the category of results from WebAssembly checks
+const wasmCheckCategory = "Custom"
+
+// applyWasmChecks runs the WebAssembly checks loaded with --wasm-checks against a resource
+func applyWasmChecks(conf *config.Configuration, resource kube.GenericResource) (ResultSet, error) {
+	results := ResultSet{}
+	if len(conf.WasmModules) == 0 {
+		return results, nil
+	}
+	resourceJSON, err := json.Marshal(resource.Resource.Object)
+	if err != nil {
+		return results, err
+	}
+	for _, module := range conf.WasmModules {
+		checkID := module.ID()
+		severity, ok := getPolicyCheckSeverity(conf, checkID, resource, "")
+		if !ok {
+			continue
+		}
+		output, err := module.Run(context.Background(), resourceJSON)
+		if err != nil {
+			// a broken or slow module fails its own check, rather than the whole audit
+			logrus.Warnf("%v for %s %s", err, resource.Kind, resource.ObjectMeta.GetName())
+			output = config.WasmResult{Success: false, Message: fmt.Sprintf("WebAssembly check failed to run: %v", err)}
+		}
+		results[checkID] = ResultMessage{
+			ID:       checkID,
+			Message:  output.Message,
+			Success:  output.Success,
+			Severity: severity,
+			Category: wasmCheckCategory,
+			Priority: getResultPriority(conf, checkID),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+// testWasmModule stands in for a WebAssembly check, failing resources without a team label
+type testWasmModule struct {
+	id  string
+	err error
+}
+
+func (m testWasmModule) ID() string {
+	return m.id
+}
+
+func (m testWasmModule) Run(ctx context.Context, resource []byte) (conf.WasmResult, error) {
+	if m.err != nil {
+		return conf.WasmResult{}, m.err
+	}
+	obj := struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(resource, &obj); err != nil {
+		return conf.WasmResult{}, err
+	}
+	if _, ok := obj.Metadata.Labels["team"]; ok {
+		return conf.WasmResult{Success: true, Message: "Team label is set"}, nil
+	}
+	return conf.WasmResult{Message: "Team label is missing"}, nil
+}
+
+func TestApplyWasmChecks(t *testing.T) {
+	provider := kube.CreateResourceProviderFromYaml(labelsTestYaml)
+	deployment := provider.Resources["apps/Deployment"][0]
+
+	c := conf.Configuration{Checks: map[string]conf.Severity{}}
+	assert.NoError(t, c.AddWasmModule(testWasmModule{id: "teamLabel"}))
+	result, err := applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	teamLabel := result.Results["teamLabel"]
+	assert.True(t, teamLabel.Success)
+	assert.Equal(t, conf.SeverityWarning, teamLabel.Severity, "severity should default to warning")
+	assert.Equal(t, "Custom", teamLabel.Category)
+
+	c.Checks["teamLabel"] = conf.SeverityIgnore
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.Results, "teamLabel")
+
+	c.Checks = map[string]conf.Severity{"broken": conf.SeverityWarning, "teamLabel": conf.SeverityWarning}
+	c.WasmModules = []conf.WasmModule{
+		testWasmModule{id: "broken", err: errors.New("WebAssembly check broken timed out after 1s")},
+		testWasmModule{id: "teamLabel"},
+	}
+	result, err = applyControllerSchemaChecks(&c, provider, deployment)
+	assert.NoError(t, err, "a failing module shouldn't abort the audit")
+	broken := result.Results["broken"]
+	assert.False(t, broken.Success)
+	assert.Equal(t, "WebAssembly check failed to run: WebAssembly check broken timed out after 1s", broken.Message)
+	assert.True(t, result.Results["teamLabel"].Success, "other checks should still run")
+}
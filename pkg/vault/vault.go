@@ -0,0 +1,110 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vault reads secrets from HashiCorp Vault using its HTTP API, so that
+// Vault support doesn't add a client library dependency.
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ReadSecretFromEnv reads a secret with client using the standard VAULT_ADDR and VAULT_TOKEN environment variables
+func ReadSecretFromEnv(client *http.Client, path string) (map[string]interface{}, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, errors.New("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+	return ReadSecret(client, addr, token, path)
+}
+
+// ReadSecret reads the secret at path with client, e.g. secret/data/myapp for a KV version 2 engine
+// mounted at secret. Error messages never include the secret's data.
+func ReadSecret(client *http.Client, addr, token, path string) (map[string]interface{}, error) {
+	secretURL := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequest("GET", secretURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for Vault secret %s: %w", path, err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading Vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading Vault secret %s: expected 200, received %d", path, resp.StatusCode)
+	}
+	secret := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("decoding Vault secret %s failed", path)
+	}
+	// KV version 2 nests the secret under data, next to its metadata
+	if data, ok := secret.Data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := secret.Data["metadata"]; hasMetadata {
+			return data, nil
+		}
+	}
+	if secret.Data == nil {
+		return nil, fmt.Errorf("Vault secret %s has no data", path)
+	}
+	return secret.Data, nil
+}
+
+// GetSecretStrings returns every string in the secret, longest first, e.g. to redact them from output
+func GetSecretStrings(secret interface{}) []string {
+	values := []string{}
+	switch value := secret.(type) {
+	case string:
+		if value != "" {
+			values = append(values, value)
+		}
+	case map[string]interface{}:
+		for _, nested := range value {
+			values = append(values, GetSecretStrings(nested)...)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			values = append(values, GetSecretStrings(nested)...)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	return values
+}
+
+// MinRedactedLength is the length of the shortest value Redact replaces. Shorter values, like "80" or
+// "true", are too common to be secrets, and replacing them would mangle unrelated text.
+const MinRedactedLength = 6
+
+// Redact replaces each secret value in str, skipping values shorter than MinRedactedLength
+func Redact(str string, secrets []string) string {
+	for _, secret := range secrets {
+		if len(secret) < MinRedactedLength {
+			continue
+		}
+		str = strings.ReplaceAll(str, secret, "<redacted>")
+	}
+	return str
+}
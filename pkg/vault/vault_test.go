@@ -0,0 +1,84 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			w.Write([]byte(`{"data": {"data": {"db": {"password": "hunter22"}}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/app":
+			w.Write([]byte(`{"data": {"apiKey": "abc123"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	secret, err := ReadSecret(server.Client(), server.URL+"/", "token", "secret/data/app")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"db": map[string]interface{}{"password": "hunter22"}}, secret)
+
+	secret, err = ReadSecret(server.Client(), server.URL, "token", "/kv/app")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"apiKey": "abc123"}, secret)
+
+	_, err = ReadSecret(server.Client(), server.URL, "wrong", "kv/app")
+	assert.EqualError(t, err, "reading Vault secret kv/app: expected 200, received 403")
+	_, err = ReadSecret(server.Client(), server.URL, "token", "kv/missing")
+	assert.EqualError(t, err, "reading Vault secret kv/missing: expected 200, received 404")
+}
+
+func TestReadSecretTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	_, err := ReadSecret(client, server.URL, "token", "kv/app")
+	assert.Error(t, err, "an unresponsive Vault should time out")
+}
+
+func TestRedact(t *testing.T) {
+	secrets := GetSecretStrings(map[string]interface{}{
+		"password": "hunter2",
+		"nested":   []interface{}{"hunter22", 3},
+	})
+	assert.Equal(t, []string{"hunter22", "hunter2"}, secrets)
+	assert.Equal(t, "password=<redacted> other=<redacted>", Redact("password=hunter22 other=hunter2", secrets))
+}
+
+func TestRedactSkipsShortValues(t *testing.T) {
+	secrets := GetSecretStrings(map[string]interface{}{
+		"password": "hunter2",
+		"enabled":  "true",
+		"port":     "80",
+	})
+	assert.Equal(t, "port 80 is enabled: true, password <redacted>", Redact("port 80 is enabled: true, password hunter2", secrets))
+}
@@ -0,0 +1,13 @@
+;; Source of alwaysFails.wasm, which fails every resource
+(module
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 1024))
+  (func (export "polaris_alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "polaris_check") (param $ptr i32) (param $len i32) (result i64)
+    ;; the output is at address 0
+    (i64.const 67))
+  (data (i32.const 0) "{\"success\":false,\"message\":\"Resource failed the WebAssembly check\"}"))
@@ -0,0 +1,3 @@
+;; Source of missingExports.wasm, which doesn't export the check functions
+(module
+  (memory (export "memory") 1))
@@ -0,0 +1,214 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wasm runs custom checks implemented as WebAssembly modules.
+//
+// A module is run in a sandbox without access to the filesystem, network, or environment,
+// and must export:
+//
+//	memory
+//	polaris_alloc(size i32) i32               allocates size bytes for the input, returning their address
+//	polaris_check(ptr i32, len i32) i64       checks the resource JSON at ptr, returning the address of the
+//	                                          output in the upper 32 bits and its length in the lower 32 bits
+//
+// The output is JSON, e.g. {"success": false, "message": "Deployment should set a team label"}
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+const (
+	allocFunction = "polaris_alloc"
+	checkFunction = "polaris_check"
+	// memoryLimitPages caps each module's memory at 64MiB
+	memoryLimitPages = 1024
+)
+
+// Check is a custom check loaded from a WebAssembly module, named after its file
+type Check struct {
+	id      string
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	timeout time.Duration
+}
+
+var _ config.WasmModule = &Check{}
+
+// Runtime holds the checks loaded from a directory, which share a WebAssembly runtime
+type Runtime struct {
+	// Checks are sorted by ID
+	Checks  []*Check
+	runtime wazero.Runtime
+}
+
+// Close releases the runtime and its compiled modules, after which the checks can't be run
+func (r *Runtime) Close(ctx context.Context) error {
+	return r.runtime.Close(ctx)
+}
+
+// LoadChecks compiles every .wasm module in a directory. Each run of a check is limited to the timeout.
+// The returned runtime should be closed once the checks aren't needed anymore.
+func LoadChecks(ctx context.Context, dir string, timeout time.Duration) (*Runtime, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(memoryLimitPages)
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	checks, err := compileChecks(ctx, runtime, dir, entries, timeout)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	return &Runtime{Checks: checks, runtime: runtime}, nil
+}
+
+func compileChecks(ctx context.Context, runtime wazero.Runtime, dir string, entries []os.DirEntry, timeout time.Duration) ([]*Check, error) {
+	// modules built for WASI, e.g. with TinyGo or Rust, need its imports, but aren't given any files or environment
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return nil, err
+	}
+	checks := []*Check{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".wasm" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		module, err := runtime.CompileModule(ctx, contents)
+		if err != nil {
+			return nil, fmt.Errorf("compiling %s: %w", path, err)
+		}
+		if err := validateExports(module); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		checks = append(checks, &Check{
+			id:      strings.TrimSuffix(entry.Name(), ".wasm"),
+			runtime: runtime,
+			module:  module,
+			timeout: timeout,
+		})
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].id < checks[j].id
+	})
+	return checks, nil
+}
+
+func validateExports(module wazero.CompiledModule) error {
+	if _, ok := module.ExportedMemories()["memory"]; !ok {
+		return errors.New("module must export its memory as memory")
+	}
+	functions := module.ExportedFunctions()
+	expected := []struct {
+		name    string
+		params  []api.ValueType
+		results []api.ValueType
+	}{
+		{allocFunction, []api.ValueType{api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}},
+		{checkFunction, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64}},
+	}
+	for _, signature := range expected {
+		function, ok := functions[signature.name]
+		if !ok {
+			return fmt.Errorf("module must export the function %s", signature.name)
+		}
+		if !equalTypes(function.ParamTypes(), signature.params) || !equalTypes(function.ResultTypes(), signature.results) {
+			return fmt.Errorf("function %s has the wrong signature", signature.name)
+		}
+	}
+	return nil
+}
+
+func equalTypes(a, b []api.ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// ID returns the check ID, which is the module's file name without the .wasm extension
+func (c *Check) ID() string {
+	return c.id
+}
+
+// Run checks a resource in a new instance of the module, so no state is kept between resources
+func (c *Check) Run(ctx context.Context, resource []byte) (config.WasmResult, error) {
+	result := config.WasmResult{}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	// an empty name allows several instances of the module at once
+	instance, err := c.runtime.InstantiateModule(ctx, c.module, wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return result, c.runError(ctx, err)
+	}
+	defer instance.Close(ctx)
+
+	allocated, err := instance.ExportedFunction(allocFunction).Call(ctx, uint64(len(resource)))
+	if err != nil {
+		return result, c.runError(ctx, err)
+	}
+	inputPtr := uint32(allocated[0])
+	if !instance.Memory().Write(inputPtr, resource) {
+		return result, fmt.Errorf("WebAssembly check %s allocated memory out of range", c.id)
+	}
+	returned, err := instance.ExportedFunction(checkFunction).Call(ctx, uint64(inputPtr), uint64(len(resource)))
+	if err != nil {
+		return result, c.runError(ctx, err)
+	}
+	outputPtr, outputLen := uint32(returned[0]>>32), uint32(returned[0])
+	output, ok := instance.Memory().Read(outputPtr, outputLen)
+	if !ok {
+		return result, fmt.Errorf("WebAssembly check %s returned output out of range", c.id)
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return result, fmt.Errorf("WebAssembly check %s returned invalid output: %w", c.id, err)
+	}
+	return result, nil
+}
+
+func (c *Check) runError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("WebAssembly check %s timed out after %s", c.id, c.timeout)
+	}
+	return fmt.Errorf("running WebAssembly check %s: %w", c.id, err)
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 FairwindsOps, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+func TestLoadChecks(t *testing.T) {
+	ctx := context.Background()
+	runtime, err := LoadChecks(ctx, "testdata/checks", time.Second)
+	assert.NoError(t, err)
+	defer runtime.Close(ctx)
+	checks := runtime.Checks
+	assert.Len(t, checks, 2)
+	assert.Equal(t, "alwaysFails", checks[0].ID())
+	assert.Equal(t, "echo", checks[1].ID())
+
+	result, err := checks[0].Run(ctx, []byte(`{"kind":"Deployment"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, config.WasmResult{Success: false, Message: "Resource failed the WebAssembly check"}, result)
+
+	result, err = checks[1].Run(ctx, []byte(`{"success":true,"message":"Read the input"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, config.WasmResult{Success: true, Message: "Read the input"}, result)
+
+	_, err = checks[1].Run(ctx, []byte(`not json`))
+	assert.ErrorContains(t, err, "WebAssembly check echo returned invalid output")
+
+	_, err = LoadChecks(ctx, "testdata/invalid", time.Second)
+	assert.EqualError(t, err, "testdata/invalid/missingExports.wasm: module must export the function polaris_alloc")
+
+	_, err = LoadChecks(ctx, "testdata/missing", time.Second)
+	assert.Error(t, err)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	ctx := context.Background()
+	runtime, err := LoadChecks(ctx, "testdata/timeout", 50*time.Millisecond)
+	assert.NoError(t, err)
+	defer runtime.Close(ctx)
+	checks := runtime.Checks
+	assert.Len(t, checks, 1)
+
+	_, err = checks[0].Run(ctx, []byte(`{"kind":"Deployment"}`))
+	assert.EqualError(t, err, "WebAssembly check infiniteLoop timed out after 50ms")
+}
+
+func TestRuntimeClose(t *testing.T) {
+	ctx := context.Background()
+	runtime, err := LoadChecks(ctx, "testdata/checks", time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, runtime.Close(ctx))
+
+	_, err = runtime.Checks[0].Run(ctx, []byte(`{"kind":"Deployment"}`))
+	assert.Error(t, err)
+}